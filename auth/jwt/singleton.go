@@ -5,6 +5,7 @@ import (
 	"embed"
 	"github.com/viant/afs"
 	"github.com/viant/datly/auth/cognito"
+	"github.com/viant/datly/auth/oidc"
 	"github.com/viant/datly/gateway"
 	"github.com/viant/datly/gateway/registry"
 	"github.com/viant/datly/view"
@@ -14,6 +15,7 @@ import (
 
 var cognitoService *cognito.Service
 var jwtVerifier *verifier.Service
+var oidcService *oidc.Service
 var authServiceInit sync.Once
 
 func Init(config *gateway.Config, embedFs *embed.FS) (gateway.Authorizer, error) {
@@ -36,6 +38,12 @@ func Init(config *gateway.Config, embedFs *embed.FS) (gateway.Authorizer, error)
 				registry.Codecs.Register(view.NewVisitor(registry.CodecKeyJwtClaim, New(jwtVerifier.VerifyClaims)))
 			}
 		}
+		if err == nil && config.OIDC != nil {
+			oidcService = oidc.New(config.OIDC)
+			if err = oidcService.Init(context.Background()); err == nil {
+				registry.Codecs.Register(view.NewVisitor(registry.CodecKeyJwtClaim, New(oidcService.VerifyClaims)))
+			}
+		}
 	})
 
 	if err != nil {
@@ -0,0 +1,110 @@
+package mtls
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+//ClientCertHeader represents request header with verified client certificate, set by the TLS server only
+const ClientCertHeader = "Datly-Client-Cert"
+
+type certificateKey struct{}
+
+//Claims represents client certificate subject and SAN fields
+type Claims struct {
+	CommonName         string
+	Organization       []string `json:",omitempty"`
+	OrganizationalUnit []string `json:",omitempty"`
+	Country            []string `json:",omitempty"`
+	SerialNumber       string
+	Issuer             string
+	DNSNames           []string `json:",omitempty"`
+	EmailAddresses     []string `json:",omitempty"`
+	URIs               []string `json:",omitempty"`
+	IPAddresses        []string `json:",omitempty"`
+	NotAfter           time.Time
+	Fingerprint        string //SHA-256 of the DER encoded certificate
+}
+
+//NewClaims creates claims from the certificate
+func NewClaims(cert *x509.Certificate) *Claims {
+	fingerprint := sha256.Sum256(cert.Raw)
+	result := &Claims{
+		CommonName:         cert.Subject.CommonName,
+		Organization:       cert.Subject.Organization,
+		OrganizationalUnit: cert.Subject.OrganizationalUnit,
+		Country:            cert.Subject.Country,
+		SerialNumber:       cert.SerialNumber.String(),
+		Issuer:             cert.Issuer.CommonName,
+		DNSNames:           cert.DNSNames,
+		EmailAddresses:     cert.EmailAddresses,
+		NotAfter:           cert.NotAfter,
+		Fingerprint:        hex.EncodeToString(fingerprint[:]),
+	}
+
+	for _, URI := range cert.URIs {
+		result.URIs = append(result.URIs, URI.String())
+	}
+
+	for _, IP := range cert.IPAddresses {
+		result.IPAddresses = append(result.IPAddresses, IP.String())
+	}
+
+	return result
+}
+
+//ParseClaims parses URL escaped PEM client certificate header value
+func ParseClaims(raw string) (*Claims, error) {
+	if raw == "" {
+		return nil, fmt.Errorf("client certificate was empty")
+	}
+
+	unescaped, err := url.QueryUnescape(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode([]byte(unescaped))
+	if block == nil {
+		return nil, fmt.Errorf("invalid client certificate PEM")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClaims(cert), nil
+}
+
+//EncodeCertificate encodes certificate as URL escaped PEM header value
+func EncodeCertificate(cert *x509.Certificate) string {
+	return url.QueryEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+}
+
+//Handler forwards verified client certificate to the next handler, any client supplied ClientCertHeader is removed
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		request.Header.Del(ClientCertHeader)
+		if request.TLS != nil && len(request.TLS.VerifiedChains) > 0 && len(request.TLS.VerifiedChains[0]) > 0 {
+			encoded := EncodeCertificate(request.TLS.VerifiedChains[0][0])
+			request.Header.Set(ClientCertHeader, encoded)
+			request = request.WithContext(context.WithValue(request.Context(), certificateKey{}, encoded))
+		}
+
+		next.ServeHTTP(writer, request)
+	})
+}
+
+//VerifiedCertificate returns client certificate verified by the Handler, ClientCertHeader is not trusted outside of the Handler
+func VerifiedCertificate(ctx context.Context) string {
+	value, _ := ctx.Value(certificateKey{}).(string)
+	return value
+}
@@ -0,0 +1,197 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/viant/afs"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//ClientAuthRequire requires verified client certificate
+	ClientAuthRequire = "require"
+	//ClientAuthOptional verifies client certificate if given
+	ClientAuthOptional = "optional"
+	//ClientAuthNone does not request client certificate
+	ClientAuthNone = "none"
+
+	defaultReloadIntervalMs = 60000
+)
+
+type (
+	//Config represents TLS server config, certificates are loaded from afs locations
+	Config struct {
+		CertURL          string
+		KeyURL           string
+		ClientCAURL      string `json:",omitempty"` //CA bundle verifying client certificates
+		ClientAuth       string `json:",omitempty"` //require (default with ClientCAURL), optional or none (default without ClientCAURL)
+		ReloadIntervalMs int    `json:",omitempty"`
+	}
+
+	//Service represents TLS server certificates with hot reloading
+	Service struct {
+		config   *Config
+		fs       afs.Service
+		mux      sync.RWMutex
+		cert     *tls.Certificate
+		pool     *x509.CertPool
+		modified map[string]time.Time
+		checked  time.Time
+		reload   sync.Mutex
+	}
+)
+
+//Init initializes Config
+func (c *Config) Init() error {
+	if c.CertURL == "" || c.KeyURL == "" {
+		return fmt.Errorf("TLS CertURL and KeyURL are required")
+	}
+
+	c.ClientAuth = strings.ToLower(c.ClientAuth)
+	switch c.ClientAuth {
+	case "":
+		c.ClientAuth = ClientAuthRequire
+		if c.ClientCAURL == "" {
+			c.ClientAuth = ClientAuthNone
+		}
+	case ClientAuthRequire, ClientAuthOptional:
+		if c.ClientCAURL == "" {
+			return fmt.Errorf("TLS client auth %v requires ClientCAURL", c.ClientAuth)
+		}
+	case ClientAuthNone:
+	default:
+		return fmt.Errorf("unsupported TLS client auth %v", c.ClientAuth)
+	}
+
+	if c.ReloadIntervalMs == 0 {
+		c.ReloadIntervalMs = defaultReloadIntervalMs
+	}
+
+	return nil
+}
+
+//VerifiesClient returns true if client certificates are verified
+func (c *Config) VerifiesClient() bool {
+	return c.ClientAuth != ClientAuthNone
+}
+
+//New creates TLS certificates service
+func New(ctx context.Context, config *Config, fs afs.Service) (*Service, error) {
+	if err := config.Init(); err != nil {
+		return nil, err
+	}
+
+	result := &Service{config: config, fs: fs, modified: map[string]time.Time{}}
+	return result, result.load(ctx)
+}
+
+//TLSConfig returns server TLS config, certificates are reloaded when changed
+func (s *Service) TLSConfig() *tls.Config {
+	config := &tls.Config{MinVersion: tls.VersionTLS12, NextProtos: []string{"h2", "http/1.1"}}
+	config.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		s.reloadIfNeeded(hello.Context())
+		s.mux.RLock()
+		defer s.mux.RUnlock()
+		result := &tls.Config{MinVersion: tls.VersionTLS12, NextProtos: config.NextProtos, Certificates: []tls.Certificate{*s.cert}}
+		if s.pool != nil && s.config.VerifiesClient() {
+			result.ClientCAs = s.pool
+			result.ClientAuth = tls.RequireAndVerifyClientCert
+			if s.config.ClientAuth == ClientAuthOptional {
+				result.ClientAuth = tls.VerifyClientCertIfGiven
+			}
+		}
+
+		return result, nil
+	}
+
+	return config
+}
+
+func (s *Service) reloadIfNeeded(ctx context.Context) {
+	s.reload.Lock()
+	defer s.reload.Unlock()
+	if time.Since(s.checked) < time.Duration(s.config.ReloadIntervalMs)*time.Millisecond {
+		return
+	}
+
+	s.checked = time.Now()
+	if !s.changed(ctx) {
+		return
+	}
+
+	if err := s.load(ctx); err != nil {
+		fmt.Printf("failed to reload TLS certificates, keeping previous: %v\n", err)
+	}
+}
+
+func (s *Service) changed(ctx context.Context) bool {
+	for _, URL := range s.urls() {
+		object, err := s.fs.Object(ctx, URL)
+		if err != nil {
+			continue
+		}
+
+		if !object.ModTime().Equal(s.modified[URL]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Service) load(ctx context.Context) error {
+	modified := map[string]time.Time{}
+	for _, URL := range s.urls() {
+		if object, err := s.fs.Object(ctx, URL); err == nil {
+			modified[URL] = object.ModTime()
+		}
+	}
+
+	certPEM, err := s.fs.DownloadWithURL(ctx, s.config.CertURL)
+	if err != nil {
+		return err
+	}
+
+	keyPEM, err := s.fs.DownloadWithURL(ctx, s.config.KeyURL)
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+
+	var pool *x509.CertPool
+	if s.config.ClientCAURL != "" {
+		caPEM, err := s.fs.DownloadWithURL(ctx, s.config.ClientCAURL)
+		if err != nil {
+			return err
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return fmt.Errorf("invalid client CA bundle %v", s.config.ClientCAURL)
+		}
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.cert = &cert
+	s.pool = pool
+	s.modified = modified
+	return nil
+}
+
+func (s *Service) urls() []string {
+	result := []string{s.config.CertURL, s.config.KeyURL}
+	if s.config.ClientCAURL != "" {
+		result = append(result, s.config.ClientCAURL)
+	}
+
+	return result
+}
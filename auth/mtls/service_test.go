@@ -0,0 +1,179 @@
+package mtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCert(t *testing.T, commonName string, parent *testCert, isCA bool) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"datly"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:              []string{commonName},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+func (c *testCert) certPEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}))
+}
+
+func (c *testCert) keyPEM(t *testing.T) string {
+	der, err := x509.MarshalECPrivateKey(c.key)
+	assert.Nil(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+}
+
+func (c *testCert) tlsCert() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key}
+}
+
+func TestService_TLSConfig(t *testing.T) {
+	ctx := context.Background()
+	fs := afs.New()
+	ca := newTestCert(t, "datly-ca", nil, true)
+	server := newTestCert(t, "server-v1", ca, false)
+	client := newTestCert(t, "billing-service", ca, false)
+
+	upload := func(URL string, content string) {
+		assert.Nil(t, fs.Upload(ctx, URL, file.DefaultFileOsMode, strings.NewReader(content)))
+	}
+
+	config := &Config{CertURL: "mem://localhost/tls/server.pem", KeyURL: "mem://localhost/tls/server.key", ClientCAURL: "mem://localhost/tls/ca.pem", ReloadIntervalMs: 1}
+	upload(config.CertURL, server.certPEM())
+	upload(config.KeyURL, server.keyPEM(t))
+	upload(config.ClientCAURL, ca.certPEM())
+
+	service, err := New(ctx, config, fs)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var claims *Claims
+	var verified string
+	httpServer := httptest.NewUnstartedServer(Handler(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		claims, _ = ParseClaims(request.Header.Get(ClientCertHeader))
+		verified = VerifiedCertificate(request.Context())
+	})))
+	httpServer.TLS = service.TLSConfig()
+	httpServer.EnableHTTP2 = true
+	httpServer.StartTLS()
+	defer httpServer.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	newClient := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs}, DisableKeepAlives: true, ForceAttemptHTTP2: true}}
+	}
+
+	_, err = newClient().Get(httpServer.URL)
+	assert.NotNil(t, err, "client certificate is required")
+
+	request, _ := http.NewRequest(http.MethodGet, httpServer.URL, nil)
+	request.Header.Set(ClientCertHeader, EncodeCertificate(server.cert))
+	response, err := newClient(client.tlsCert()).Do(request)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "server-v1", response.TLS.PeerCertificates[0].Subject.CommonName)
+	assert.Equal(t, "h2", response.TLS.NegotiatedProtocol, "ALPN protocol negotiated with client config")
+	if assert.NotNil(t, claims) {
+		assert.Equal(t, "billing-service", claims.CommonName)
+		assert.Equal(t, []string{"datly"}, claims.Organization)
+		assert.Equal(t, []string{"127.0.0.1"}, claims.IPAddresses)
+	}
+	assert.Equal(t, EncodeCertificate(client.cert), verified)
+
+	rotated := newTestCert(t, "server-v2", ca, false)
+	time.Sleep(5 * time.Millisecond)
+	upload(config.CertURL, rotated.certPEM())
+	upload(config.KeyURL, rotated.keyPEM(t))
+
+	response, err = newClient(client.tlsCert()).Get(httpServer.URL)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "server-v2", response.TLS.PeerCertificates[0].Subject.CommonName)
+	}
+}
+
+func TestConfig_Init(t *testing.T) {
+	useCases := []struct {
+		description string
+		config      *Config
+		expect      string
+		expectErr   bool
+	}{
+		{
+			description: "client auth required by default with client CA",
+			config:      &Config{CertURL: "cert.pem", KeyURL: "key.pem", ClientCAURL: "ca.pem"},
+			expect:      ClientAuthRequire,
+		},
+		{
+			description: "client auth disabled by default without client CA",
+			config:      &Config{CertURL: "cert.pem", KeyURL: "key.pem"},
+			expect:      ClientAuthNone,
+		},
+		{
+			description: "required client auth without client CA",
+			config:      &Config{CertURL: "cert.pem", KeyURL: "key.pem", ClientAuth: "require"},
+			expectErr:   true,
+		},
+		{
+			description: "optional client auth without client CA",
+			config:      &Config{CertURL: "cert.pem", KeyURL: "key.pem", ClientAuth: "optional"},
+			expectErr:   true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		err := useCase.config.Init()
+		if useCase.expectErr {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+
+		if assert.Nil(t, err, useCase.description) {
+			assert.Equal(t, useCase.expect, useCase.config.ClientAuth, useCase.description)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	discoveryPath         = "/.well-known/openid-configuration"
+	defaultClockSkewSec   = 60
+	defaultRefreshSec     = 3600
+	defaultMinRefreshSec  = 30
+	defaultHTTPTimeoutSec = 10
+)
+
+//Config represents generic OpenID Connect authenticator config
+type Config struct {
+	Issuer             string
+	DiscoveryURL       string            `json:",omitempty"` //defaults to Issuer + /.well-known/openid-configuration
+	JwksURL            string            `json:",omitempty"` //overrides discovery document jwks_uri
+	Audiences          []string          `json:",omitempty"`
+	ClockSkewSec       int               `json:",omitempty"`
+	RefreshIntervalSec int               `json:",omitempty"` //periodic JWKS refresh
+	MinRefreshSec      int               `json:",omitempty"` //minimum interval between JWKS refreshes triggered by unknown kid
+	HTTPTimeoutSec     int               `json:",omitempty"`
+	ClaimsMapping      map[string]string `json:",omitempty"` //jwt.Claims json field name i.e. email, user_id, username -> token claim name
+
+	_skew       time.Duration
+	_refresh    time.Duration
+	_minRefresh time.Duration
+}
+
+//Init initializes Config
+func (c *Config) Init() error {
+	if c.Issuer == "" {
+		return fmt.Errorf("oidc issuer was empty")
+	}
+
+	if c.DiscoveryURL == "" {
+		c.DiscoveryURL = strings.TrimRight(c.Issuer, "/") + discoveryPath
+	}
+
+	if c.ClockSkewSec == 0 {
+		c.ClockSkewSec = defaultClockSkewSec
+	}
+
+	if c.RefreshIntervalSec == 0 {
+		c.RefreshIntervalSec = defaultRefreshSec
+	}
+
+	if c.MinRefreshSec == 0 {
+		c.MinRefreshSec = defaultMinRefreshSec
+	}
+
+	if c.HTTPTimeoutSec == 0 {
+		c.HTTPTimeoutSec = defaultHTTPTimeoutSec
+	}
+
+	if c.ClockSkewSec < 0 || c.RefreshIntervalSec < 0 || c.MinRefreshSec < 0 {
+		return fmt.Errorf("oidc clock skew and refresh intervals can't be negative")
+	}
+
+	c._skew = time.Duration(c.ClockSkewSec) * time.Second
+	c._refresh = time.Duration(c.RefreshIntervalSec) * time.Second
+	c._minRefresh = time.Duration(c.MinRefreshSec) * time.Second
+	return nil
+}
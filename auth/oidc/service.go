@@ -0,0 +1,384 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	sjwt "github.com/viant/scy/auth/jwt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+type (
+	//Service represents OpenID Connect token verifier with JWKS keys rotation
+	Service struct {
+		config  *Config
+		client  *http.Client
+		now     func() time.Time
+		jwksURL string
+
+		mux     sync.RWMutex
+		keys    map[string]interface{}
+		fetched time.Time
+		refresh sync.Mutex
+	}
+
+	discovery struct {
+		Issuer  string `json:"issuer"`
+		JwksURI string `json:"jwks_uri"`
+	}
+
+	jsonWebKeys struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}
+
+	jsonWebKey struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+)
+
+//New creates OpenID Connect service
+func New(config *Config) *Service {
+	return &Service{
+		config: config,
+		client: &http.Client{Timeout: time.Duration(config.HTTPTimeoutSec) * time.Second},
+		now:    time.Now,
+	}
+}
+
+//Init initializes config, fetches discovery document and JWKS
+func (s *Service) Init(ctx context.Context) error {
+	if err := s.config.Init(); err != nil {
+		return err
+	}
+
+	s.client.Timeout = time.Duration(s.config.HTTPTimeoutSec) * time.Second
+	s.jwksURL = s.config.JwksURL
+	if s.jwksURL == "" {
+		aDiscovery := &discovery{}
+		if err := s.get(ctx, s.config.DiscoveryURL, aDiscovery); err != nil {
+			return fmt.Errorf("failed to fetch oidc discovery document: %w", err)
+		}
+
+		if strings.TrimRight(aDiscovery.Issuer, "/") != strings.TrimRight(s.config.Issuer, "/") {
+			return fmt.Errorf("oidc discovery issuer %v doesn't match configured issuer %v", aDiscovery.Issuer, s.config.Issuer)
+		}
+
+		if aDiscovery.JwksURI == "" {
+			return fmt.Errorf("oidc discovery document %v jwks_uri was empty", s.config.DiscoveryURL)
+		}
+
+		s.jwksURL = aDiscovery.JwksURI
+	}
+
+	return s.refreshKeys(ctx)
+}
+
+//VerifyClaims verifies raw token and maps its claims
+func (s *Service) VerifyClaims(ctx context.Context, rawToken string) (*sjwt.Claims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods(signingMethods), jwt.WithoutClaimsValidation())
+	token, err := parser.Parse(rawToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.key(ctx, kid)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("expected %T, but had %T", claims, token.Claims)
+	}
+
+	if err = s.validate(claims); err != nil {
+		return nil, err
+	}
+
+	return s.mapClaims(claims), nil
+}
+
+func (s *Service) validate(claims jwt.MapClaims) error {
+	if !claims.VerifyIssuer(s.config.Issuer, true) {
+		return fmt.Errorf("invalid token issuer")
+	}
+
+	if len(s.config.Audiences) > 0 {
+		matched := false
+		for _, audience := range s.config.Audiences {
+			if claims.VerifyAudience(audience, true) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return fmt.Errorf("invalid token audience")
+		}
+	}
+
+	now := s.now()
+	if !claims.VerifyExpiresAt(now.Add(-s.config._skew).Unix(), true) {
+		return fmt.Errorf("token is expired")
+	}
+
+	if !claims.VerifyNotBefore(now.Add(s.config._skew).Unix(), false) {
+		return fmt.Errorf("token is not valid yet")
+	}
+
+	if !claims.VerifyIssuedAt(now.Add(s.config._skew).Unix(), false) {
+		return fmt.Errorf("token used before issued")
+	}
+
+	return nil
+}
+
+//mapClaims maps known token claims into jwt.Claims, claims of unexpected type are skipped, all token claims are exposed as Data
+func (s *Service) mapClaims(claims jwt.MapClaims) *sjwt.Claims {
+	mapped := make(map[string]interface{}, len(claims)+len(s.config.ClaimsMapping))
+	for key, value := range claims {
+		mapped[key] = value
+	}
+
+	for field, claim := range s.config.ClaimsMapping {
+		if value, ok := claims[claim]; ok {
+			mapped[field] = value
+		}
+	}
+
+	result := &sjwt.Claims{
+		Email:       stringClaim(mapped, "email"),
+		Username:    stringClaim(mapped, "username"),
+		FirstName:   stringClaim(mapped, "first_name"),
+		LastName:    stringClaim(mapped, "last_name"),
+		AccountName: stringClaim(mapped, "account_name"),
+		Scope:       stringClaim(mapped, "scope"),
+		Cognito:     stringClaim(mapped, "cognito"),
+		Data:        map[string]interface{}(claims),
+	}
+
+	result.UserID, _ = asInt(mapped["user_id"])
+	result.AccountId, _ = asInt(mapped["account_id"])
+	result.VerifiedEmail, _ = mapped["verified_email"].(bool)
+	if data, ok := mapped["dat"]; ok {
+		result.Data = data
+	}
+
+	result.Issuer = stringClaim(claims, "iss")
+	result.Subject = stringClaim(claims, "sub")
+	result.ID = stringClaim(claims, "jti")
+	result.Audience = audienceClaim(claims["aud"])
+	result.ExpiresAt = dateClaim(claims["exp"])
+	result.NotBefore = dateClaim(claims["nbf"])
+	result.IssuedAt = dateClaim(claims["iat"])
+	return result
+}
+
+func stringClaim(claims map[string]interface{}, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+func audienceClaim(value interface{}) jwt.ClaimStrings {
+	switch actual := value.(type) {
+	case string:
+		return jwt.ClaimStrings{actual}
+	case []interface{}:
+		var result jwt.ClaimStrings
+		for _, item := range actual {
+			if audience, ok := item.(string); ok {
+				result = append(result, audience)
+			}
+		}
+		return result
+	}
+
+	return nil
+}
+
+func dateClaim(value interface{}) *jwt.NumericDate {
+	switch actual := value.(type) {
+	case float64:
+		return jwt.NewNumericDate(time.Unix(int64(actual), 0))
+	case json.Number:
+		if unix, err := actual.Int64(); err == nil {
+			return jwt.NewNumericDate(time.Unix(unix, 0))
+		}
+	}
+
+	return nil
+}
+
+//key returns verification key by kid, unknown kid triggers JWKS refresh
+func (s *Service) key(ctx context.Context, kid string) (interface{}, error) {
+	key, stale := s.lookup(kid)
+	if key != nil && !stale {
+		return key, nil
+	}
+
+	if err := s.refreshKeys(ctx); err != nil {
+		if key != nil {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	if key, _ = s.lookup(kid); key != nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("not found oidc signing key %v", kid)
+}
+
+func (s *Service) lookup(kid string) (interface{}, bool) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	stale := s.now().Sub(s.fetched) > s.config._refresh
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, stale
+		}
+	}
+
+	return s.keys[kid], stale
+}
+
+func (s *Service) refreshKeys(ctx context.Context) error {
+	s.refresh.Lock()
+	defer s.refresh.Unlock()
+
+	s.mux.RLock()
+	fetched := s.fetched
+	s.mux.RUnlock()
+	if !fetched.IsZero() && s.now().Sub(fetched) < s.config._minRefresh {
+		return nil
+	}
+
+	webKeys := &jsonWebKeys{}
+	if err := s.get(ctx, s.jwksURL, webKeys); err != nil {
+		return fmt.Errorf("failed to fetch oidc jwks: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(webKeys.Keys))
+	for _, webKey := range webKeys.Keys {
+		if webKey.Use != "" && webKey.Use != "sig" {
+			continue
+		}
+
+		key, err := webKey.publicKey()
+		if err != nil {
+			return fmt.Errorf("invalid oidc jwks key %v: %w", webKey.Kid, err)
+		}
+
+		if key != nil {
+			keys[webKey.Kid] = key
+		}
+	}
+
+	s.mux.Lock()
+	s.keys = keys
+	s.fetched = s.now()
+	s.mux.Unlock()
+	return nil
+}
+
+func (s *Service) get(ctx context.Context, URL string, dest interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected %v response status %v", URL, response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(dest)
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %v", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+func asInt(value interface{}) (int, bool) {
+	switch actual := value.(type) {
+	case float64:
+		return int(actual), true
+	case string:
+		intValue, err := strconv.Atoi(actual)
+		return intValue, err == nil
+	}
+
+	return 0, false
+}
@@ -0,0 +1,143 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testIssuer struct {
+	server *httptest.Server
+	mux    sync.Mutex
+	keys   map[string]*rsa.PrivateKey
+}
+
+func newTestIssuer(kids ...string) *testIssuer {
+	result := &testIssuer{keys: map[string]*rsa.PrivateKey{}}
+	result.rotate(kids...)
+	result.server = httptest.NewServer(http.HandlerFunc(result.handle))
+	return result
+}
+
+func (i *testIssuer) rotate(kids ...string) {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+	i.keys = map[string]*rsa.PrivateKey{}
+	for _, kid := range kids {
+		i.keys[kid], _ = rsa.GenerateKey(rand.Reader, 2048)
+	}
+}
+
+func (i *testIssuer) handle(writer http.ResponseWriter, request *http.Request) {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+	switch request.URL.Path {
+	case discoveryPath:
+		_ = json.NewEncoder(writer).Encode(&discovery{Issuer: i.server.URL, JwksURI: i.server.URL + "/jwks"})
+	case "/jwks":
+		webKeys := &jsonWebKeys{}
+		for kid, key := range i.keys {
+			webKeys.Keys = append(webKeys.Keys, &jsonWebKey{
+				Kid: kid,
+				Kty: "RSA",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(writer).Encode(webKeys)
+	default:
+		writer.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (i *testIssuer) sign(kid string, claims jwt.MapClaims) string {
+	i.mux.Lock()
+	key := i.keys[kid]
+	i.mux.Unlock()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, _ := token.SignedString(key)
+	return signed
+}
+
+func TestService_VerifyClaims(t *testing.T) {
+	issuer := newTestIssuer("k1")
+	defer issuer.server.Close()
+
+	now := time.Now()
+	claims := func(overrides map[string]interface{}) jwt.MapClaims {
+		result := jwt.MapClaims{
+			"iss":   issuer.server.URL,
+			"aud":   "datly",
+			"sub":   "101",
+			"email": "dev@example.com",
+			"roles": []string{"admin"},
+			"exp":   now.Add(time.Hour).Unix(),
+			"nbf":   now.Add(-time.Minute).Unix(),
+		}
+		for k, v := range overrides {
+			result[k] = v
+		}
+		return result
+	}
+
+	service := New(&Config{Issuer: issuer.server.URL, Audiences: []string{"datly"}, ClockSkewSec: 30, ClaimsMapping: map[string]string{"user_id": "sub"}})
+	assert.Nil(t, service.Init(context.Background()))
+
+	useCases := []struct {
+		description string
+		rotate      []string
+		kid         string
+		claims      jwt.MapClaims
+		expectErr   bool
+	}{
+		{description: "valid token", kid: "k1", claims: claims(nil)},
+		{description: "expired within clock skew", kid: "k1", claims: claims(map[string]interface{}{"exp": now.Add(-10 * time.Second).Unix()})},
+		{description: "expired", kid: "k1", claims: claims(map[string]interface{}{"exp": now.Add(-time.Minute).Unix()}), expectErr: true},
+		{description: "not valid yet", kid: "k1", claims: claims(map[string]interface{}{"nbf": now.Add(time.Minute).Unix()}), expectErr: true},
+		{description: "wrong issuer", kid: "k1", claims: claims(map[string]interface{}{"iss": "https://other"}), expectErr: true},
+		{description: "wrong audience", kid: "k1", claims: claims(map[string]interface{}{"aud": "other"}), expectErr: true},
+		{description: "mistyped claims", kid: "k1", claims: claims(map[string]interface{}{"username": 123, "email_verified": "yes", "verified_email": "yes", "account_id": []string{"1"}})},
+		{description: "unknown kid within min refresh interval", rotate: []string{"k2"}, kid: "k2", claims: claims(nil), expectErr: true},
+		{description: "rotated key", kid: "k2", claims: claims(nil)},
+	}
+
+	for i, useCase := range useCases {
+		if useCase.rotate != nil {
+			issuer.rotate(useCase.rotate...)
+		}
+
+		if i == len(useCases)-1 {
+			service.now = func() time.Time {
+				return now.Add(time.Minute)
+			}
+		}
+
+		actual, err := service.VerifyClaims(context.Background(), issuer.sign(useCase.kid, useCase.claims))
+		if useCase.expectErr {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		assert.Equal(t, 101, actual.UserID, useCase.description)
+		assert.Equal(t, "dev@example.com", actual.Email, useCase.description)
+		assert.Equal(t, []interface{}{"admin"}, actual.Data.(map[string]interface{})["roles"], useCase.description)
+		assert.Equal(t, "101", actual.Subject, useCase.description)
+		assert.NotNil(t, actual.ExpiresAt, useCase.description)
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/viant/datly/gateway/runtime/standalone"
 	"github.com/viant/datly/gateway/warmup"
 	"github.com/viant/datly/router"
-	"github.com/viant/datly/router/openapi3"
 	"github.com/viant/datly/view"
 	"gopkg.in/yaml.v3"
 	"io"
@@ -52,6 +51,10 @@ func (s *Builder) build() (*standalone.Server, error) {
 		return nil, nil
 	}
 
+	if s.options.DriftCheck && s.config.RouteURL != "" {
+		checkDrift(context.Background(), s.config.RouteURL, s.options.Connector.Connectors(), s.logger)
+	}
+
 	var srv *standalone.Server
 	if authenticator == nil {
 		srv, err = standalone.New(s.config)
@@ -70,12 +73,23 @@ func (s *Builder) build() (*standalone.Server, error) {
 		return nil, err
 	}
 	if s.options.OpenApiURL != "" {
-		//TODO: add opeanpi3.Info to Config
-		openapiSpec, _ := router.GenerateOpenAPI3Spec(openapi3.Info{}, srv.Routes()...)
+		openapiSpec, _ := router.GenerateOpenAPI3Spec(s.options.Info(), srv.Routes()...)
 		openApiMarshal, _ := yaml.Marshal(openapiSpec)
 		_ = os.WriteFile(s.options.OpenApiURL, openApiMarshal, file.DefaultFileOsMode)
 	}
 
+	if s.options.GoClientURL != "" {
+		if err = writeGoClient(&s.options.Client, srv.Routes()); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.options.TypeScriptURL != "" {
+		if err = writeTypeScriptClient(&s.options.Client, srv.Routes()); err != nil {
+			return nil, err
+		}
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +145,27 @@ func New(version string, args []string, logger io.Writer) (*standalone.Server, e
 		return nil, err
 	}
 
+	if len(options.LintURLs) > 0 {
+		return nil, lint(options, logger)
+	}
+
+	if options.ReverseURL != "" {
+		return nil, reverse(options, logger)
+	}
+
+	if len(options.DriftURLs) > 0 {
+		return nil, drift(options, logger)
+	}
+
 	options.Init()
+	if len(options.TestURLs) > 0 {
+		return nil, runTestSuites(options, logger)
+	}
+
+	if options.ScaffoldTable != "" {
+		return nil, scaffold(options, logger)
+	}
+
 	builder, err := NewBuilder(options, logger)
 	if err != nil {
 		return nil, err
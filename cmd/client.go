@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/viant/afs/file"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/view"
+	"os"
+)
+
+//writeGoClient writes Go client source generated for routes, package defaults to client
+func writeGoClient(options *Client, routes []*router.Route) error {
+	source, err := router.GenerateGoClient(view.FirstNotEmpty(options.GoClientPackage, "client"), routes...)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(normalizeURL(options.GoClientURL), source, file.DefaultFileOsMode)
+}
+
+//writeTypeScriptClient writes TypeScript client module generated for routes
+func writeTypeScriptClient(options *Client, routes []*router.Route) error {
+	source, err := router.GenerateTypeScriptClient(routes...)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(normalizeURL(options.TypeScriptURL), source, file.DefaultFileOsMode)
+}
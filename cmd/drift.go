@@ -0,0 +1,759 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/url"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/view"
+	"github.com/viant/datly/view/discover"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlx/io"
+	"github.com/viant/sqlx/io/config"
+	"github.com/viant/sqlx/metadata/sink"
+	"gopkg.in/yaml.v3"
+	goIo "io"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	DriftAdded   = "added"
+	DriftRemoved = "removed"
+	DriftRetyped = "retyped"
+
+	DriftFixPatch      = "patch"
+	DriftFixRegenerate = "regenerate"
+
+	pinnedByRoute = "route"
+	pinnedByCache = "cache"
+)
+
+var wildcardSQL = regexp.MustCompile(`(?is)^\s*\(?\s*SELECT\s+(\w+\.)?\*\s+FROM\s+([\w.]+)`)
+
+type (
+	//ColumnDrift represents difference between column pinned by view or parameter type and live table column
+	ColumnDrift struct {
+		URL       string
+		View      string
+		Parameter string
+		Table     string
+		Column    string
+		Kind      string
+		Expected  string
+		Actual    string
+		nullable  bool
+		pinnedBy  string
+	}
+
+	//Drifts represents schema drifts
+	Drifts []*ColumnDrift
+
+	//DriftDetector compares route views and parameter types with database metadata
+	DriftDetector struct {
+		fs         afs.Service
+		connectors map[string]*view.Connector
+		dbs        map[string]*sql.DB
+		tables     map[string][]sink.Column
+	}
+
+	driftColumn struct {
+		name     string
+		dataType string
+		rType    reflect.Type
+	}
+)
+
+func (d *ColumnDrift) String() string {
+	owner := "view " + d.View
+	if d.Parameter != "" {
+		owner += " parameter " + d.Parameter
+	}
+
+	switch d.Kind {
+	case DriftAdded:
+		return fmt.Sprintf("%v: %v: column %v.%v added (%v)", d.URL, owner, d.Table, d.Column, d.Actual)
+	case DriftRetyped:
+		return fmt.Sprintf("%v: %v: column %v.%v retyped from %v to %v", d.URL, owner, d.Table, d.Column, d.Expected, d.Actual)
+	default:
+		return fmt.Sprintf("%v: %v: column %v.%v removed", d.URL, owner, d.Table, d.Column)
+	}
+}
+
+//NewDriftDetector creates DriftDetector, connectors take precedence over connectors defined in route
+func NewDriftDetector(connectors ...*view.Connector) *DriftDetector {
+	result := &DriftDetector{
+		fs:         afs.New(),
+		connectors: map[string]*view.Connector{},
+		dbs:        map[string]*sql.DB{},
+		tables:     map[string][]sink.Column{},
+	}
+
+	for _, connector := range connectors {
+		result.connectors[connector.Name] = connector
+	}
+
+	return result
+}
+
+//Detect compares views Columns, Schema and parameters struct types of route YAML with table columns
+func (d *DriftDetector) Detect(ctx context.Context, URL string) (Drifts, error) {
+	resource, err := router.LoadResource(ctx, d.fs, URL, false)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := d.columnsCache(ctx, URL)
+	types := d.types(ctx, resource.Resource)
+	parameters := map[string]*view.Parameter{}
+	for _, parameter := range resource.Resource.Parameters {
+		parameters[parameter.Name] = parameter
+	}
+
+	var result Drifts
+	for _, aView := range resource.Resource.Views {
+		if aView.Table == "" {
+			continue
+		}
+
+		connector, err := d.connector(aView, resource.Resource)
+		if err != nil {
+			return nil, err
+		}
+
+		tableColumns, err := d.tableColumns(ctx, connector, aView.Table)
+		if err != nil {
+			return nil, err
+		}
+
+		source, ok := d.viewSource(ctx, URL, aView)
+		wildcard := ok && d.isWildcard(source, aView)
+		selected := d.selectedColumns(source)
+		pinnedBy, pinned := pinnedByRoute, aView.Columns
+		if len(pinned) == 0 && cache != nil {
+			pinnedBy, pinned = pinnedByCache, cache.Items[aView.Name]
+		}
+
+		if len(pinned) > 0 {
+			var columns []*driftColumn
+			for _, column := range pinned {
+				if column.Expression != "" || column.Codec != nil {
+					continue
+				}
+
+				name := column.DatabaseColumn
+				if name == "" {
+					name = column.Name
+				}
+
+				rType, _ := view.ParseType(column.DataType, types)
+				columns = append(columns, &driftColumn{name: name, dataType: column.DataType, rType: rType})
+			}
+
+			result = append(result, d.compare(URL, aView, "", pinnedBy, columns, tableColumns, wildcard, selected)...)
+		} else if aView.Schema != nil && aView.Schema.DataType != "" {
+			if columns := d.structColumns(types, aView.Schema.DataType); len(columns) > 0 {
+				result = append(result, d.compare(URL, aView, "", "", columns, tableColumns, wildcard, selected)...)
+			}
+		}
+
+		if aView.Template == nil {
+			continue
+		}
+
+		for _, parameter := range aView.Template.Parameters {
+			if parameter.Ref != "" && parameters[parameter.Ref] != nil {
+				parameter = parameters[parameter.Ref]
+			}
+
+			if parameter.Schema == nil || parameter.Schema.DataType == "" {
+				continue
+			}
+
+			if columns := d.structColumns(types, parameter.Schema.DataType); len(columns) > 0 {
+				result = append(result, d.compare(URL, aView, parameter.Name, "", columns, tableColumns, true, nil)...)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+//compare reports columns drifts, removed columns are reported for wildcard views and columns selected from the table as is
+func (d *DriftDetector) compare(URL string, aView *view.View, parameter string, pinnedBy string, columns []*driftColumn, tableColumns []sink.Column, wildcard bool, selected map[string]bool) Drifts {
+	var result Drifts
+	newDrift := func(kind, column string) *ColumnDrift {
+		drift := &ColumnDrift{URL: URL, View: aView.Name, Parameter: parameter, Table: aView.Table, Column: column, Kind: kind, pinnedBy: pinnedBy}
+		result = append(result, drift)
+		return drift
+	}
+
+	tableIndex := map[string]*sink.Column{}
+	for i, column := range tableColumns {
+		tableIndex[strings.ToLower(column.Name)] = &tableColumns[i]
+	}
+
+	pinnedIndex := map[string]bool{}
+	for _, column := range columns {
+		pinnedIndex[strings.ToLower(column.name)] = true
+		tableColumn, ok := tableIndex[strings.ToLower(column.name)]
+		if !ok {
+			if wildcard || selected[strings.ToLower(column.name)] {
+				newDrift(DriftRemoved, column.name).Expected = column.dataType
+			}
+			continue
+		}
+
+		actual, err := view.ParseType(tableColumn.Type, nil)
+		if err != nil || column.rType == nil || driftType(actual) == driftType(column.rType) {
+			continue
+		}
+
+		drift := newDrift(DriftRetyped, column.name)
+		drift.Expected, drift.Actual = column.dataType, tableColumn.Type
+		if drift.Expected == "" {
+			drift.Expected = column.rType.String()
+		}
+	}
+
+	if !wildcard {
+		return result
+	}
+
+	excluded := map[string]bool{}
+	for _, column := range aView.Exclude {
+		excluded[strings.ToLower(column)] = true
+	}
+
+	for _, column := range tableColumns {
+		name := strings.ToLower(column.Name)
+		if pinnedIndex[name] || excluded[name] {
+			continue
+		}
+
+		drift := newDrift(DriftAdded, column.Name)
+		drift.Actual = column.Type
+		drift.nullable = strings.ToLower(column.Nullable) == "yes" || column.Nullable == "1"
+	}
+
+	return result
+}
+
+//driftType normalizes type to the one datly uses for column of given database type
+func driftType(rType reflect.Type) reflect.Type {
+	for rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+
+	switch rType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.TypeOf(0)
+	case reflect.Float32, reflect.Float64:
+		return reflect.TypeOf(0.0)
+	}
+
+	return rType
+}
+
+//structColumns returns top level struct fields mapped to columns with sqlx tag, transient fields are skipped
+func (d *DriftDetector) structColumns(types view.Types, dataType string) []*driftColumn {
+	rType, err := view.GetOrParseType(types, dataType)
+	if err != nil {
+		return nil
+	}
+
+	for rType.Kind() == reflect.Ptr || rType.Kind() == reflect.Slice {
+		rType = rType.Elem()
+	}
+
+	if rType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var result []*driftColumn
+	for i := 0; i < rType.NumField(); i++ {
+		field := rType.Field(i)
+		sqlxTag, ok := field.Tag.Lookup("sqlx")
+		if !ok || field.PkgPath != "" {
+			continue
+		}
+
+		tag := io.ParseTag(sqlxTag)
+		if tag.Transient || tag.Column == "" {
+			continue
+		}
+
+		result = append(result, &driftColumn{name: tag.Column, dataType: field.Type.String(), rType: field.Type})
+	}
+
+	return result
+}
+
+func (d *DriftDetector) types(ctx context.Context, resource *view.Resource) view.Types {
+	types := view.Types{}
+	for _, definition := range resource.Types {
+		if err := definition.Init(ctx, types); err != nil {
+			continue
+		}
+
+		types.Register(definition.Name, definition.Type())
+	}
+
+	return types
+}
+
+//viewSource returns view SQL, false if template source URL couldn't be read
+func (d *DriftDetector) viewSource(ctx context.Context, URL string, aView *view.View) (string, bool) {
+	source := aView.From
+	if source == "" && aView.Template != nil {
+		source = aView.Template.Source
+		if source == "" && aView.Template.SourceURL != "" {
+			parent, _ := url.Split(URL, file.Scheme)
+			data, err := d.fs.DownloadWithURL(ctx, url.Join(parent, aView.Template.SourceURL))
+			if err != nil {
+				return "", false
+			}
+			source = string(data)
+		}
+	}
+
+	return source, true
+}
+
+func (d *DriftDetector) isWildcard(source string, aView *view.View) bool {
+	if strings.TrimSpace(source) == "" {
+		return true
+	}
+
+	matched := wildcardSQL.FindStringSubmatch(source)
+	return len(matched) > 0 && strings.EqualFold(matched[2], aView.Table)
+}
+
+//selectedColumns returns lower case names of columns selected as is, expressions and aliased columns are skipped
+func (d *DriftDetector) selectedColumns(source string) map[string]bool {
+	if strings.TrimSpace(source) == "" {
+		return nil
+	}
+
+	SQL := strings.TrimSpace(source)
+	if strings.HasPrefix(SQL, "(") && strings.HasSuffix(SQL, ")") {
+		SQL = SQL[1 : len(SQL)-1]
+	}
+
+	aQuery, err := sqlparser.ParseQuery(SQL)
+	if err != nil || aQuery == nil {
+		return nil
+	}
+
+	result := map[string]bool{}
+	for _, item := range aQuery.List {
+		var name string
+		switch actual := item.Expr.(type) {
+		case *expr.Ident:
+			name = actual.Name
+		case *expr.Selector:
+			name = sqlparser.Stringify(actual.X)
+		default:
+			continue
+		}
+
+		name = strings.Trim(name, "`")
+		if item.Alias != "" && !strings.EqualFold(item.Alias, name) {
+			continue
+		}
+
+		result[strings.ToLower(name)] = true
+	}
+
+	return result
+}
+
+func (d *DriftDetector) columnsCache(ctx context.Context, URL string) *discover.Cache {
+	parent, name := url.Split(URL, file.Scheme)
+	cache := discover.New(url.Join(parent, ".meta", name), d.fs)
+	if !cache.Exists(ctx) {
+		return nil
+	}
+
+	sourceURL := cache.SourceURL
+	if err := cache.Load(ctx); err != nil {
+		return nil
+	}
+
+	cache.SourceURL = sourceURL
+	return cache
+}
+
+func (d *DriftDetector) connector(aView *view.View, resource *view.Resource) (*view.Connector, error) {
+	name := ""
+	if aView.Connector != nil {
+		name = aView.Connector.Ref
+		if name == "" {
+			name = aView.Connector.Name
+		}
+	}
+
+	if connector, ok := d.connectors[name]; ok {
+		return connector, nil
+	}
+
+	for _, connector := range resource.Connectors {
+		if connector.Name == name {
+			return connector, nil
+		}
+	}
+
+	if aView.Connector != nil && aView.Connector.Driver != "" && aView.Connector.DSN != "" {
+		return aView.Connector, nil
+	}
+
+	if len(d.connectors) == 1 {
+		for _, connector := range d.connectors {
+			return connector, nil
+		}
+	}
+
+	return nil, fmt.Errorf("view %v: unknown connector %v", aView.Name, name)
+}
+
+func (d *DriftDetector) tableColumns(ctx context.Context, connector *view.Connector, tableName string) ([]sink.Column, error) {
+	key := connector.Name + ":" + tableName
+	if columns, ok := d.tables[key]; ok {
+		return columns, nil
+	}
+
+	db, ok := d.dbs[connector.Name]
+	if !ok {
+		aConnector := &view.Connector{Name: connector.Name, Driver: connector.Driver, DSN: connector.DSN, Secret: connector.Secret}
+		if err := aConnector.Init(ctx, nil); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if db, err = aConnector.DB(); err != nil {
+			return nil, err
+		}
+		d.dbs[connector.Name] = db
+	}
+
+	session, err := config.Session(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := config.Columns(ctx, session, db, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %v does not exist", tableName)
+	}
+
+	d.tables[key] = columns
+	return columns, nil
+}
+
+//Fix patches or regenerates columns pinned by route YAML and columns cache, parameter types drifts are not fixed
+func (d *DriftDetector) Fix(ctx context.Context, URL string, drifts Drifts, mode string) error {
+	if mode != DriftFixPatch && mode != DriftFixRegenerate {
+		return fmt.Errorf("unsupported drift fix mode %v, supported: %v, %v", mode, DriftFixPatch, DriftFixRegenerate)
+	}
+
+	byView := map[string]map[string]Drifts{}
+	for _, drift := range drifts {
+		if drift.URL != URL || drift.pinnedBy == "" {
+			continue
+		}
+
+		if byView[drift.pinnedBy] == nil {
+			byView[drift.pinnedBy] = map[string]Drifts{}
+		}
+		byView[drift.pinnedBy][drift.View] = append(byView[drift.pinnedBy][drift.View], drift)
+	}
+
+	if views := byView[pinnedByRoute]; len(views) > 0 {
+		if err := d.fixRoute(ctx, URL, views, mode); err != nil {
+			return err
+		}
+	}
+
+	views := byView[pinnedByCache]
+	if len(views) == 0 {
+		return nil
+	}
+
+	cache := d.columnsCache(ctx, URL)
+	if cache == nil {
+		return nil
+	}
+
+	for viewName, viewDrifts := range views {
+		if mode == DriftFixRegenerate {
+			delete(cache.Items, viewName)
+			continue
+		}
+
+		cache.Items[viewName] = patchColumns(cache.Items[viewName], viewDrifts)
+	}
+
+	return cache.Store(ctx)
+}
+
+func patchColumns(columns view.Columns, drifts Drifts) view.Columns {
+	index := map[string]*ColumnDrift{}
+	for _, drift := range drifts {
+		index[strings.ToLower(drift.Column)] = drift
+	}
+
+	var result view.Columns
+	for _, column := range columns {
+		name := column.DatabaseColumn
+		if name == "" {
+			name = column.Name
+		}
+
+		drift, ok := index[strings.ToLower(name)]
+		switch {
+		case !ok:
+		case drift.Kind == DriftRemoved:
+			continue
+		case drift.Kind == DriftRetyped:
+			column.DataType = drift.Actual
+		}
+
+		result = append(result, column)
+	}
+
+	for _, drift := range drifts {
+		if drift.Kind == DriftAdded {
+			result = append(result, &view.Column{Name: drift.Column, DataType: drift.Actual, Nullable: drift.nullable})
+		}
+	}
+
+	return result
+}
+
+func (d *DriftDetector) fixRoute(ctx context.Context, URL string, views map[string]Drifts, mode string) error {
+	data, err := d.fs.DownloadWithURL(ctx, URL)
+	if err != nil {
+		return err
+	}
+
+	root := &yaml.Node{}
+	if err = yaml.Unmarshal(data, root); err != nil {
+		return err
+	}
+
+	if len(root.Content) == 0 {
+		return fmt.Errorf("route was empty: %v", URL)
+	}
+
+	viewsNode, _ := yamlMapValue(yamlValueOf(root.Content[0], "Resource"), "Views")
+	if viewsNode == nil {
+		return fmt.Errorf("route has no views: %v", URL)
+	}
+
+	for _, viewNode := range viewsNode.Content {
+		nameNode, _ := yamlMapValue(viewNode, "Name")
+		if nameNode == nil || len(views[nameNode.Value]) == 0 {
+			continue
+		}
+
+		columnsNode, index := yamlMapValue(viewNode, "Columns")
+		if columnsNode == nil {
+			continue
+		}
+
+		if mode == DriftFixRegenerate {
+			viewNode.Content = append(viewNode.Content[:index-1], viewNode.Content[index+1:]...)
+			continue
+		}
+
+		patchColumnsNode(columnsNode, views[nameNode.Value])
+	}
+
+	if data, err = yaml.Marshal(root); err != nil {
+		return err
+	}
+
+	return d.fs.Upload(ctx, URL, file.DefaultFileOsMode, strings.NewReader(string(data)))
+}
+
+//patchColumnsNode applies drifts to route YAML columns keeping columns settings
+func patchColumnsNode(node *yaml.Node, drifts Drifts) {
+	index := map[string]*ColumnDrift{}
+	for _, drift := range drifts {
+		index[strings.ToLower(drift.Column)] = drift
+	}
+
+	var content []*yaml.Node
+	for _, columnNode := range node.Content {
+		nameNode := yamlValueOf(columnNode, "DatabaseColumn")
+		if nameNode == nil || nameNode.Value == "" {
+			nameNode = yamlValueOf(columnNode, "Name")
+		}
+
+		var drift *ColumnDrift
+		if nameNode != nil {
+			drift = index[strings.ToLower(nameNode.Value)]
+		}
+
+		switch {
+		case drift == nil:
+		case drift.Kind == DriftRemoved:
+			continue
+		case drift.Kind == DriftRetyped:
+			if dataTypeNode := yamlValueOf(columnNode, "DataType"); dataTypeNode != nil {
+				dataTypeNode.Value = drift.Actual
+			} else {
+				columnNode.Content = append(columnNode.Content, yamlScalar("DataType"), yamlScalar(drift.Actual))
+			}
+		}
+
+		content = append(content, columnNode)
+	}
+
+	for _, drift := range drifts {
+		if drift.Kind != DriftAdded {
+			continue
+		}
+
+		columnNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+			yamlScalar("Name"), yamlScalar(drift.Column),
+			yamlScalar("DataType"), yamlScalar(drift.Actual),
+		}}
+		if drift.nullable {
+			columnNode.Content = append(columnNode.Content, yamlScalar("Nullable"), &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		}
+		content = append(content, columnNode)
+	}
+
+	node.Content = content
+}
+
+func yamlScalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func yamlValueOf(node *yaml.Node, key string) *yaml.Node {
+	value, _ := yamlMapValue(node, key)
+	return value
+}
+
+//yamlMapValue returns value node and its index for case insensitive mapping key
+func yamlMapValue(node *yaml.Node, key string) (*yaml.Node, int) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, -1
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return node.Content[i+1], i + 1
+		}
+	}
+
+	return nil, -1
+}
+
+//routeURLs returns route YAML locations under given URL, columns cache folders are skipped
+func routeURLs(ctx context.Context, fs afs.Service, URL string) ([]string, error) {
+	objects, err := fs.List(ctx, URL)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, object := range objects {
+		if url.Equals(object.URL(), URL) {
+			continue
+		}
+
+		if object.IsDir() {
+			if object.Name() == ".meta" {
+				continue
+			}
+
+			nested, err := routeURLs(ctx, fs, object.URL())
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, nested...)
+			continue
+		}
+
+		if ext := strings.ToLower(path.Ext(object.Name())); ext == ".yaml" || ext == ".yml" {
+			result = append(result, object.URL())
+		}
+	}
+
+	sort.Strings(result)
+	return result, nil
+}
+
+//checkDrift logs schema drifts of all routes under given URL, routes that can't be checked are reported too
+func checkDrift(ctx context.Context, URL string, connectors []*view.Connector, logger goIo.Writer) {
+	URLs, err := routeURLs(ctx, afs.New(), URL)
+	if err != nil {
+		_, _ = logger.Write([]byte(fmt.Sprintf("drift check failed: %v\n", err)))
+		return
+	}
+
+	detector := NewDriftDetector(connectors...)
+	for _, routeURL := range URLs {
+		drifts, err := detector.Detect(ctx, routeURL)
+		if err != nil {
+			_, _ = logger.Write([]byte(fmt.Sprintf("drift check failed: %v: %v\n", routeURL, err)))
+			continue
+		}
+
+		for _, drift := range drifts {
+			_, _ = logger.Write([]byte("warning: " + drift.String() + "\n"))
+		}
+	}
+}
+
+func drift(options *Options, logger goIo.Writer) error {
+	var connectors []*view.Connector
+	if options.DSN != "" || len(options.Connects) > 0 {
+		options.Connector.Init()
+		connectors = options.Connector.Connectors()
+	}
+
+	ctx := context.Background()
+	detector := NewDriftDetector(connectors...)
+	total := 0
+	for _, URL := range options.DriftURLs {
+		URL = normalizeURL(URL)
+		drifts, err := detector.Detect(ctx, URL)
+		if err != nil {
+			return err
+		}
+
+		for _, drift := range drifts {
+			_, _ = logger.Write([]byte(drift.String() + "\n"))
+		}
+
+		total += len(drifts)
+		if options.DriftFix == "" || len(drifts) == 0 {
+			continue
+		}
+
+		if err = detector.Fix(ctx, URL, drifts, strings.ToLower(options.DriftFix)); err != nil {
+			return err
+		}
+	}
+
+	if total > 0 && options.DriftFix == "" {
+		return fmt.Errorf("detected %v schema drift(s)", total)
+	}
+
+	return nil
+}
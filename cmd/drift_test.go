@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestDriftDetector_Detect(t *testing.T) {
+	dbLocation := "/tmp/datly_drift_test.db"
+	_ = os.Remove(dbLocation)
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE vendor (ID INTEGER PRIMARY KEY, NAME INTEGER, CREATED DATETIME)")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	testLocation := toolbox.CallerDirectory(3)
+	paramDrifts := []string{
+		"view vendor parameter Vendor: column vendor.NAME retyped from string to INTEGER",
+		"view vendor parameter Vendor: column vendor.CREATED added (DATETIME)",
+	}
+
+	useCases := []struct {
+		description string
+		route       string
+		fix         string
+		expect      []string
+		expectFixed []string
+	}{
+		{
+			description: "detect",
+			route:       "route.yaml",
+			expect: []string{
+				"view vendor: column vendor.NAME retyped from string to INTEGER",
+				"view vendor: column vendor.REMOVED removed",
+				"view vendor: column vendor.CREATED added (DATETIME)",
+				paramDrifts[0],
+				paramDrifts[1],
+			},
+		},
+		{
+			description: "patch",
+			route:       "route.yaml",
+			fix:         DriftFixPatch,
+			expectFixed: paramDrifts,
+		},
+		{
+			description: "regenerate",
+			route:       "route.yaml",
+			fix:         DriftFixRegenerate,
+			expectFixed: paramDrifts,
+		},
+		{
+			description: "detect without wildcard",
+			route:       "explicit.yaml",
+			expect: []string{
+				"view vendor: column vendor.NAME retyped from string to INTEGER",
+				"view vendor: column vendor.REMOVED removed",
+			},
+		},
+	}
+
+	for _, useCase := range useCases {
+		source, err := os.ReadFile(path.Join(testLocation, "testdata", "drift", useCase.route))
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		URL := path.Join(os.TempDir(), "datly_drift", "route.yaml")
+		_ = os.MkdirAll(path.Dir(URL), 0755)
+		if !assert.Nil(t, os.WriteFile(URL, source, 0644), useCase.description) {
+			continue
+		}
+
+		detector := NewDriftDetector()
+		drifts, err := detector.Detect(context.Background(), URL)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		if useCase.fix == "" {
+			assert.Equal(t, useCase.expect, driftMessages(URL, drifts), useCase.description)
+			continue
+		}
+
+		if !assert.Nil(t, detector.Fix(context.Background(), URL, drifts, useCase.fix), useCase.description) {
+			continue
+		}
+
+		drifts, err = NewDriftDetector().Detect(context.Background(), URL)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		assert.Equal(t, useCase.expectFixed, driftMessages(URL, drifts), useCase.description)
+	}
+}
+
+func driftMessages(URL string, drifts Drifts) []string {
+	var result []string
+	for _, drift := range drifts {
+		result = append(result, drift.String()[len(URL)+2:])
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//selectJSONPath returns values matching path like $.Data[0].Id, * matches every map value or slice item
+func selectJSONPath(value interface{}, aPath string) []interface{} {
+	return selectPath(value, pathTokens(aPath))
+}
+
+//removeJSONPath removes map keys matching path, matching slice items are set to nil
+func removeJSONPath(value interface{}, aPath string) {
+	removePath(value, pathTokens(aPath))
+}
+
+func pathTokens(aPath string) []string {
+	aPath = strings.NewReplacer("[", ".", "]", "").Replace(aPath)
+	var result []string
+	for _, token := range strings.Split(aPath, ".") {
+		if token == "" || token == "$" {
+			continue
+		}
+		result = append(result, token)
+	}
+
+	return result
+}
+
+func selectPath(value interface{}, tokens []string) []interface{} {
+	if len(tokens) == 0 {
+		return []interface{}{value}
+	}
+
+	var result []interface{}
+	for _, child := range pathChildren(value, tokens[0]) {
+		result = append(result, selectPath(child, tokens[1:])...)
+	}
+
+	return result
+}
+
+func removePath(value interface{}, tokens []string) {
+	if len(tokens) == 0 {
+		return
+	}
+
+	if len(tokens) > 1 {
+		for _, child := range pathChildren(value, tokens[0]) {
+			removePath(child, tokens[1:])
+		}
+		return
+	}
+
+	switch actual := value.(type) {
+	case map[string]interface{}:
+		if tokens[0] == "*" {
+			for key := range actual {
+				delete(actual, key)
+			}
+			return
+		}
+		delete(actual, tokens[0])
+	case []interface{}:
+		for i := range actual {
+			if tokens[0] == "*" || tokens[0] == strconv.Itoa(i) {
+				actual[i] = nil
+			}
+		}
+	}
+}
+
+func pathChildren(value interface{}, token string) []interface{} {
+	switch actual := value.(type) {
+	case map[string]interface{}:
+		if token != "*" {
+			child, ok := actual[token]
+			if !ok {
+				return nil
+			}
+			return []interface{}{child}
+		}
+
+		var result []interface{}
+		for _, key := range sortedKeys(actual) {
+			result = append(result, actual[key])
+		}
+		return result
+	case []interface{}:
+		if token == "*" {
+			return actual
+		}
+
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index >= len(actual) {
+			return nil
+		}
+		return []interface{}{actual[index]}
+	}
+
+	return nil
+}
+
+func sortedKeys(aMap map[string]interface{}) []string {
+	result := make([]string, 0, len(aMap))
+	for key := range aMap {
+		result = append(result, key)
+	}
+
+	sort.Strings(result)
+	return result
+}
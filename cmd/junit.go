@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"strings"
+	"time"
+)
+
+type (
+	junitSuites struct {
+		XMLName  xml.Name      `xml:"testsuites"`
+		Tests    int           `xml:"tests,attr"`
+		Failures int           `xml:"failures,attr"`
+		Time     string        `xml:"time,attr"`
+		Suites   []*junitSuite `xml:"testsuite"`
+	}
+
+	junitSuite struct {
+		Name     string       `xml:"name,attr"`
+		Tests    int          `xml:"tests,attr"`
+		Failures int          `xml:"failures,attr"`
+		Time     string       `xml:"time,attr"`
+		Cases    []*junitCase `xml:"testcase"`
+	}
+
+	junitCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Time      string        `xml:"time,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+//writeJUnit writes test results as JUnit XML report, suites are reported in results order
+func writeJUnit(ctx context.Context, fs afs.Service, URL string, results []*TestResult) error {
+	report := &junitSuites{}
+	suites := map[string]*junitSuite{}
+	var total time.Duration
+	for _, result := range results {
+		suite, ok := suites[result.Suite]
+		if !ok {
+			suite = &junitSuite{Name: result.Suite}
+			suites[result.Suite] = suite
+			report.Suites = append(report.Suites, suite)
+		}
+
+		aCase := &junitCase{Name: result.Case, ClassName: result.Suite, Time: junitTime(result.Elapsed)}
+		if len(result.Failures) > 0 {
+			aCase.Failure = &junitFailure{Message: result.Failures[0], Text: strings.Join(result.Failures, "\n")}
+			suite.Failures++
+			report.Failures++
+		}
+
+		suite.Tests++
+		suite.Cases = append(suite.Cases, aCase)
+		report.Tests++
+		total += result.Elapsed
+	}
+
+	report.Time = junitTime(total)
+	for _, suite := range report.Suites {
+		var elapsed time.Duration
+		for _, result := range results {
+			if result.Suite == suite.Name {
+				elapsed += result.Elapsed
+			}
+		}
+		suite.Time = junitTime(elapsed)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = fs.Upload(ctx, URL, file.DefaultFileOsMode, bytes.NewReader(append([]byte(xml.Header), data...))); err != nil {
+		return fmt.Errorf("failed to write JUnit report %v: %w", URL, err)
+	}
+
+	return nil
+}
+
+func junitTime(elapsed time.Duration) string {
+	return fmt.Sprintf("%.3f", elapsed.Seconds())
+}
@@ -0,0 +1,602 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/datly/cmd/option"
+	"github.com/viant/datly/gateway/registry"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/template/sanitize"
+	"github.com/viant/datly/view"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	"github.com/viant/sqlparser/query"
+	"github.com/viant/velty/parser"
+	"gopkg.in/yaml.v3"
+	"io"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+):`)
+
+type (
+	//Diagnostic represents lint finding at given file position
+	Diagnostic struct {
+		URL      string
+		Line     int
+		Column   int
+		Severity string
+		Message  string
+	}
+
+	//Diagnostics represents lint findings
+	Diagnostics []*Diagnostic
+
+	//Linter statically validates DSQL and route YAML, column references are checked only with connector
+	Linter struct {
+		fs        afs.Service
+		connector *view.Connector
+		db        *sql.DB
+		tables    map[string]map[string]bool
+	}
+
+	lintSource struct {
+		URL         string
+		content     string
+		diagnostics Diagnostics
+	}
+
+	lintParameter struct {
+		Parameter
+		option.ParamMeta
+	}
+)
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%v:%v:%v: %v: %v", d.URL, d.Line, d.Column, d.Severity, d.Message)
+}
+
+//Errors returns number of error diagnostics
+func (d Diagnostics) Errors() int {
+	result := 0
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			result++
+		}
+	}
+
+	return result
+}
+
+//NewLinter creates linter, connector is optional
+func NewLinter(connector *view.Connector) *Linter {
+	return &Linter{fs: afs.New(), connector: connector, tables: map[string]map[string]bool{}}
+}
+
+//Lint validates DSQL or route YAML (.yaml, .yml) located at URL
+func (l *Linter) Lint(ctx context.Context, URL string) (Diagnostics, error) {
+	data, err := l.fs.DownloadWithURL(ctx, URL)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &lintSource{URL: URL, content: string(data)}
+	switch strings.ToLower(path.Ext(URL)) {
+	case ".yaml", ".yml":
+		err = l.lintRoute(ctx, src)
+	default:
+		err = l.lintDSQL(ctx, src)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(src.diagnostics, func(i, j int) bool {
+		if src.diagnostics[i].Line == src.diagnostics[j].Line {
+			return src.diagnostics[i].Column < src.diagnostics[j].Column
+		}
+		return src.diagnostics[i].Line < src.diagnostics[j].Line
+	})
+
+	return src.diagnostics, nil
+}
+
+func (l *Linter) lintDSQL(ctx context.Context, src *lintSource) error {
+	SQL := src.content
+	routeConfig := &option.RouteConfig{}
+	if hint := sanitize.ExtractHint(SQL); hint != "" {
+		offset := src.offsetOf(hint, 0)
+		if err := strictHintToStruct(hint, routeConfig); err != nil {
+			src.reportAt(offset, SeverityError, "invalid route hint: %v", err)
+		}
+
+		SQL = blank(SQL, offset, hint)
+	}
+
+	body := SQL
+	hints := sanitize.ExtractParameterHints(SQL)
+	offset := 0
+	for _, hint := range hints {
+		offset = src.offsetOf(hint.Hint, offset)
+		src.lintParameterHint(offset, hint.Parameter, hint.Hint)
+		SQL = blank(SQL, offset, hint.Hint)
+	}
+
+	if _, err := parser.Parse([]byte(SQL)); err != nil {
+		src.reportAt(-1, SeverityError, "invalid template: %v", err)
+		return nil
+	}
+
+	template, err := NewTemplate(NewParametersIndex(routeConfig, hints.Index()), SQL, view.QueryKind, nil, nil)
+	if err != nil {
+		src.reportAt(-1, SeverityError, "invalid template parameters: %v", err)
+		return nil
+	}
+
+	src.lintReferences(routeConfig, template, body)
+	boundary := GetStmtBoundaries(SQL)
+	if len(boundary) == 0 {
+		return nil
+	}
+
+	parsableSQL, _ := ExtractCondBlock(SQL[boundary[0]:])
+	aQuery, err := sqlparser.ParseQuery(parsableSQL)
+	if err != nil {
+		src.reportAt(boundary[0], SeverityWarning, "couldn't parse SQL: %v", err)
+	}
+
+	if aQuery == nil {
+		return nil
+	}
+
+	return l.lintQuery(ctx, src, aQuery, true)
+}
+
+func (s *lintSource) lintParameterHint(offset int, paramName, hint string) {
+	JSONHint, _ := sanitize.SplitHint(hint)
+	if strings.TrimSpace(JSONHint) == "" {
+		return
+	}
+
+	aParam := &lintParameter{}
+	if err := strictHintToStruct(JSONHint, aParam); err != nil {
+		s.reportAt(offset, SeverityError, "invalid parameter %v hint: %v", paramName, err)
+		return
+	}
+
+	s.lintParameterConfig(offset, paramName, &aParam.ParameterConfig)
+}
+
+func (s *lintSource) lintParameterConfig(offset int, paramName string, config *option.ParameterConfig) {
+	if config.Kind != "" {
+		if err := view.Kind(config.Kind).Validate(); err != nil {
+			s.reportAt(offset, SeverityError, "invalid parameter %v: %v", paramName, err)
+		}
+	}
+
+	if config.Codec != "" {
+		if _, err := registry.Codecs.Lookup(config.Codec); err != nil {
+			s.reportAt(offset, SeverityWarning, "parameter %v uses unregistered codec %v", paramName, config.Codec)
+		}
+	}
+}
+
+func (s *lintSource) lintReferences(routeConfig *option.RouteConfig, template *Template, SQL string) {
+	referenced := map[string]bool{}
+	for _, param := range template.Parameters {
+		referenced[param.Name] = true
+	}
+
+	for paramName := range extractURIParams(routeConfig.URI) {
+		if !referenced[paramName] {
+			s.reportAt(s.offsetOf("{"+paramName+"}", 0), SeverityWarning, "URI parameter %v is not referenced in SQL", paramName)
+		}
+	}
+
+	for name := range routeConfig.Declare {
+		if !referenced[name] && !strings.Contains(SQL, name) {
+			s.reportAt(s.offsetOf(strconv.Quote(name), 0), SeverityWarning, "declared %v is neither parameter nor column", name)
+		}
+	}
+}
+
+func (l *Linter) lintQuery(ctx context.Context, src *lintSource, aQuery *query.Select, top bool) error {
+	if top {
+		src.lintHint(aQuery.From.Comments, &option.ViewConfig{}, "view %v", aQuery.From.Alias)
+	}
+
+	tables := map[string]string{}
+	if err := l.lintFrom(ctx, src, aQuery.From.X, aQuery.From.Alias, tables); err != nil {
+		return err
+	}
+
+	for _, join := range aQuery.Joins {
+		if top {
+			if isParamPredicate(sqlparser.Stringify(join.On.X)) {
+				aParam := &lintParameter{}
+				if src.lintHint(join.Comments, aParam, "parameter view %v", join.Alias) {
+					src.lintParameterConfig(src.offsetOf(join.Comments, 0), join.Alias, &aParam.ParameterConfig)
+				}
+			} else {
+				src.lintHint(join.Comments, &option.ViewConfig{}, "view %v", join.Alias)
+			}
+		}
+
+		if err := l.lintFrom(ctx, src, join.With, join.Alias, tables); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range aQuery.List {
+		if star, ok := item.Expr.(*expr.Star); ok {
+			src.lintHint(star.Comments, &option.OutputConfig{}, "output %v", sqlparser.Stringify(star.X))
+			continue
+		}
+
+		src.lintHint(item.Comments, &view.ColumnConfig{}, "column %v", view.FirstNotEmpty(item.Alias, sqlparser.Stringify(item.Expr)))
+		if err := l.lintColumn(ctx, src, item, tables); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *Linter) lintFrom(ctx context.Context, src *lintSource, x node.Node, alias string, tables map[string]string) error {
+	switch actual := x.(type) {
+	case *expr.Raw:
+		//subquery columns are not resolved, the alias is registered to skip columns it qualifies
+		tables[alias] = ""
+		_, SQL := extractTableSQL(actual)
+		innerSQL, _ := ExtractCondBlock(SQL)
+		innerQuery, err := sqlparser.ParseQuery(innerSQL)
+		if err != nil {
+			src.reportAt(src.offsetOf(SQL, 0), SeverityWarning, "couldn't parse %v SQL: %v", alias, err)
+		}
+
+		if innerQuery == nil {
+			return nil
+		}
+
+		return l.lintQuery(ctx, src, innerQuery, false)
+	case *expr.Selector, *expr.Ident:
+		tableName, _ := extractTableName(actual)
+		tables[view.FirstNotEmpty(alias, tableName)] = strings.Trim(tableName, "`")
+	}
+
+	return nil
+}
+
+func (l *Linter) lintColumn(ctx context.Context, src *lintSource, item *query.Item, tables map[string]string) error {
+	if l.connector == nil || len(tables) == 0 {
+		return nil
+	}
+
+	var ns, columnName string
+	switch actual := item.Expr.(type) {
+	case *expr.Ident:
+		columnName = actual.Name
+	case *expr.Selector:
+		ns, columnName = actual.Name, sqlparser.Stringify(actual.X)
+	default:
+		return nil
+	}
+
+	if ns != "" {
+		if tableName, ok := tables[ns]; !ok || tableName == "" {
+			return nil
+		}
+	}
+
+	columnName = strings.Trim(columnName, "`")
+	for alias, tableName := range tables {
+		if ns == "" && tableName == "" {
+			return nil
+		}
+
+		if ns != "" && ns != alias {
+			continue
+		}
+
+		columns, err := l.tableColumns(ctx, tableName)
+		if err != nil {
+			src.reportAt(src.offsetOf(tableName, 0), SeverityError, "couldn't read table %v columns: %v", tableName, err)
+			return nil
+		}
+
+		if columns[strings.ToLower(columnName)] {
+			return nil
+		}
+	}
+
+	src.reportAt(src.offsetOf(sqlparser.Stringify(item.Expr), 0), SeverityError, "unknown column %v", sqlparser.Stringify(item.Expr))
+	return nil
+}
+
+func (l *Linter) tableColumns(ctx context.Context, tableName string) (map[string]bool, error) {
+	if columns, ok := l.tables[tableName]; ok {
+		return columns, nil
+	}
+
+	if l.db == nil {
+		if err := l.connector.Init(ctx, nil); err != nil {
+			return nil, err
+		}
+
+		db, err := l.connector.DB()
+		if err != nil {
+			return nil, err
+		}
+		l.db = db
+	}
+
+	rows, err := l.db.QueryContext(ctx, "SELECT * FROM "+tableName+" WHERE 1 = 0")
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]bool{}
+	for _, name := range names {
+		columns[strings.ToLower(name)] = true
+	}
+
+	l.tables[tableName] = columns
+	return columns, nil
+}
+
+func (l *Linter) lintRoute(ctx context.Context, src *lintSource) error {
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(src.content), root); err != nil {
+		line := 1
+		if matched := yamlErrorLine.FindStringSubmatch(err.Error()); len(matched) > 1 {
+			line, _ = strconv.Atoi(matched[1])
+		}
+
+		src.report(line, 1, SeverityError, "invalid YAML: %v", err)
+		return nil
+	}
+
+	if len(root.Content) == 0 {
+		return nil
+	}
+
+	src.lintFields(root.Content[0], reflect.TypeOf(router.Resource{}))
+	resource, err := router.LoadResource(ctx, l.fs, src.URL, false)
+	if err != nil {
+		src.reportAt(-1, SeverityError, "invalid route resource: %v", err)
+		return nil
+	}
+
+	for _, aView := range resource.Resource.Views {
+		src.lintView(resource.Resource, aView)
+	}
+
+	return nil
+}
+
+func (s *lintSource) lintView(resource *view.Resource, aView *view.View) {
+	if len(aView.Columns) > 0 {
+		for columnName := range aView.ColumnsConfig {
+			if !hasColumn(aView.Columns, columnName) {
+				s.reportAt(s.offsetOf(columnName+":", 0), SeverityError, "view %v ColumnsConfig refers to unknown column %v", aView.Name, columnName)
+			}
+		}
+	}
+
+	if aView.Template == nil || aView.Template.Source == "" {
+		return
+	}
+
+	declared := map[string]bool{}
+	for _, parameter := range append(resource.Parameters, aView.Template.Parameters...) {
+		name := view.FirstNotEmpty(parameter.Name, parameter.Ref)
+		declared[name] = true
+		declared[strings.Split(name, ".")[0]] = true
+		if parameter.In != nil && parameter.In.Kind != "" {
+			if err := parameter.In.Kind.Validate(); err != nil {
+				s.reportAt(s.offsetOf("Name: "+parameter.Name, 0), SeverityError, "invalid parameter %v: %v", parameter.Name, err)
+			}
+		}
+	}
+
+	template, err := NewTemplate(NewParametersIndex(nil, nil), aView.Template.Source, view.QueryKind, nil, nil)
+	if err != nil {
+		s.reportAt(s.offsetOf(aView.Template.Source, 0), SeverityError, "invalid view %v template: %v", aView.Name, err)
+		return
+	}
+
+	for _, parameter := range template.Parameters {
+		if !declared[parameter.Name] {
+			s.reportAt(s.offsetOf("$"+parameter.Name, 0), SeverityError, "view %v template refers to undeclared parameter %v", aView.Name, parameter.Name)
+		}
+	}
+}
+
+func hasColumn(columns []*view.Column, name string) bool {
+	for _, column := range columns {
+		if strings.EqualFold(column.Name, name) || strings.EqualFold(column.DatabaseColumn, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//lintFields reports YAML keys without matching struct field, as these are silently ignored when resource is loaded
+func (s *lintSource) lintFields(node *yaml.Node, rType reflect.Type) {
+	for rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+
+	switch rType.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+
+		fields := map[string]reflect.Type{}
+		indexFields(rType, fields)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			fieldType, ok := fields[strings.ToLower(key.Value)]
+			if !ok {
+				s.report(key.Line, key.Column, SeverityError, "unknown field %v in %v", key.Value, rType.String())
+				continue
+			}
+
+			s.lintFields(node.Content[i+1], fieldType)
+		}
+	case reflect.Slice, reflect.Array:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+
+		for _, item := range node.Content {
+			s.lintFields(item, rType.Elem())
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+
+		for i := 1; i < len(node.Content); i += 2 {
+			s.lintFields(node.Content[i], rType.Elem())
+		}
+	}
+}
+
+func indexFields(rType reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < rType.NumField(); i++ {
+		field := rType.Field(i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && fieldType.Kind() == reflect.Struct {
+			indexFields(fieldType, fields)
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fields[strings.ToLower(field.Name)] = field.Type
+		for _, tag := range []string{field.Tag.Get("json"), field.Tag.Get("yaml")} {
+			if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+				fields[strings.ToLower(name)] = field.Type
+			}
+		}
+	}
+}
+
+//lintHint reports hint that does not decode into aStructPtr, returns true if hint was decoded
+func (s *lintSource) lintHint(hint string, aStructPtr interface{}, subject string, args ...interface{}) bool {
+	if strings.TrimSpace(hint) == "" {
+		return false
+	}
+
+	if err := strictHintToStruct(hint, aStructPtr); err != nil {
+		s.reportAt(s.offsetOf(hint, 0), SeverityError, "invalid %v hint: %v", fmt.Sprintf(subject, args...), err)
+		return false
+	}
+
+	return true
+}
+
+func (s *lintSource) offsetOf(text string, from int) int {
+	text = strings.TrimSpace(text)
+	if text == "" || from < 0 || from > len(s.content) {
+		return -1
+	}
+
+	index := strings.Index(s.content[from:], text)
+	if index == -1 {
+		return -1
+	}
+
+	return from + index
+}
+
+func (s *lintSource) reportAt(offset int, severity string, format string, args ...interface{}) {
+	if offset < 0 || offset > len(s.content) {
+		s.report(1, 1, severity, format, args...)
+		return
+	}
+
+	prefix := s.content[:offset]
+	s.report(strings.Count(prefix, "\n")+1, offset-strings.LastIndex(prefix, "\n"), severity, format, args...)
+}
+
+func (s *lintSource) report(line, column int, severity string, format string, args ...interface{}) {
+	s.diagnostics = append(s.diagnostics, &Diagnostic{URL: s.URL, Line: line, Column: column, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+//strictHintToStruct decodes hint like hintToStruct, but reports fields not defined by aStructPtr
+func strictHintToStruct(encoded string, aStructPtr interface{}) error {
+	encoded = strings.ReplaceAll(encoded, "/*", "")
+	encoded = strings.ReplaceAll(encoded, "*/", "")
+	decoder := json.NewDecoder(bytes.NewReader([]byte(encoded)))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(aStructPtr)
+}
+
+//blank replaces text at offset with spaces, preserving positions of the remaining content
+func blank(content string, offset int, text string) string {
+	if offset < 0 || offset+len(text) > len(content) || content[offset:offset+len(text)] != text {
+		return strings.Replace(content, text, "", 1)
+	}
+
+	return content[:offset] + strings.Repeat(" ", len(text)) + content[offset+len(text):]
+}
+
+func lint(options *Options, logger io.Writer) error {
+	var connector *view.Connector
+	if options.DSN != "" || len(options.Connects) > 0 {
+		options.Connector.Init()
+		connector = options.Connector.New()
+	}
+
+	linter := NewLinter(connector)
+	errors := 0
+	for _, URL := range options.LintURLs {
+		diagnostics, err := linter.Lint(context.Background(), normalizeURL(URL))
+		if err != nil {
+			return err
+		}
+
+		for _, diagnostic := range diagnostics {
+			_, _ = logger.Write([]byte(diagnostic.String() + "\n"))
+		}
+
+		errors += diagnostics.Errors()
+	}
+
+	if errors > 0 {
+		return fmt.Errorf("lint failed with %v error(s)", errors)
+	}
+
+	return nil
+}
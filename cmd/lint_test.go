@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/view"
+	"github.com/viant/toolbox"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestLinter_Lint(t *testing.T) {
+	dbLocation := "/tmp/datly_lint_test.db"
+	_ = os.Remove(dbLocation)
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE events (id INTEGER PRIMARY KEY, quantity DECIMAL(7, 2), event_type_id INTEGER); " +
+		"CREATE TABLE event_types (id INTEGER PRIMARY KEY, name TEXT)")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	testLocation := toolbox.CallerDirectory(3)
+	useCases := []struct {
+		description string
+		URL         string
+		connector   *view.Connector
+		expect      []string
+	}{
+		{
+			description: "valid DSQL",
+			URL:         "valid.sql",
+			connector:   &view.Connector{Name: "dev", Driver: "sqlite3", DSN: dbLocation},
+		},
+		{
+			description: "invalid DSQL",
+			URL:         "invalid.sql",
+			connector:   &view.Connector{Name: "dev", Driver: "sqlite3", DSN: dbLocation},
+			expect: []string{
+				`1:1: error: invalid route hint: json: unknown field "Methd"`,
+				`1:19: warning: URI parameter eventID is not referenced in SQL`,
+				`5:23: error: unknown column e.qty`,
+				`8:39: error: invalid parameter quantity: unsupported location Kind querystring`,
+				`9:15: error: invalid view events hint: json: unknown field "MetaColumn"`,
+			},
+		},
+		{
+			description: "columns of subquery and table aliases",
+			URL:         "subquery.sql",
+			connector:   &view.Connector{Name: "dev", Driver: "sqlite3", DSN: dbLocation},
+			expect: []string{
+				`6:8: error: unknown column t.label`,
+			},
+		},
+		{
+			description: "route YAML without connector",
+			URL:         "route.yaml",
+			expect: []string{
+				`7:7: error: unknown field ViewPrefix in router.Index`,
+				`18:9: error: view events ColumnsConfig refers to unknown column qty`,
+				`21:68: error: view events template refers to undeclared parameter quantity`,
+			},
+		},
+	}
+
+	for _, useCase := range useCases {
+		URL := path.Join(testLocation, "testdata", "lint", useCase.URL)
+		diagnostics, err := NewLinter(useCase.connector).Lint(context.Background(), URL)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		var actual []string
+		for _, diagnostic := range diagnostics {
+			actual = append(actual, diagnostic.String()[len(URL)+1:])
+		}
+
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/viant/afs"
 	"github.com/viant/afs/file"
 	"github.com/viant/afs/url"
+	"github.com/viant/datly/router/openapi3"
 	"github.com/viant/datly/view"
 	"github.com/viant/scy"
 	"strings"
@@ -33,8 +34,32 @@ type (
 		Connector
 		CacheWarmup
 		Prepare
-		OpenApiURL string `short:"o" long:"openapi"`
-		Version    bool   `short:"v" long:"version"  description:"build version"`
+		Lint
+		Test
+		Reverse
+		Scaffold
+		OpenAPI
+		Client
+		Drift
+		Version bool `short:"v" long:"version"  description:"build version"`
+	}
+
+	OpenAPI struct {
+		OpenApiURL     string `short:"o" long:"openapi"`
+		OpenApiTitle   string `short:"K" long:"otitle" description:"OpenAPI info title"`
+		OpenApiVersion string `short:"Z" long:"oversion" description:"OpenAPI info version"`
+	}
+
+	Client struct {
+		GoClientURL     string `short:"H" long:"goclient" description:"generated Go client location"`
+		GoClientPackage string `short:"P" long:"gopkg" description:"generated Go client package, client by default"`
+		TypeScriptURL   string `short:"M" long:"tsclient" description:"generated TypeScript client location"`
+	}
+
+	Drift struct {
+		DriftURLs  []string `short:"W" long:"drift" description:"route YAML location to compare with database schema"`
+		DriftFix   string   `short:"Q" long:"dfix" description:"fix drifted routes with patch|regenerate"`
+		DriftCheck bool     `short:"O" long:"dcheck" description:"report schema drifts of routes on startup"`
 	}
 
 	CacheWarmup struct {
@@ -54,6 +79,28 @@ type (
 		Location string `short:"X" long:"sqlx" description:"SQLX (extension for relation) location" `
 	}
 
+	Lint struct {
+		LintURLs []string `short:"L" long:"lint" description:"DSQL or route YAML location to lint"`
+	}
+
+	Test struct {
+		TestURLs []string `short:"t" long:"test" description:"test suite YAML location"`
+		JUnitURL string   `short:"J" long:"junit" description:"JUnit XML report location"`
+	}
+
+	Reverse struct {
+		ReverseURL    string `short:"R" long:"reverse" description:"route YAML location to convert into DSQL"`
+		ReverseDest   string `short:"S" long:"rdest" description:"generated DSQL destination folder"`
+		ReverseVerify bool   `short:"Y" long:"rverify" description:"build generated DSQL and compare it with route YAML"`
+	}
+
+	Scaffold struct {
+		ScaffoldTable     string   `short:"T" long:"scaffold" description:"table to generate GET, POST, PUT, PATCH and DELETE routes for"`
+		ScaffoldURI       string   `short:"U" long:"suri" description:"scaffold routes URI, table name by default"`
+		ScaffoldDepth     int      `short:"F" long:"fkdepth" description:"number of foreign key levels to follow"`
+		ScaffoldRelations []string `short:"I" long:"srel" description:"tables to follow by foreign key, all when empty"`
+	}
+
 	Prepare struct {
 		PrepareRule string `short:"G" long:"generate" description:"prepare rule for patch|post|put|delete"`
 	}
@@ -85,6 +132,14 @@ func (o *Options) Init() {
 	o.Connector.Init()
 }
 
+//Info returns OpenAPI info, title defaults to Datly
+func (o *OpenAPI) Info() openapi3.Info {
+	return openapi3.Info{
+		Title:   view.FirstNotEmpty(o.OpenApiTitle, "Datly"),
+		Version: view.FirstNotEmpty(o.OpenApiVersion, "1.0"),
+	}
+}
+
 // MatchConnector returns matcher or default connector
 func (c *Connector) MatchConnector(name string) *view.Connector {
 	if name == "" {
@@ -631,7 +631,7 @@ func (sb *stmtBuilder) iterateOverHints(metadata *inputMetadata, iterator func(*
 	}
 
 	for _, relation := range metadata.relations {
-		if err := iterator(relation); err != nil {
+		if err := sb.iterateOverHints(relation, iterator); err != nil {
 			return err
 		}
 	}
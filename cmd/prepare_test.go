@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/view"
+	"strings"
+	"testing"
+)
+
+func TestStmtBuilder_appendHintsWithRelations(t *testing.T) {
+	newMetadata := func(name string, cardinality view.Cardinality, relations ...*inputMetadata) *inputMetadata {
+		config := &viewConfig{}
+		config.outputConfig.Cardinality = cardinality
+		return &inputMetadata{paramName: name, bodyHolder: name, sqlName: name + "DBRecords", sql: "SELECT * FROM " + name, config: config, relations: relations}
+	}
+
+	useCases := []struct {
+		description string
+		metadata    *inputMetadata
+		expectHints string
+		expectSQL   string
+	}{
+		{
+			description: "no relations",
+			metadata:    newMetadata("Vendor", view.One),
+			expectHints: `
+#set($_ = $Vendor<*Vendor>(body/Vendor))`,
+			expectSQL: `
+#set($_ = $VendorDBRecords /* {"Required":false} SELECT * FROM Vendor */
+)`,
+		},
+		{
+			description: "single level relations",
+			metadata:    newMetadata("Vendor", view.Many, newMetadata("Products", view.Many), newMetadata("Address", view.One)),
+			expectHints: `
+#set($_ = $Vendor<[]*Vendor>(body/Vendor))
+#set($_ = $Products<[]*Products>(body/Products))
+#set($_ = $Address<*Address>(body/Address))`,
+			expectSQL: `
+#set($_ = $VendorDBRecords /* {"Required":false} SELECT * FROM Vendor */
+)
+#set($_ = $ProductsDBRecords /* {"Required":false} SELECT * FROM Products */
+)
+#set($_ = $AddressDBRecords /* {"Required":false} SELECT * FROM Address */
+)`,
+		},
+		{
+			description: "nested relations",
+			metadata:    newMetadata("Vendor", view.One, newMetadata("Products", view.Many, newMetadata("Tags", view.Many)), newMetadata("Address", view.One)),
+			expectHints: `
+#set($_ = $Vendor<*Vendor>(body/Vendor))
+#set($_ = $Products<[]*Products>(body/Products))
+#set($_ = $Tags<[]*Tags>(body/Tags))
+#set($_ = $Address<*Address>(body/Address))`,
+			expectSQL: `
+#set($_ = $VendorDBRecords /* {"Required":false} SELECT * FROM Vendor */
+)
+#set($_ = $ProductsDBRecords /* {"Required":false} SELECT * FROM Products */
+)
+#set($_ = $TagsDBRecords /* {"Required":false} SELECT * FROM Tags */
+)
+#set($_ = $AddressDBRecords /* {"Required":false} SELECT * FROM Address */
+)`,
+		},
+	}
+
+	for _, useCase := range useCases {
+		hints := &strings.Builder{}
+		assert.Nil(t, newStmtBuilder(hints, useCase.metadata).appendHintsWithRelations(), useCase.description)
+		assert.Equal(t, useCase.expectHints, hints.String(), useCase.description)
+
+		SQL := &strings.Builder{}
+		assert.Nil(t, newStmtBuilder(SQL, useCase.metadata).appendSQLWithRelations(), useCase.description)
+		assert.Equal(t, useCase.expectSQL, SQL.String(), useCase.description)
+	}
+}
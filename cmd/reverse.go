@@ -0,0 +1,988 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/url"
+	"github.com/viant/datly/cmd/option"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/shared"
+	"github.com/viant/datly/template/columns"
+	"github.com/viant/datly/template/sanitize"
+	"github.com/viant/datly/view"
+	"github.com/viant/parsly"
+	"github.com/viant/toolbox/format"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+const reverseAPIPrefix = "/v1/api/"
+
+type (
+	//Reverser generates DSQL from route YAML, so that hand written routes can be migrated into the DSQL workflow
+	Reverser struct {
+		fs       afs.Service
+		URL      string
+		resource *router.Resource
+		index    *reverseIndex
+	}
+
+	//ReverseResult represents DSQL generated for a route
+	ReverseResult struct {
+		Name     string //main view name, used as DSQL file name, builder derives view name from it
+		Route    *router.Route
+		DSQL     string
+		Warnings []string
+	}
+
+	reverseIndex struct {
+		views  map[string]*view.View
+		params map[string]*view.Parameter
+	}
+
+	reverseBuilder struct {
+		*Reverser
+		result      *ReverseResult
+		routeConfig *option.RouteConfig
+		excluded    map[string][]string
+		connector   string
+	}
+)
+
+//NewReverser creates Reverser
+func NewReverser(fs afs.Service) *Reverser {
+	return &Reverser{fs: fs}
+}
+
+//Reverse generates DSQL for every route defined in route YAML
+func (r *Reverser) Reverse(ctx context.Context, URL string) ([]*ReverseResult, error) {
+	resource, err := router.LoadResource(ctx, r.fs, URL, false)
+	if err != nil {
+		return nil, err
+	}
+
+	r.URL = URL
+	r.resource = resource
+	r.index = newReverseIndex(resource.Resource)
+
+	var results []*ReverseResult
+	names := map[string]int{}
+	for _, route := range resource.Routes {
+		result, err := r.reverseRoute(ctx, route)
+		if err != nil {
+			return nil, err
+		}
+
+		if counter := names[strings.ToLower(result.Name)]; counter > 0 {
+			names[strings.ToLower(result.Name)] = counter + 1
+			result.Name = fmt.Sprintf("%v%v", result.Name, counter)
+		} else {
+			names[strings.ToLower(result.Name)] = 1
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *Reverser) reverseRoute(ctx context.Context, route *router.Route) (*ReverseResult, error) {
+	mainView := r.index.view(route.View)
+	if mainView == nil || mainView.Name == "" {
+		return nil, fmt.Errorf("route %v %v: view was not found", route.Method, route.URI)
+	}
+
+	b := &reverseBuilder{
+		Reverser:  r,
+		result:    &ReverseResult{Name: mainView.Name, Route: route},
+		excluded:  map[string][]string{},
+		connector: connectorName(mainView.Connector),
+	}
+
+	b.routeConfig = b.buildRouteConfig(route)
+	for _, aPath := range route.Exclude {
+		holder, field := "", aPath
+		if index := strings.LastIndex(aPath, "."); index != -1 {
+			holder, field = aPath[:index], aPath[index+1:]
+		}
+		b.excluded[holder] = append(b.excluded[holder], field)
+	}
+
+	b.warnRoute(route)
+
+	sb := &strings.Builder{}
+	if mainView.Mode == view.SQLExecMode {
+		SQL, err := b.viewSQL(ctx, mainView)
+		if err != nil {
+			return nil, err
+		}
+
+		sb.WriteString(SQL)
+	} else if err := b.appendQuery(ctx, sb, mainView, mainView.Name, "", "", b.outputHint(route)); err != nil {
+		return nil, err
+	}
+
+	for _, holder := range sortedExcludedKeys(b.excluded) {
+		b.warn("route %v Exclude %v has no DSQL equivalent and was skipped", holder, b.excluded[holder])
+	}
+
+	routeHint, err := marshalHint(b.routeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	b.result.DSQL = fmt.Sprintf("/* %v */\n\n%v\n", routeHint, strings.TrimSpace(sb.String()))
+	return b.result, nil
+}
+
+func (b *reverseBuilder) buildRouteConfig(route *router.Route) *option.RouteConfig {
+	URI := route.URI
+	switch {
+	case strings.HasPrefix(URI, reverseAPIPrefix+folderDev+"/"):
+		URI = URI[len(reverseAPIPrefix+folderDev+"/"):]
+	case strings.HasPrefix(URI, reverseAPIPrefix):
+		URI = URI[len(reverseAPIPrefix):]
+		b.warn("route %v will be published as %v", route.URI, reverseAPIPrefix+folderDev+"/"+URI)
+	default:
+		URI = strings.TrimPrefix(URI, "/")
+		b.warn("route %v will be published as %v", route.URI, reverseAPIPrefix+folderDev+"/"+URI)
+	}
+
+	result := &option.RouteConfig{
+		URI:        URI,
+		DateFormat: route.DateFormat,
+		CSV:        route.CSV,
+		CaseFormat: string(route.CaseFormat),
+	}
+
+	if route.Method != "" && !strings.EqualFold(route.Method, "GET") {
+		result.Method = route.Method
+	}
+
+	if result.CaseFormat == "" {
+		result.CaseFormat = string(view.UpperCamel)
+	}
+
+	if route.RequestBodySchema != nil && route.RequestBodySchema.DataType != "" {
+		result.RequestBody = &option.BodyConfig{DataType: route.RequestBodySchema.DataType}
+	}
+
+	if route.ResponseBody != nil && route.ResponseBody.StateValue != "" {
+		result.ResponseBody = &option.ResponseBodyConfig{From: route.ResponseBody.StateValue}
+	}
+
+	return result
+}
+
+func (b *reverseBuilder) warnRoute(route *router.Route) {
+	unsupported := map[string]bool{
+		"Auth":        route.Auth != nil,
+		"APIKey":      route.APIKey != nil,
+		"Signature":   route.Signature != nil,
+		"Shadow":      route.Shadow != nil,
+		"Version":     route.Version != "",
+		"Cache":       route.Cache != nil,
+		"Transforms":  len(route.Transforms) > 0,
+		"Invalidates": len(route.Invalidates) > 0,
+	}
+
+	for _, name := range sortedBoolKeys(unsupported) {
+		if unsupported[name] {
+			b.warn("route %v setting has no DSQL equivalent and was skipped", name)
+		}
+	}
+}
+
+func (b *reverseBuilder) outputHint(route *router.Route) string {
+	output := map[string]interface{}{}
+	if route.Style == router.ComprehensiveStyle || route.ResponseField != "" {
+		output["Style"] = router.ComprehensiveStyle
+		if route.ResponseField != "" {
+			output["ResponseField"] = route.ResponseField
+		}
+	}
+
+	if route.Cardinality == view.One {
+		output["Cardinality"] = view.One
+	}
+
+	hint, _ := marshalHint(output)
+	return hint
+}
+
+//appendQuery writes view with its relations as SELECT alias.*, rel.* FROM (view SQL) alias JOIN (rel SQL) rel ON ...
+func (b *reverseBuilder) appendQuery(ctx context.Context, sb *strings.Builder, aView *view.View, alias, holderPath, indent, outputHint string) error {
+	relations := b.relations(aView)
+	sb.WriteString("SELECT " + alias + ".*")
+	if fields, ok := b.excluded[holderPath]; ok && holderPath == "" {
+		sb.WriteString(" EXCEPT " + strings.Join(fields, ", "))
+		delete(b.excluded, holderPath)
+	}
+
+	if outputHint != "" {
+		sb.WriteString(" /* " + outputHint + " */")
+	}
+
+	for _, relation := range relations {
+		sb.WriteString(",\n" + indent + "       ")
+		sb.WriteString(relationAlias(relation) + ".*")
+	}
+
+	SQL, err := b.viewSQL(ctx, aView)
+	if err != nil {
+		return err
+	}
+
+	sb.WriteString("\n" + indent + "FROM ")
+	appendSubquery(sb, SQL, indent)
+	sb.WriteString(" " + alias)
+	if holderPath == "" {
+		if hint := b.viewHint(aView); hint != "" {
+			sb.WriteString(" /* " + hint + " */")
+		}
+	}
+
+	for _, relation := range relations {
+		relView := b.index.view(&relation.Of.View)
+		relAlias := relationAlias(relation)
+		sb.WriteString("\n" + indent + "JOIN ")
+		if len(b.relations(relView)) > 0 {
+			nested := &strings.Builder{}
+			if err = b.appendQuery(ctx, nested, relView, relAlias, joinHolder(holderPath, relation.Holder), indent+"    ", ""); err != nil {
+				return err
+			}
+			appendSubquery(sb, nested.String(), indent)
+		} else {
+			relSQL, err := b.viewSQL(ctx, relView)
+			if err != nil {
+				return err
+			}
+			appendSubquery(sb, relSQL, indent)
+		}
+
+		sb.WriteString(" " + relAlias)
+		if hint := b.viewHint(relView); hint != "" {
+			sb.WriteString(" /* " + hint + " */")
+		}
+
+		sb.WriteString(fmt.Sprintf(" ON %v.%v = %v.%v", alias, view.FirstNotEmpty(relation.Column, relation.Field), relAlias, view.FirstNotEmpty(relation.Of.Column, relation.Of.Field)))
+		if relation.Cardinality == view.One {
+			sb.WriteString(" AND 1 = 1")
+		}
+	}
+
+	return nil
+}
+
+func (b *reverseBuilder) relations(aView *view.View) []*view.Relation {
+	var result []*view.Relation
+	for _, relation := range aView.With {
+		if relation.Of == nil {
+			b.warn("view %v relation %v without Of was skipped", aView.Name, relation.Name)
+			continue
+		}
+
+		if relation.Holder == "" {
+			b.warn("view %v relation %v without Holder was skipped", aView.Name, relation.Name)
+			continue
+		}
+
+		result = append(result, relation)
+	}
+
+	return result
+}
+
+func (b *reverseBuilder) viewHint(aView *view.View) string {
+	config := &option.ViewConfig{
+		Self:       aView.SelfReference,
+		Cache:      aView.Cache,
+		Selector:   aView.Selector,
+		AllowNulls: aView.AllowNulls,
+	}
+
+	if config.Selector == nil {
+		config.Selector = &view.Config{} //prevents builder default limit and constraints
+	}
+
+	if name := connectorName(aView.Connector); name != "" && (name != b.connector || aView.Name == b.result.Name) {
+		config.Connector = name
+	}
+
+	hint, err := marshalHint(config)
+	if err != nil {
+		b.warn("view %v settings were skipped: %v", aView.Name, err)
+		return ""
+	}
+
+	return hint
+}
+
+//viewSQL returns view SQL with parameter hints
+func (b *reverseBuilder) viewSQL(ctx context.Context, aView *view.View) (string, error) {
+	SQL, err := b.viewSource(ctx, aView)
+	if err != nil {
+		return "", err
+	}
+
+	b.warnView(aView)
+	return b.withParameterHints(ctx, aView, SQL)
+}
+
+func (b *reverseBuilder) viewSource(ctx context.Context, aView *view.View) (string, error) {
+	var SQL string
+	var err error
+	switch {
+	case aView.Template != nil && aView.Template.Source != "":
+		SQL = aView.Template.Source
+	case aView.Template != nil && aView.Template.SourceURL != "":
+		SQL, err = b.download(ctx, aView.Template.SourceURL)
+	case aView.From != "":
+		SQL = aView.From
+	case aView.FromURL != "":
+		SQL, err = b.download(ctx, aView.FromURL)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("view %v: %w", aView.Name, err)
+	}
+
+	SQL = strings.TrimSpace(SQL)
+	if SQL != "" && !columns.CanBeTableName(SQL) {
+		if len(aView.ColumnsConfig) > 0 {
+			b.warn("view %v ColumnsConfig requires column hints in view SQL and was skipped", aView.Name)
+		}
+		return SQL, nil
+	}
+
+	table := view.FirstNotEmpty(SQL, aView.Table)
+	if table == "" {
+		return "", fmt.Errorf("view %v: neither table nor SQL was specified", aView.Name)
+	}
+
+	if len(aView.ColumnsConfig) == 0 {
+		return "SELECT * FROM " + table + " t", nil
+	}
+
+	if len(aView.Columns) == 0 {
+		b.warn("view %v ColumnsConfig requires view Columns and was skipped", aView.Name)
+		return "SELECT * FROM " + table + " t", nil
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString("SELECT ")
+	for i, column := range aView.Columns {
+		if i > 0 {
+			sb.WriteString(",\n       ")
+		}
+
+		sb.WriteString(column.Name)
+		if config := columnConfig(aView.ColumnsConfig, column.Name); config != nil {
+			hint, err := marshalHint(config)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(" /* " + hint + " */")
+		}
+	}
+
+	sb.WriteString("\nFROM " + table + " t")
+	return sb.String(), nil
+}
+
+func (b *reverseBuilder) warnView(aView *view.View) {
+	unsupported := map[string]bool{
+		"Criteria":     aView.Criteria != "",
+		"Exclude":      len(aView.Exclude) > 0,
+		"Batch":        aView.Batch != nil,
+		"RowFilters":   len(aView.RowFilters) > 0,
+		"TemplateMeta": aView.Template != nil && aView.Template.Meta != nil,
+	}
+
+	for _, name := range sortedBoolKeys(unsupported) {
+		if unsupported[name] {
+			b.warn("view %v %v setting has no DSQL equivalent and was skipped", aView.Name, name)
+		}
+	}
+}
+
+func (b *reverseBuilder) download(ctx context.Context, URL string) (string, error) {
+	if url.IsRelative(URL) {
+		baseURL, _ := url.Split(b.URL, file.Scheme)
+		URL = url.Join(baseURL, URL)
+	}
+
+	data, err := b.fs.DownloadWithURL(ctx, URL)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+//withParameterHints places parameter hint right after the first parameter occurrence in SQL
+func (b *reverseBuilder) withParameterHints(ctx context.Context, aView *view.View, SQL string) (string, error) {
+	if aView.Template == nil {
+		return SQL, nil
+	}
+
+	positions := parameterPositions(SQL)
+	type insertion struct {
+		pos  int
+		hint string
+	}
+
+	var insertions []*insertion
+	for _, param := range aView.Template.Parameters {
+		param = b.index.parameter(param)
+		if param.In != nil && param.In.Kind == view.LiteralKind {
+			if b.routeConfig.Const == nil {
+				b.routeConfig.Const = map[string]interface{}{}
+			}
+			b.routeConfig.Const[param.Name] = param.Const
+			continue
+		}
+
+		if strings.Contains(param.Name, ".") {
+			b.warn("view %v parameter %v has no DSQL equivalent and was skipped", aView.Name, param.Name)
+			continue
+		}
+
+		pos, ok := positions[param.Name]
+		if !ok {
+			b.warn("view %v parameter %v is not referenced in view SQL and was skipped", aView.Name, param.Name)
+			continue
+		}
+
+		if pos == -1 {
+			continue
+		}
+
+		hint, err := b.parameterHint(ctx, param)
+		if err != nil {
+			return "", err
+		}
+
+		insertions = append(insertions, &insertion{pos: pos, hint: hint})
+	}
+
+	sort.Slice(insertions, func(i, j int) bool {
+		return insertions[i].pos > insertions[j].pos
+	})
+
+	for _, item := range insertions {
+		SQL = SQL[:item.pos] + " /* " + item.hint + " */" + SQL[item.pos:]
+	}
+
+	return SQL, nil
+}
+
+func (b *reverseBuilder) parameterHint(ctx context.Context, param *view.Parameter) (string, error) {
+	config := &option.ParameterConfig{Required: param.Required}
+	if param.PresenceName != "" && param.PresenceName != param.Name {
+		config.Name = param.PresenceName
+	}
+
+	if param.Schema != nil {
+		config.DataType = param.Schema.DataType
+		if param.Schema.Cardinality == view.Many {
+			config.Cardinality = view.Many
+		}
+	}
+
+	config.DataType = view.FirstNotEmpty(config.DataType, param.DataType)
+	if codec := view.FirstNotEmpty(codecName(param.Output), codecName(param.Codec)); codec != "" {
+		config.Codec = codec
+	}
+
+	var SQL string
+	if param.In != nil {
+		if param.In.Kind == view.KindDataView {
+			dataView := b.index.view(&view.View{Reference: shared.Reference{Ref: param.In.Name}})
+			if dataView == nil || dataView.Name == "" {
+				return "", fmt.Errorf("parameter %v: data view %v was not found", param.Name, param.In.Name)
+			}
+
+			source, err := b.viewSource(ctx, dataView)
+			if err != nil {
+				return "", err
+			}
+
+			if strings.Contains(source, "*/") {
+				return "", fmt.Errorf("parameter %v: data view %v SQL can not contain comments", param.Name, dataView.Name)
+			}
+
+			b.warnView(dataView)
+			if dataView.Template != nil && len(dataView.Template.Parameters) > 0 {
+				b.warn("data view %v parameters have no DSQL hint placement and were skipped", dataView.Name)
+			}
+
+			SQL = " " + source
+			if name := connectorName(dataView.Connector); name != "" && name != b.connector {
+				config.Connector = name
+			}
+		} else {
+			config.Kind = string(param.In.Kind)
+			if param.In.Name != "" && param.In.Name != param.Name {
+				target := param.In.Name
+				config.Target = &target
+			}
+		}
+	}
+
+	hint, err := marshalHint(config)
+	if err != nil {
+		return "", err
+	}
+
+	return hint + SQL, nil
+}
+
+func (b *reverseBuilder) warn(template string, args ...interface{}) {
+	b.result.Warnings = append(b.result.Warnings, fmt.Sprintf(template, args...))
+}
+
+//parameterPositions returns position right after the first occurrence of each parameter, -1 if occurrence already has a hint
+func parameterPositions(SQL string) map[string]int {
+	result := map[string]int{}
+	presence := map[string]int{}
+	cursor := parsly.NewCursor("", []byte(SQL), 0)
+	matcher := sanitize.NewParamMatcher()
+	for cursor.Pos < cursor.InputSize {
+		selector, pos := matcher.TryMatchParam(cursor)
+		if pos == -1 {
+			cursor.Pos++
+			continue
+		}
+
+		prefix, holder := sanitize.GetHolderName(selector)
+		end := cursor.Pos
+		if matched := cursor.MatchAfterOptional(whitespaceMatcher, commentMatcher); matched.Code == commentToken {
+			end = -1
+		}
+
+		if prefix == "Has" {
+			if _, ok := presence[holder]; !ok {
+				presence[holder] = end
+			}
+			continue
+		}
+
+		if _, ok := result[holder]; !ok {
+			result[holder] = end
+		}
+	}
+
+	for holder, pos := range presence {
+		if _, ok := result[holder]; !ok {
+			result[holder] = pos
+		}
+	}
+
+	return result
+}
+
+func appendSubquery(sb *strings.Builder, SQL string, indent string) {
+	if !strings.Contains(SQL, "\n") {
+		sb.WriteString("(" + SQL + ")")
+		return
+	}
+
+	sb.WriteString("(\n" + indent + "    ")
+	sb.WriteString(strings.ReplaceAll(SQL, "\n", "\n"+indent+"    "))
+	sb.WriteString("\n" + indent + ")")
+}
+
+func relationAlias(relation *view.Relation) string {
+	caseFormat, err := format.NewCase(view.DetectCase(relation.Holder))
+	if err != nil {
+		return relation.Holder
+	}
+
+	return caseFormat.Format(relation.Holder, format.CaseLowerCamel)
+}
+
+func joinHolder(holderPath, holder string) string {
+	if holderPath == "" {
+		return holder
+	}
+
+	return holderPath + "." + holder
+}
+
+func connectorName(connector *view.Connector) string {
+	if connector == nil {
+		return ""
+	}
+
+	return view.FirstNotEmpty(connector.Ref, connector.Name)
+}
+
+func codecName(codec *view.Codec) string {
+	if codec == nil {
+		return ""
+	}
+
+	return view.FirstNotEmpty(codec.Ref, codec.Name)
+}
+
+func columnConfig(configs map[string]*view.ColumnConfig, name string) *view.ColumnConfig {
+	for key, config := range configs {
+		if strings.EqualFold(key, name) {
+			return config
+		}
+	}
+
+	return nil
+}
+
+func marshalHint(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	if string(data) == "{}" {
+		return "", nil
+	}
+
+	return string(data), nil
+}
+
+func sortedExcludedKeys(aMap map[string][]string) []string {
+	result := make([]string, 0, len(aMap))
+	for key := range aMap {
+		result = append(result, key)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+func sortedBoolKeys(aMap map[string]bool) []string {
+	result := make([]string, 0, len(aMap))
+	for key := range aMap {
+		result = append(result, key)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+func newReverseIndex(resource *view.Resource) *reverseIndex {
+	result := &reverseIndex{views: map[string]*view.View{}, params: map[string]*view.Parameter{}}
+	if resource == nil {
+		return result
+	}
+
+	for _, aView := range resource.Views {
+		result.views[aView.Name] = aView
+	}
+
+	for _, param := range resource.Parameters {
+		result.params[param.Name] = param
+	}
+
+	return result
+}
+
+//view returns view with Ref chain resolved, locally defined settings take precedence over referenced view
+func (i *reverseIndex) view(aView *view.View) *view.View {
+	return i.resolveView(aView, map[string]bool{})
+}
+
+func (i *reverseIndex) resolveView(aView *view.View, visited map[string]bool) *view.View {
+	if aView == nil || aView.Ref == "" || visited[aView.Ref] {
+		return aView
+	}
+
+	visited[aView.Ref] = true
+	ref, ok := i.views[aView.Ref]
+	if !ok {
+		return aView
+	}
+
+	ref = i.resolveView(ref, visited)
+	result := &view.View{
+		Mode:          ref.Mode,
+		Connector:     ref.Connector,
+		Name:          ref.Name,
+		Table:         ref.Table,
+		From:          ref.From,
+		FromURL:       ref.FromURL,
+		Exclude:       ref.Exclude,
+		Columns:       ref.Columns,
+		Criteria:      ref.Criteria,
+		Selector:      ref.Selector,
+		Template:      ref.Template,
+		With:          ref.With,
+		Batch:         ref.Batch,
+		RowFilters:    ref.RowFilters,
+		AllowNulls:    ref.AllowNulls,
+		Cache:         ref.Cache,
+		ColumnsConfig: ref.ColumnsConfig,
+		SelfReference: ref.SelfReference,
+	}
+
+	if aView.Name != "" {
+		result.Name = aView.Name
+	}
+	if aView.Mode != "" {
+		result.Mode = aView.Mode
+	}
+	if aView.Connector != nil {
+		result.Connector = aView.Connector
+	}
+	if aView.Table != "" {
+		result.Table = aView.Table
+	}
+	if aView.From != "" {
+		result.From = aView.From
+	}
+	if aView.FromURL != "" {
+		result.FromURL = aView.FromURL
+	}
+	if len(aView.Exclude) > 0 {
+		result.Exclude = aView.Exclude
+	}
+	if len(aView.Columns) > 0 {
+		result.Columns = aView.Columns
+	}
+	if aView.Criteria != "" {
+		result.Criteria = aView.Criteria
+	}
+	if aView.Selector != nil {
+		result.Selector = aView.Selector
+	}
+	if aView.Template != nil {
+		result.Template = aView.Template
+	}
+	if len(aView.With) > 0 {
+		result.With = aView.With
+	}
+	if aView.Batch != nil {
+		result.Batch = aView.Batch
+	}
+	if len(aView.RowFilters) > 0 {
+		result.RowFilters = aView.RowFilters
+	}
+	if aView.AllowNulls != nil {
+		result.AllowNulls = aView.AllowNulls
+	}
+	if aView.Cache != nil {
+		result.Cache = aView.Cache
+	}
+	if len(aView.ColumnsConfig) > 0 {
+		result.ColumnsConfig = aView.ColumnsConfig
+	}
+	if aView.SelfReference != nil {
+		result.SelfReference = aView.SelfReference
+	}
+
+	return result
+}
+
+//parameter returns referenced resource parameter if needed
+func (i *reverseIndex) parameter(param *view.Parameter) *view.Parameter {
+	if param.Ref == "" {
+		return param
+	}
+
+	if ref, ok := i.params[param.Ref]; ok {
+		return ref
+	}
+
+	return param
+}
+
+//summary returns route settings that DSQL can express, used to compare route YAML with the one built from DSQL
+func (i *reverseIndex) summary(route *router.Route) []string {
+	var result []string
+	add := func(template string, args ...interface{}) {
+		result = append(result, fmt.Sprintf(template, args...))
+	}
+
+	URI := strings.TrimPrefix(route.URI, reverseAPIPrefix)
+	add("route URI: %v", strings.TrimPrefix(strings.TrimPrefix(URI, folderDev+"/"), "/"))
+	add("route Method: %v", strings.ToUpper(view.FirstNotEmpty(route.Method, "GET")))
+	if route.Style == router.ComprehensiveStyle || route.ResponseField != "" {
+		add("route Style: %v %v", router.ComprehensiveStyle, view.FirstNotEmpty(route.ResponseField, "Data"))
+	} else {
+		add("route Style: %v", router.BasicStyle)
+	}
+
+	add("route Cardinality: %v", view.FirstNotEmpty(string(route.Cardinality), string(view.Many)))
+	caseFormat := view.CaseFormat(view.FirstNotEmpty(string(route.CaseFormat), string(view.UpperCamel)))
+	if caser, err := caseFormat.Caser(); err == nil {
+		add("route CaseFormat: %v", caser)
+	}
+
+	exclude := append([]string{}, route.Exclude...)
+	sort.Strings(exclude)
+	add("route Exclude: %v", exclude)
+	i.viewSummary(i.view(route.View), "", "", add)
+	sort.Strings(result)
+	return result
+}
+
+func (i *reverseIndex) viewSummary(aView *view.View, holderPath, connector string, add func(template string, args ...interface{})) {
+	if aView == nil {
+		return
+	}
+
+	prefix := "view " + view.FirstNotEmpty(holderPath, "<main>")
+	if aView.Table != "" && columns.CanBeTableName(aView.Table) {
+		add("%v Table: %v", prefix, aView.Table)
+	}
+
+	connector = view.FirstNotEmpty(connectorName(aView.Connector), connector) //relation views inherit parent connector
+	add("%v Connector: %v", prefix, connector)
+
+	limit := 0
+	if aView.Selector != nil {
+		limit = aView.Selector.Limit
+	}
+	add("%v Limit: %v", prefix, limit)
+
+	if aView.Template != nil {
+		for _, param := range aView.Template.Parameters {
+			param = i.parameter(param)
+			if param.In == nil {
+				continue
+			}
+			add("%v parameter %v: %v/%v", prefix, param.Name, param.In.Kind, view.FirstNotEmpty(param.In.Name, param.Name))
+		}
+	}
+
+	for _, relation := range aView.With {
+		if relation.Of == nil {
+			continue
+		}
+
+		relPath := joinHolder(holderPath, relation.Holder)
+		add("relation %v: %v %v = %v", relPath, view.FirstNotEmpty(string(relation.Cardinality), string(view.Many)), view.FirstNotEmpty(relation.Column, relation.Field), view.FirstNotEmpty(relation.Of.Column, relation.Of.Field))
+		i.viewSummary(i.view(&relation.Of.View), relPath, connector, add)
+	}
+}
+
+//Verify builds DSQL with builder and compares generated route YAML with the source route, returns differences
+func (r *Reverser) Verify(ctx context.Context, result *ReverseResult) ([]string, error) {
+	location := url.Join("mem://localhost/reverse", result.Name+".sql")
+	if err := r.fs.Upload(ctx, location, file.DefaultFileOsMode, strings.NewReader(result.DSQL)); err != nil {
+		return nil, err
+	}
+
+	options := &Options{}
+	options.Location = location
+	mainConnector := connectorName(r.index.view(result.Route.View).Connector)
+	for _, connector := range r.resource.Resource.Connectors {
+		connect := connector.Name + "|" + connector.Driver + "|" + connector.DSN
+		if connector.Name == mainConnector {
+			options.Connects = append([]string{connect}, options.Connects...)
+			continue
+		}
+		options.Connects = append(options.Connects, connect)
+	}
+
+	options.Init()
+	if _, err := NewBuilder(options, ioutil.Discard); err != nil {
+		return nil, fmt.Errorf("failed to build %v: %w", result.Name, err)
+	}
+
+	connections, err := view.LoadResourceFromURL(ctx, options.DepURL("connections"), r.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	built, err := router.LoadResource(ctx, r.fs, options.RouterURL(), false, map[string]*view.Resource{"connections": connections})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(built.Routes) == 0 {
+		return nil, fmt.Errorf("builder did not generate route for %v", result.Name)
+	}
+
+	expected := r.index.summary(result.Route)
+	actual := newReverseIndex(built.Resource).summary(built.Routes[0])
+	return diffLines(expected, actual), nil
+}
+
+func diffLines(expected, actual []string) []string {
+	expectedIndex := map[string]bool{}
+	for _, line := range expected {
+		expectedIndex[line] = true
+	}
+
+	actualIndex := map[string]bool{}
+	for _, line := range actual {
+		actualIndex[line] = true
+	}
+
+	var result []string
+	for _, line := range expected {
+		if !actualIndex[line] {
+			result = append(result, "- "+line)
+		}
+	}
+
+	for _, line := range actual {
+		if !expectedIndex[line] {
+			result = append(result, "+ "+line)
+		}
+	}
+
+	return result
+}
+
+func reverse(options *Options, logger io.Writer) error {
+	ctx := context.Background()
+	fs := afs.New()
+	reverser := NewReverser(fs)
+	results, err := reverser.Reverse(ctx, normalizeURL(options.ReverseURL))
+	if err != nil {
+		return err
+	}
+
+	differences := 0
+	for _, result := range results {
+		for _, warning := range result.Warnings {
+			_, _ = logger.Write([]byte(fmt.Sprintf("[WARN] %v: %v\n", result.Name, warning)))
+		}
+
+		if options.ReverseDest == "" {
+			_, _ = logger.Write([]byte(fmt.Sprintf("-------------- %v.sql --------------\n%v\n", result.Name, result.DSQL)))
+		} else {
+			URL := url.Join(normalizeURL(options.ReverseDest), result.Name+".sql")
+			if err = fs.Upload(ctx, URL, file.DefaultFileOsMode, strings.NewReader(result.DSQL)); err != nil {
+				return fmt.Errorf("failed to write %v: %w", URL, err)
+			}
+			_, _ = logger.Write([]byte(fmt.Sprintf("generated %v\n", URL)))
+		}
+
+		if !options.ReverseVerify {
+			continue
+		}
+
+		diff, err := reverser.Verify(ctx, result)
+		if err != nil {
+			return err
+		}
+
+		for _, line := range diff {
+			_, _ = logger.Write([]byte(fmt.Sprintf("%v: %v\n", result.Name, line)))
+		}
+		differences += len(diff)
+	}
+
+	if differences > 0 {
+		return fmt.Errorf("reverse verification failed with %v difference(s)", differences)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+	_ "github.com/viant/sqlx/metadata/product/sqlite"
+	"github.com/viant/toolbox"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestReverser_Reverse(t *testing.T) {
+	dbLocation := "/tmp/datly_reverse_test.db"
+	_ = os.Remove(dbLocation)
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE events (id INTEGER PRIMARY KEY, event_type_id INTEGER, quantity DECIMAL(7, 2), user_id INTEGER); " +
+		"CREATE TABLE event_types (id INTEGER PRIMARY KEY, name TEXT, account_id INTEGER)")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	testLocation := toolbox.CallerDirectory(3)
+	useCases := []struct {
+		description string
+		URL         string
+		expectURL   string
+		warnings    []string
+		diff        []string
+	}{
+		{
+			description: "route with relation, parameters and selector",
+			URL:         "route.yaml",
+			expectURL:   "route.sql",
+		},
+		{
+			description: "route with settings without DSQL equivalent",
+			URL:         "unsupported.yaml",
+			expectURL:   "unsupported.sql",
+			warnings: []string{
+				"route /api/events will be published as /v1/api/dev/api/events",
+				"route Version setting has no DSQL equivalent and was skipped",
+				"view events Criteria setting has no DSQL equivalent and was skipped",
+				"view events parameter userID is not referenced in view SQL and was skipped",
+				"route EventType Exclude [AccountId] has no DSQL equivalent and was skipped",
+			},
+			diff: []string{
+				"- route Exclude: [EventType.AccountId]",
+				"- view <main> parameter userID: query/user",
+				"+ route Exclude: []",
+			},
+		},
+	}
+
+	fs := afs.New()
+	for _, useCase := range useCases {
+		reverser := NewReverser(fs)
+		results, err := reverser.Reverse(context.Background(), path.Join(testLocation, "testdata", "reverse", useCase.URL))
+		if !assert.Nil(t, err, useCase.description) || !assert.Len(t, results, 1, useCase.description) {
+			continue
+		}
+
+		expect, err := os.ReadFile(path.Join(testLocation, "testdata", "reverse", useCase.expectURL))
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		assert.Equal(t, string(expect), results[0].DSQL, useCase.description)
+		assert.Equal(t, useCase.warnings, results[0].Warnings, useCase.description)
+
+		diff, err := reverser.Verify(context.Background(), results[0])
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.diff, diff, useCase.description)
+	}
+}
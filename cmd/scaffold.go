@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/url"
+	"github.com/viant/datly/cmd/option"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/view"
+	"github.com/viant/datly/view/discover"
+	"github.com/viant/sqlx/metadata"
+	"github.com/viant/sqlx/metadata/info"
+	"github.com/viant/sqlx/metadata/sink"
+	"github.com/viant/toolbox/format"
+	"gopkg.in/yaml.v3"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+type (
+	//scaffoldTable represents table with relations discovered by foreign keys referencing it
+	scaffoldTable struct {
+		name        string
+		alias       string
+		primaryKeys []string
+		fkColumn    string //column referencing parent table
+		refColumn   string //parent table column referenced by fkColumn
+		relations   []*scaffoldTable
+	}
+
+	//scaffoldRoute represents generated route DSQL
+	scaffoldRoute struct {
+		Method string
+		URI    string
+		URL    string
+	}
+)
+
+func scaffold(options *Options, logger io.Writer) error {
+	ctx := context.Background()
+	pristine := *options
+	pristine.Location = ""
+	generatorOptions := pristine
+	generator, err := NewBuilder(&generatorOptions, logger)
+	if err != nil {
+		return err
+	}
+
+	routes, err := generator.scaffold(ctx, pristine)
+	if err != nil {
+		return err
+	}
+
+	fs := afs.New()
+	var built []*router.Route
+	for _, route := range routes {
+		routeOptions := pristine
+		routeOptions.Location = route.URL
+		routeOptions.Init()
+		if _, err = NewBuilder(&routeOptions, logger); err != nil {
+			return fmt.Errorf("failed to build %v %v: %w", route.Method, route.URI, err)
+		}
+
+		_, _ = logger.Write([]byte(fmt.Sprintf("generated %v %v: %v\n", route.Method, route.URI, route.URL)))
+		if options.OpenApiURL == "" && options.GoClientURL == "" && options.TypeScriptURL == "" {
+			continue
+		}
+
+		connections, err := view.LoadResourceFromURL(ctx, routeOptions.DepURL("connections"), fs)
+		if err != nil {
+			return err
+		}
+
+		resource, err := router.LoadResource(ctx, fs, routeOptions.RouterURL(), true, map[string]*view.Resource{"connections": connections})
+		if err != nil {
+			return err
+		}
+
+		if err = generator.storeScaffoldColumns(ctx, resource); err != nil {
+			return err
+		}
+
+		if err = resource.Init(ctx); err != nil {
+			return fmt.Errorf("failed to initialise %v %v: %w", route.Method, route.URI, err)
+		}
+
+		built = append(built, router.New(resource, router.ApiPrefix(generator.config.APIPrefix)).Routes("")...)
+	}
+
+	if options.OpenApiURL != "" {
+		spec, err := router.GenerateOpenAPI3Spec(options.Info(), built...)
+		if err != nil {
+			return err
+		}
+
+		data, err := yaml.Marshal(spec)
+		if err != nil {
+			return err
+		}
+
+		if err = os.WriteFile(normalizeURL(options.OpenApiURL), data, file.DefaultFileOsMode); err != nil {
+			return err
+		}
+	}
+
+	if options.GoClientURL != "" {
+		if err = writeGoClient(&options.Client, built); err != nil {
+			return err
+		}
+	}
+
+	if options.TypeScriptURL != "" {
+		if err = writeTypeScriptClient(&options.Client, built); err != nil {
+			return err
+		}
+	}
+
+	if options.WriteLocation != "" {
+		dumpConfiguration(options.WriteLocation, folderDev, options)
+	}
+
+	return nil
+}
+
+//scaffold uploads GET collection, GET by id, POST, PUT, PATCH and DELETE DSQL for the scaffold table,
+//options are used to create rule builders as builder initialisation updates them
+func (s *Builder) scaffold(ctx context.Context, options Options) ([]*scaffoldRoute, error) {
+	connector, err := s.ConnectorRef(s.options.Connector.DbName)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := s.DB(connector)
+	if err != nil {
+		return nil, err
+	}
+
+	var foreignKeys []sink.Key
+	if err = metadata.New().Info(ctx, db, info.KindForeignKeys, &foreignKeys); err != nil {
+		return nil, err
+	}
+
+	table, err := s.scaffoldTable(ctx, db, foreignKeys, s.options.ScaffoldTable, 0, newUniqueIndex(true), map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(table.primaryKeys) == 0 {
+		return nil, fmt.Errorf("table %v has no primary key", table.name)
+	}
+
+	URI := view.FirstNotEmpty(s.options.ScaffoldURI, strings.ToLower(table.name))
+	params := table.pathParams()
+	itemURI := URI
+	for _, param := range params {
+		itemURI += "/{" + param + "}"
+	}
+
+	listSQL := &strings.Builder{}
+	table.appendQuery(listSQL, "")
+
+	itemSQL := &strings.Builder{}
+	table.appendQuery(itemSQL, " WHERE "+table.keyCriteria(params))
+
+	name := strings.ToLower(table.name)
+	routes := []*scaffoldRoute{
+		{Method: http.MethodGet, URI: URI, URL: name + "_list"},
+		{Method: http.MethodGet, URI: itemURI, URL: name + "_get"},
+		{Method: http.MethodPost, URI: URI, URL: name + "_post"},
+		{Method: http.MethodPut, URI: URI, URL: name + "_put"},
+		{Method: http.MethodPatch, URI: URI, URL: name + "_patch"},
+		{Method: http.MethodDelete, URI: itemURI, URL: name + "_delete"},
+	}
+
+	for _, route := range routes {
+		routeConfig := &option.RouteConfig{URI: route.URI}
+		if route.Method != http.MethodGet {
+			routeConfig.Method = route.Method
+		}
+
+		hint, err := json.Marshal(routeConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		SQL := fmt.Sprintf("/* %s */\n\n", hint)
+		switch route.Method {
+		case http.MethodGet:
+			if route.URI == URI {
+				SQL += listSQL.String()
+			} else {
+				SQL += strings.Replace(itemSQL.String(), table.alias+".*", table.alias+`.* /* {"Cardinality":"One"} */`, 1)
+			}
+		case http.MethodDelete:
+			SQL += table.deleteSQL(params)
+		default:
+			if SQL, err = s.prepareScaffoldSQL(ctx, options, strings.ToLower(route.Method), SQL+listSQL.String()); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err = s.uploadSQL(folderSQL, route.URL, SQL, false); err != nil {
+			return nil, err
+		}
+
+		route.URL = normalizeURL(s.options.URL(folderSQL, route.URL, false, ".sql"))
+	}
+
+	return routes, nil
+}
+
+//storeScaffoldColumns stores route views table columns in the route columns cache, so that route resource is initialised
+//without detecting columns with templates evaluated for empty parameters
+func (s *Builder) storeScaffoldColumns(ctx context.Context, resource *router.Resource) error {
+	connector, err := s.ConnectorRef(s.options.Connector.DbName)
+	if err != nil {
+		return err
+	}
+
+	db, err := s.DB(connector)
+	if err != nil {
+		return err
+	}
+
+	parent, name := url.Split(resource.SourceURL, file.Scheme)
+	cache := discover.New(url.Join(parent, ".meta", name), s.fs)
+	for _, aView := range resource.Resource.Views {
+		if aView.Table == "" {
+			continue
+		}
+
+		columns, err := s.readSinkColumns(ctx, db, aView.Table)
+		if err != nil {
+			return err
+		}
+
+		for _, column := range columns {
+			nullable := strings.ToLower(column.Nullable) == "yes" || column.Nullable == "1"
+			cache.Items[aView.Name] = append(cache.Items[aView.Name], &view.Column{Name: column.Name, DataType: column.Type, Nullable: nullable})
+		}
+	}
+
+	cache.ModTime = resource.Resource.ModTime
+	return cache.Store(ctx)
+}
+
+//prepareScaffoldSQL generates rule DSQL with a new builder, so that every rule uploads the same Go type files
+func (s *Builder) prepareScaffoldSQL(ctx context.Context, options Options, rule string, ruleSQL string) (string, error) {
+	generator, err := NewBuilder(&options, s.logger)
+	if err != nil {
+		return "", err
+	}
+
+	routeOption, config, metadata, err := generator.buildInputMetadata(ctx, []byte(ruleSQL))
+	if err != nil {
+		return "", err
+	}
+
+	switch rule {
+	case PreparePost:
+		return generator.buildInsertSQL(metadata, config, routeOption)
+	case PreparePut:
+		return generator.buildUpdateSQL(routeOption, config, metadata)
+	case PreparePatch:
+		return generator.buildPatchSQL(routeOption, config, metadata)
+	default:
+		return "", fmt.Errorf("unsupported prepare rule type")
+	}
+}
+
+func (s *Builder) scaffoldTable(ctx context.Context, db *sql.DB, foreignKeys []sink.Key, tableName string, depth int, aliases *uniqueIndex, visited map[string]bool) (*scaffoldTable, error) {
+	primaryKeys, err := s.readPrimaryKeys(ctx, db, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &scaffoldTable{name: tableName, alias: aliases.unique(lowerCamel(tableName))}
+	for _, key := range primaryKeys {
+		result.primaryKeys = append(result.primaryKeys, key.Column)
+	}
+
+	if depth >= s.options.ScaffoldDepth {
+		return result, nil
+	}
+
+	visited[strings.ToLower(tableName)] = true
+	defer delete(visited, strings.ToLower(tableName))
+	for _, key := range foreignKeys {
+		if !strings.EqualFold(key.ReferenceTable, tableName) || visited[strings.ToLower(key.Table)] || !s.followScaffoldRelation(key.Table) {
+			continue
+		}
+
+		relation, err := s.scaffoldTable(ctx, db, foreignKeys, key.Table, depth+1, aliases, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		relation.fkColumn = key.Column
+		relation.refColumn = key.ReferenceColumn
+		result.relations = append(result.relations, relation)
+	}
+
+	return result, nil
+}
+
+func (s *Builder) followScaffoldRelation(tableName string) bool {
+	if len(s.options.ScaffoldRelations) == 0 {
+		return true
+	}
+
+	for _, candidate := range s.options.ScaffoldRelations {
+		if strings.EqualFold(candidate, tableName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *scaffoldTable) appendQuery(sb *strings.Builder, criteria string) {
+	sb.WriteString("SELECT " + t.alias + ".*")
+	for _, relation := range t.relations {
+		sb.WriteString(",\n\t" + relation.alias + ".*")
+	}
+
+	sb.WriteString("\nFROM (SELECT * FROM " + t.name + criteria + ") " + t.alias)
+	for _, relation := range t.relations {
+		sb.WriteString("\nJOIN (")
+		if len(relation.relations) == 0 {
+			sb.WriteString("SELECT * FROM " + relation.name)
+		} else {
+			relation.appendQuery(sb, "")
+		}
+		sb.WriteString(fmt.Sprintf(") %v ON %v.%v = %v.%v", relation.alias, relation.alias, relation.fkColumn, t.alias, relation.refColumn))
+	}
+}
+
+func (t *scaffoldTable) pathParams() []string {
+	result := make([]string, 0, len(t.primaryKeys))
+	for _, column := range t.primaryKeys {
+		result = append(result, lowerCamel(column))
+	}
+
+	return result
+}
+
+func (t *scaffoldTable) keyCriteria(params []string) string {
+	criteria := make([]string, 0, len(t.primaryKeys))
+	for i, column := range t.primaryKeys {
+		criteria = append(criteria, column+" = $"+params[i])
+	}
+
+	return strings.Join(criteria, " AND ")
+}
+
+//deleteSQL returns DELETE statement removing the table record only, related records are left to the database constraints
+func (t *scaffoldTable) deleteSQL(params []string) string {
+	return fmt.Sprintf("DELETE FROM %v WHERE %v;\n", t.name, t.keyCriteria(params))
+}
+
+func lowerCamel(name string) string {
+	caseFormat, err := format.NewCase(view.DetectCase(name))
+	if err != nil {
+		return name
+	}
+
+	return caseFormat.Format(name, format.CaseLowerCamel)
+}
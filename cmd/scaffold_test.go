@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	_ "github.com/viant/sqlx/metadata/product/sqlite"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestScaffold(t *testing.T) {
+	dbLocation := "/tmp/datly_scaffold_test.db"
+	_ = os.Remove(dbLocation)
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE vendor (ID INTEGER PRIMARY KEY AUTOINCREMENT, NAME TEXT); " +
+		"CREATE TABLE product (ID INTEGER PRIMARY KEY AUTOINCREMENT, NAME TEXT, VENDOR_ID INTEGER, FOREIGN KEY(VENDOR_ID) REFERENCES vendor(ID)); " +
+		"CREATE TABLE product_tag (ID INTEGER PRIMARY KEY AUTOINCREMENT, TAG TEXT, PRODUCT_ID INTEGER, FOREIGN KEY(PRODUCT_ID) REFERENCES product(ID))")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	workingDir, err := os.Getwd()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.Chdir(workingDir)
+
+	useCases := []struct {
+		description string
+		args        []string
+		expect      map[string]string
+		expectFiles map[string][]string
+	}{
+		{
+			description: "table without relations",
+			args:        []string{"-F=0"},
+			expect: map[string]string{
+				"vendor_list.sql": "/* {\"URI\":\"vendors\"} */\n\nSELECT vendor.*\nFROM (SELECT * FROM vendor) vendor",
+				"vendor_get.sql":  "/* {\"URI\":\"vendors/{id}\"} */\n\nSELECT vendor.* /* {\"Cardinality\":\"One\"} */\nFROM (SELECT * FROM vendor WHERE ID = $id) vendor",
+				"vendor_delete.sql": "/* {\"URI\":\"vendors/{id}\",\"Method\":\"DELETE\"} */\n\n" +
+					"DELETE FROM vendor WHERE ID = $id;\n",
+			},
+		},
+		{
+			description: "OpenAPI spec and clients",
+			args:        []string{"-F=0", "-o=openapi.yaml", "-H=client.go", "-M=client.ts"},
+			expect: map[string]string{
+				"vendor_list.sql": "/* {\"URI\":\"vendors\"} */\n\nSELECT vendor.*\nFROM (SELECT * FROM vendor) vendor",
+			},
+			expectFiles: map[string][]string{
+				"openapi.yaml": {"/v1/api/dev/vendors:", "/v1/api/dev/vendors/{id}:", "get:", "post:", "put:", "patch:", "delete:"},
+				"client.go":    {"vendors"},
+				"client.ts":    {"vendors"},
+			},
+		},
+		{
+			description: "relations followed two levels",
+			args:        []string{"-F=2"},
+			expect: map[string]string{
+				"vendor_list.sql": "/* {\"URI\":\"vendors\"} */\n\nSELECT vendor.*,\n\tproduct.*\nFROM (SELECT * FROM vendor) vendor\n" +
+					"JOIN (SELECT product.*,\n\tproductTag.*\nFROM (SELECT * FROM product) product\n" +
+					"JOIN (SELECT * FROM product_tag) productTag ON productTag.PRODUCT_ID = product.ID) product ON product.VENDOR_ID = vendor.ID",
+				"vendor_delete.sql": "/* {\"URI\":\"vendors/{id}\",\"Method\":\"DELETE\"} */\n\n" +
+					"DELETE FROM vendor WHERE ID = $id;\n",
+			},
+		},
+		{
+			description: "relations selected by flag",
+			args:        []string{"-F=2", "-I=product"},
+			expect: map[string]string{
+				"vendor_list.sql": "/* {\"URI\":\"vendors\"} */\n\nSELECT vendor.*,\n\tproduct.*\nFROM (SELECT * FROM vendor) vendor\n" +
+					"JOIN (SELECT * FROM product) product ON product.VENDOR_ID = vendor.ID",
+			},
+		},
+	}
+
+	for _, useCase := range useCases {
+		testDir := t.TempDir()
+		if !assert.Nil(t, os.Chdir(testDir), useCase.description) {
+			continue
+		}
+
+		args := append([]string{"-D=sqlite3", "-A=" + dbLocation, "-T=vendor", "-U=vendors", "-w=" + path.Join(testDir, "out")}, useCase.args...)
+		_, err = New("", args, ioutil.Discard)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		for _, name := range []string{"vendor_list", "vendor_get", "vendor_post", "vendor_put", "vendor_patch", "vendor_delete"} {
+			_, err = os.Stat(path.Join(testDir, "out", "Datly", "routes", "dev", name+".yaml"))
+			assert.Nil(t, err, useCase.description+" "+name)
+		}
+
+		_, err = os.Stat(path.Join(testDir, folderSQL, "vendor.go"))
+		assert.Nil(t, err, useCase.description)
+		for fileName, fragments := range useCase.expectFiles {
+			data, err := os.ReadFile(path.Join(testDir, fileName))
+			assert.Nil(t, err, useCase.description+" "+fileName)
+			for _, fragment := range fragments {
+				assert.Contains(t, string(data), fragment, useCase.description+" "+fileName)
+			}
+		}
+
+		for fileName, expect := range useCase.expect {
+			actual, err := os.ReadFile(path.Join(testDir, folderSQL, fileName))
+			if !assert.Nil(t, err, useCase.description+" "+fileName) {
+				continue
+			}
+
+			assert.Equal(t, expect, string(actual), useCase.description+" "+fileName)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/url"
+	"github.com/viant/dsunit"
+	"github.com/viant/toolbox"
+	turl "github.com/viant/toolbox/url"
+	"gopkg.in/yaml.v3"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type (
+	//TestSuite represents HTTP cases executed against routes, optionally seeding datastore with dsunit
+	TestSuite struct {
+		URL      string `json:"-"`
+		Name     string
+		Init     string //dsunit init config URL, i.e. datastore, recreate, schema scripts
+		Fixtures string //folder with <table>.json or <table>.csv datasets populated before the suite
+		Cases    []*TestCase
+		service  dsunit.Service
+		store    string
+	}
+
+	//TestCase represents HTTP request and expected response
+	TestCase struct {
+		Name     string
+		Method   string
+		URI      string
+		Headers  map[string]string
+		Body     interface{}
+		Fixtures string //folder with datasets populated before the case
+		Status   int
+		Expect   map[string]interface{} //JSON path to expected value, i.e. $.Data[0].Id
+		Response interface{}            //expected response body
+		Ignore   []string               //JSON paths excluded from the Response comparison, i.e. $.Data[*].Timestamp
+	}
+
+	//TestResult represents test case outcome
+	TestResult struct {
+		Suite    string
+		Case     string
+		Failures []string
+		Elapsed  time.Duration
+	}
+)
+
+//LoadTestSuite loads test suite, relative URLs are resolved against suite location
+func LoadTestSuite(ctx context.Context, fs afs.Service, URL string) (*TestSuite, error) {
+	data, err := fs.DownloadWithURL(ctx, URL)
+	if err != nil {
+		return nil, err
+	}
+
+	aMap := map[string]interface{}{}
+	if err = yaml.Unmarshal(data, &aMap); err != nil {
+		return nil, fmt.Errorf("invalid test suite %v: %w", URL, err)
+	}
+
+	suite := &TestSuite{}
+	if err = toolbox.DefaultConverter.AssignConverted(suite, aMap); err != nil {
+		return nil, fmt.Errorf("invalid test suite %v: %w", URL, err)
+	}
+
+	suite.URL = URL
+	if suite.Name == "" {
+		_, name := url.Split(URL, file.Scheme)
+		suite.Name = strings.TrimSuffix(name, path.Ext(name))
+	}
+
+	suite.Init = suite.resolve(suite.Init)
+	suite.Fixtures = suite.resolve(suite.Fixtures)
+	for i, aCase := range suite.Cases {
+		aCase.Fixtures = suite.resolve(aCase.Fixtures)
+		if aCase.Name == "" {
+			aCase.Name = fmt.Sprintf("case%03d", i+1)
+		}
+	}
+
+	return suite, nil
+}
+
+func (s *TestSuite) resolve(URL string) string {
+	if URL == "" || !url.IsRelative(URL) {
+		return URL
+	}
+
+	baseURL, _ := url.Split(s.URL, file.Scheme)
+	return url.Join(baseURL, URL)
+}
+
+//Prepare initialises datastore and populates suite fixtures
+func (s *TestSuite) Prepare() error {
+	if s.Init == "" {
+		if s.Fixtures != "" {
+			return fmt.Errorf("test suite %v fixtures require Init datastore config", s.Name)
+		}
+		return nil
+	}
+
+	request, err := dsunit.NewInitRequestFromURL(s.Init)
+	if err != nil {
+		return fmt.Errorf("invalid test suite %v init: %w", s.Name, err)
+	}
+
+	if request.RunScriptRequest != nil {
+		for i, script := range request.Scripts {
+			request.Scripts[i] = turl.NewResource(s.resolve(script.URL))
+		}
+	}
+
+	s.service = dsunit.New()
+	s.store = request.Datastore
+	if err = s.service.Init(request).Error(); err != nil {
+		return fmt.Errorf("failed to init test suite %v datastore: %w", s.Name, err)
+	}
+
+	return s.populate(s.Fixtures)
+}
+
+func (s *TestSuite) populate(URL string) error {
+	if URL == "" {
+		return nil
+	}
+
+	if s.service == nil {
+		return fmt.Errorf("test suite %v fixtures require Init datastore config", s.Name)
+	}
+
+	request := dsunit.NewPrepareRequest(dsunit.NewDatasetResource(s.store, URL, "", ""))
+	if err := s.service.Prepare(request).Error(); err != nil {
+		return fmt.Errorf("failed to populate %v: %w", URL, err)
+	}
+
+	return nil
+}
+
+//Run executes suite cases with handler
+func (s *TestSuite) Run(handler http.Handler) []*TestResult {
+	var results []*TestResult
+	for _, aCase := range s.Cases {
+		started := time.Now()
+		result := &TestResult{Suite: s.Name, Case: aCase.Name}
+		if err := s.populate(aCase.Fixtures); err != nil {
+			result.Failures = append(result.Failures, err.Error())
+		} else {
+			result.Failures = aCase.Run(handler)
+		}
+
+		result.Elapsed = time.Since(started)
+		results = append(results, result)
+	}
+
+	return results
+}
+
+//Run executes case request with handler, returns failures
+func (c *TestCase) Run(handler http.Handler) []string {
+	body, err := c.requestBody()
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	method := c.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	request := httptest.NewRequest(method, c.URI, body)
+	for key, value := range c.Headers {
+		request.Header.Set(key, value)
+	}
+
+	if c.Body != nil && request.Header.Get("Content-Type") == "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	var failures []string
+	status := c.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if recorder.Code != status {
+		failures = append(failures, fmt.Sprintf("expected status %v, but had %v: %s", status, recorder.Code, recorder.Body.Bytes()))
+	}
+
+	if len(c.Expect) == 0 && c.Response == nil {
+		return failures
+	}
+
+	var actual interface{}
+	if err = json.Unmarshal(recorder.Body.Bytes(), &actual); err != nil {
+		return append(failures, fmt.Sprintf("invalid JSON response: %v, %s", err, recorder.Body.Bytes()))
+	}
+
+	for _, aPath := range sortedKeys(c.Expect) {
+		failures = append(failures, c.expectPath(actual, aPath)...)
+	}
+
+	if c.Response != nil {
+		failures = append(failures, c.expectResponse(actual)...)
+	}
+
+	return failures
+}
+
+func (c *TestCase) requestBody() (io.Reader, error) {
+	switch actual := c.Body.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.NewReader(actual), nil
+	default:
+		data, err := json.Marshal(normalizeYAML(actual))
+		if err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+		return bytes.NewReader(data), nil
+	}
+}
+
+func (c *TestCase) expectPath(actual interface{}, aPath string) []string {
+	expected, err := normalizeJSON(c.Expect[aPath])
+	if err != nil {
+		return []string{fmt.Sprintf("invalid expected %v: %v", aPath, err)}
+	}
+
+	values := selectJSONPath(actual, aPath)
+	if len(values) == 0 {
+		return []string{fmt.Sprintf("%v: not found", aPath)}
+	}
+
+	var actualValue interface{} = values
+	if !strings.Contains(aPath, "*") {
+		actualValue = values[0]
+	}
+
+	if reflect.DeepEqual(expected, actualValue) {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%v: expected %v, but had %v", aPath, asJSON(expected), asJSON(actualValue))}
+}
+
+func (c *TestCase) expectResponse(actual interface{}) []string {
+	expected := c.Response
+	if text, ok := expected.(string); ok {
+		if err := json.Unmarshal([]byte(text), &expected); err != nil {
+			return []string{fmt.Sprintf("invalid expected response: %v", err)}
+		}
+	}
+
+	expected, err := normalizeJSON(expected)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid expected response: %v", err)}
+	}
+
+	for _, aPath := range c.Ignore {
+		removeJSONPath(expected, aPath)
+		removeJSONPath(actual, aPath)
+	}
+
+	if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("response mismatch, expected: %v, but had: %v", asJSON(expected), asJSON(actual))}
+}
+
+//normalizeJSON converts value to its JSON decoded representation
+func normalizeJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(normalizeYAML(value))
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	return result, json.Unmarshal(data, &result)
+}
+
+//normalizeYAML converts map[interface{}]interface{} produced by the converter into JSON marshalable maps
+func normalizeYAML(value interface{}) interface{} {
+	switch actual := value.(type) {
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for key, item := range actual {
+			result[fmt.Sprintf("%v", key)] = normalizeYAML(item)
+		}
+		return result
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, item := range actual {
+			result[key] = normalizeYAML(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(actual))
+		for i, item := range actual {
+			result[i] = normalizeYAML(item)
+		}
+		return result
+	}
+
+	return value
+}
+
+func asJSON(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func runTestSuites(options *Options, logger io.Writer) error {
+	ctx := context.Background()
+	fs := afs.New()
+	var suites []*TestSuite
+	for _, URL := range options.TestURLs {
+		suite, err := LoadTestSuite(ctx, fs, normalizeURL(URL))
+		if err != nil {
+			return err
+		}
+
+		if err = suite.Prepare(); err != nil {
+			return err
+		}
+
+		suites = append(suites, suite)
+	}
+
+	builder, err := NewBuilder(options, logger)
+	if err != nil {
+		return err
+	}
+
+	srv, err := builder.build()
+	if err != nil {
+		return err
+	}
+
+	if srv == nil {
+		return fmt.Errorf("test suites require datly server, but configuration was dumped")
+	}
+
+	var results []*TestResult
+	for _, suite := range suites {
+		results = append(results, suite.Run(srv.Handler)...)
+	}
+
+	failed := 0
+	for _, result := range results {
+		status := "PASS"
+		if len(result.Failures) > 0 {
+			status = "FAIL"
+			failed++
+		}
+
+		_, _ = logger.Write([]byte(fmt.Sprintf("%v %v/%v (%v)\n", status, result.Suite, result.Case, result.Elapsed)))
+		for _, failure := range result.Failures {
+			_, _ = logger.Write([]byte("\t" + failure + "\n"))
+		}
+	}
+
+	if options.JUnitURL != "" {
+		if err = writeJUnit(ctx, fs, normalizeURL(options.JUnitURL), results); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%v of %v test case(s) failed", failed, len(results))
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+	"github.com/viant/afs/mem"
+	"github.com/viant/datly/gateway"
+	"github.com/viant/toolbox"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestRunTestSuites(t *testing.T) {
+	_ = os.MkdirAll("/tmp/datly_suite_test", 0755)
+	testLocation := path.Join(toolbox.CallerDirectory(3), "testdata", "suite")
+	junitURL := "/tmp/datly_suite_test/junit.xml"
+
+	useCases := []struct {
+		description string
+		suite       string
+		expectErr   string
+		expectJUnit []string
+	}{
+		{
+			description: "passing cases",
+			suite:       "events.yaml",
+			expectJUnit: []string{`<testsuite name="events" tests="3" failures="0"`, `<testcase name="read projection" classname="events"`},
+		},
+		{
+			description: "failing case",
+			suite:       "failing.yaml",
+			expectErr:   "1 of 1 test case(s) failed",
+			expectJUnit: []string{`<testsuite name="failing" tests="1" failures="1"`, `<failure message="$[1].Quantity: expected 6, but had 5">`},
+		},
+	}
+
+	fs := afs.New()
+	//routes are copied as loading routes generates columns cache next to route YAML
+	routesURL := path.Join(t.TempDir(), "routes")
+	if !assert.Nil(t, fs.Copy(context.Background(), path.Join(testLocation, "routes"), routesURL)) {
+		return
+	}
+
+	for _, useCase := range useCases {
+		mem.ResetSingleton()
+		gateway.ResetSingleton()
+		_ = os.Remove(junitURL)
+
+		args := []string{"-r=" + routesURL, "-t=" + path.Join(testLocation, useCase.suite), "-J=" + junitURL}
+		_, err := New("", args, &memoryWriter{})
+		if useCase.expectErr != "" {
+			assert.EqualError(t, err, useCase.expectErr, useCase.description)
+		} else {
+			assert.Nil(t, err, useCase.description)
+		}
+
+		report, err := fs.DownloadWithURL(context.Background(), junitURL)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		for _, fragment := range useCase.expectJUnit {
+			assert.True(t, strings.Contains(string(report), fragment), useCase.description+": "+fragment)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+/* {"URI":"events/{eventID}", "Methd":"GET"} */
+
+SELECT events.*
+FROM (
+         SELECT e.id, e.qty
+         FROM events e
+         WHERE e.id = $eventId
+           AND e.quantity > $quantity /* {"Kind": "querystring"} */
+     ) events /* {"Cache": {"Ref": "aerospike"}, "MetaColumn": "account_id"} */
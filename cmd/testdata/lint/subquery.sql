@@ -0,0 +1,8 @@
+/* {"URI":"events"} */
+
+SELECT events.*,
+       events.quantity /* {"DataType": "float64"} */,
+       t.name,
+       t.label
+FROM (SELECT * FROM events) events
+JOIN event_types t ON t.id = events.event_type_id
@@ -0,0 +1,11 @@
+/* {"URI":"events/{eventID}", "Method":"GET"} */
+
+SELECT events.*
+FROM (
+         SELECT e.id, e.quantity /* {"DataType": "float64"} */
+         FROM events e
+         WHERE e.id = $eventID
+           #if($Has.quantity)
+           AND e.quantity > $quantity /* {"Kind": "query", "DataType": "float64"} */
+           #end
+     ) events /* {"Selector": {"Constraints": {"Projection": true}}} */
@@ -0,0 +1,6 @@
+/* {"URI":"events/{eventTypeID}","CaseFormat":"uppercamel"} */
+
+SELECT events.* EXCEPT UserId /* {"ResponseField":"Data","Style":"Comprehensive"} */,
+       eventType.*
+FROM (SELECT * FROM events t WHERE t.event_type_id = $eventTypeID /* {"Kind":"path","Required":true,"DataType":"int"} */ AND t.quantity > $quantity /* {"Kind":"query","Required":false,"DataType":"float64","Target":"qty"} */) events /* {"Connector":"dev","Selector":{"Limit":10,"Constraints":{"Criteria":true,"OrderBy":false,"Limit":true,"Offset":true,"Projection":true,"Filterable":["*"],"Page":null}}} */
+JOIN (SELECT * FROM event_types t) eventType /* {"Selector":{}} */ ON events.event_type_id = eventType.id AND 1 = 1
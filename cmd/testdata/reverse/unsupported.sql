@@ -0,0 +1,6 @@
+/* {"URI":"api/events","CaseFormat":"uppercamel"} */
+
+SELECT events.*,
+       eventType.*
+FROM (SELECT * FROM events t) events /* {"Connector":"dev","Selector":{"Limit":25}} */
+JOIN (SELECT * FROM event_types t) eventType /* {"Selector":{"Limit":40}} */ ON events.event_type_id = eventType.id AND 1 = 1
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS events;
+
+CREATE TABLE events
+(
+    id        INTEGER PRIMARY KEY,
+    timestamp DATETIME,
+    quantity  DECIMAL(7, 2)
+);
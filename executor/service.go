@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/viant/datly/shared"
+	"github.com/viant/datly/view"
 	"strings"
 	"sync"
+	"time"
 )
 
 type Executor struct {
@@ -78,7 +80,9 @@ func (e *Executor) execData(ctx context.Context, wg *sync.WaitGroup, tx *sql.Tx,
 }
 
 func (e *Executor) executeStatement(ctx context.Context, tx *sql.Tx, stmt *SQLStatment, session *Session) error {
-	_, err := tx.ExecContext(ctx, stmt.SQL, stmt.Args...)
+	start := time.Now()
+	result, err := tx.ExecContext(ctx, stmt.SQL, stmt.Args...)
+	session.View.LogSlowQueryIfNeeded(ctx, nil, view.SQLExecMode, stmt.SQL, stmt.Args, rowsAffected(result), time.Since(start), err)
 	if err != nil {
 		session.View.Logger.LogDatabaseErr(stmt.SQL, err)
 		err = fmt.Errorf("error occured while connecting to database")
@@ -86,3 +90,12 @@ func (e *Executor) executeStatement(ctx context.Context, tx *sql.Tx, stmt *SQLSt
 
 	return err
 }
+
+func rowsAffected(result sql.Result) int {
+	if result == nil {
+		return 0
+	}
+
+	affected, _ := result.RowsAffected()
+	return int(affected)
+}
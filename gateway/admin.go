@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"github.com/viant/scy"
+	"net/http"
+	"strings"
+)
+
+const defaultAdminKeyHeader = "X-Admin-Key"
+
+//AdminKey represents credential required by the meta endpoints changing gateway state
+type AdminKey struct {
+	Header string //request header with the admin key, X-Admin-Key by default
+	Secret *scy.Resource
+
+	_hash []byte
+}
+
+//Init loads admin key secret
+func (a *AdminKey) Init(ctx context.Context) error {
+	if a.Header == "" {
+		a.Header = defaultAdminKeyHeader
+	}
+
+	if a._hash != nil {
+		return nil
+	}
+
+	if a.Secret == nil {
+		return fmt.Errorf("admin key secret was empty")
+	}
+
+	secret, err := scy.New().Load(ctx, a.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to load admin key secret: %w", err)
+	}
+
+	key := strings.TrimSpace(secret.String())
+	if key == "" {
+		return fmt.Errorf("admin key secret was empty")
+	}
+
+	a._hash = adminKeyHash(key)
+	return nil
+}
+
+func (a *AdminKey) authorize(request *http.Request) bool {
+	value := request.Header.Get(a.Header)
+	if value == "" || a._hash == nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(adminKeyHash(value), a._hash) == 1
+}
+
+func adminKeyHash(key string) []byte {
+	hash := sha256.Sum256([]byte(key))
+	return hash[:]
+}
+
+//authorizeAdmin checks admin key, admin endpoints are disabled without configured admin key
+func (r *Router) authorizeAdmin(request *http.Request) bool {
+	if r.config == nil || r.config.AdminKey == nil {
+		return false
+	}
+
+	return r.config.AdminKey.authorize(request)
+}
+
+func initAdminKey(ctx context.Context, config *Config) error {
+	if config.AdminKey == nil {
+		if config.ManagedAPIKeys != nil {
+			return fmt.Errorf("managed API keys require AdminKey")
+		}
+
+		return nil
+	}
+
+	return config.AdminKey.Init(ctx)
+}
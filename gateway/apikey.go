@@ -0,0 +1,584 @@
+package gateway
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/view"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultManagedAPIKeyHeader = "X-Api-Key"
+	defaultAPIKeyTable         = "DATLY_API_KEYS"
+	defaultAPIKeyUsageFlushSec = 60
+)
+
+type (
+	//ManagedAPIKeys represents managed API keys config, keys are stored hashed at URL or in the connector Table
+	ManagedAPIKeys struct {
+		URL           string
+		Connector     *view.Connector
+		Table         string   //connector table storing keys, DATLY_API_KEYS by default
+		Header        string   //request header with the API key, X-Api-Key by default
+		Required      []string //URI prefixes requiring a valid managed or static API key
+		UsageFlushSec int      //usage counters persistence interval, 60 by default
+	}
+
+	//ManagedAPIKey represents managed API key, only SHA-256 hash of the key is stored
+	ManagedAPIKey struct {
+		ID      string
+		Owner   string
+		Hash    string `json:",omitempty"`
+		Scopes  []*APIKeyScope
+		Expiry  *time.Time `json:",omitempty"`
+		Enabled bool
+		Created time.Time
+		Rotated *time.Time   `json:",omitempty"`
+		Usage   *APIKeyUsage `json:",omitempty"`
+	}
+
+	//APIKeyScope represents URI pattern and methods permitted to API key, pattern ending with * matches URI prefix
+	APIKeyScope struct {
+		URI     string
+		Methods []string `json:",omitempty"`
+	}
+
+	//APIKeyUsage represents API key usage counters
+	APIKeyUsage struct {
+		Count    int64
+		LastUsed *time.Time `json:",omitempty"`
+	}
+
+	//APIKeyRequest represents create API key request
+	APIKeyRequest struct {
+		Owner       string
+		Scopes      []*APIKeyScope
+		ExpiryInSec int
+	}
+
+	//APIKeyResponse represents created or rotated API key, Key is returned only once
+	APIKeyResponse struct {
+		ID     string
+		Key    string
+		Expiry *time.Time `json:",omitempty"`
+	}
+
+	//apiKeyRepository persists managed API keys, each change modifies only the affected key columns,
+	//so that changes done by other instances are not overridden
+	apiKeyRepository interface {
+		load(ctx context.Context) ([]*ManagedAPIKey, error)
+		insert(ctx context.Context, key *ManagedAPIKey) error
+		rotate(ctx context.Context, ID string, hash string, rotated time.Time) (bool, error)
+		revoke(ctx context.Context, ID string) (bool, error)
+		addUsage(ctx context.Context, usage map[string]*APIKeyUsage) error
+	}
+
+	apiKeyStore struct {
+		config     *ManagedAPIKeys
+		repository apiKeyRepository
+		mux        sync.RWMutex
+		keys       []*ManagedAPIKey
+		byHash     map[string]*ManagedAPIKey
+		usage      map[string]*apiKeyCounter
+		flushing   int32
+		flushed    int64
+	}
+
+	//apiKeyCounter represents usage not persisted yet
+	apiKeyCounter struct {
+		count    int64
+		lastUsed int64
+	}
+)
+
+//Init initializes ManagedAPIKeys
+func (m *ManagedAPIKeys) Init() {
+	if m.Header == "" {
+		m.Header = defaultManagedAPIKeyHeader
+	}
+
+	if m.Table == "" {
+		m.Table = defaultAPIKeyTable
+	}
+
+	if m.UsageFlushSec == 0 {
+		m.UsageFlushSec = defaultAPIKeyUsageFlushSec
+	}
+
+	for i, prefix := range m.Required {
+		m.Required[i] = router.AsRelative(prefix)
+	}
+}
+
+func newAPIKeyStore(ctx context.Context, config *ManagedAPIKeys, fs afs.Service) (*apiKeyStore, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	var repository apiKeyRepository
+	switch {
+	case config.Connector != nil:
+		if err := config.Connector.Init(ctx, nil); err != nil {
+			return nil, fmt.Errorf("invalid managed API keys connector: %w", err)
+		}
+		repository = &sqlAPIKeys{connector: config.Connector, table: config.Table}
+	case config.URL != "":
+		repository = &afsAPIKeys{fs: fs, URL: config.URL}
+	default:
+		return nil, fmt.Errorf("managed API keys URL and Connector were empty")
+	}
+
+	store := &apiKeyStore{config: config, repository: repository, usage: map[string]*apiKeyCounter{}, flushed: time.Now().UnixNano()}
+	return store, store.update(ctx, nil)
+}
+
+//update persists pending usage and changes done by fn, then reloads keys, so that changes done by other instances are not lost.
+func (s *apiKeyStore) update(ctx context.Context, fn func(repository apiKeyRepository) error) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if pending := s.pendingUsage(); len(pending) > 0 {
+		if err := s.repository.addUsage(ctx, pending); err != nil {
+			s.restoreUsage(pending)
+			return err
+		}
+	}
+
+	if fn != nil {
+		if err := fn(s.repository); err != nil {
+			return err
+		}
+	}
+
+	keys, err := s.repository.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.index()
+	return nil
+}
+
+//pendingUsage returns and resets usage not persisted yet
+func (s *apiKeyStore) pendingUsage() map[string]*APIKeyUsage {
+	result := map[string]*APIKeyUsage{}
+	for ID, counter := range s.usage {
+		if pending := counter.take(); pending != nil {
+			result[ID] = pending
+		}
+	}
+
+	return result
+}
+
+func (s *apiKeyStore) restoreUsage(pending map[string]*APIKeyUsage) {
+	for ID, usage := range pending {
+		s.usage[ID].restore(usage)
+	}
+}
+
+//index needs to be called with the lock held
+func (s *apiKeyStore) index() {
+	s.byHash = make(map[string]*ManagedAPIKey, len(s.keys))
+	for _, key := range s.keys {
+		s.byHash[key.Hash] = key
+		if _, ok := s.usage[key.ID]; !ok {
+			s.usage[key.ID] = &apiKeyCounter{}
+		}
+	}
+}
+
+func (s *apiKeyStore) create(ctx context.Context, request *APIKeyRequest) (*APIKeyResponse, error) {
+	if request.Owner == "" {
+		return nil, fmt.Errorf("API key owner was empty")
+	}
+
+	if len(request.Scopes) == 0 {
+		return nil, fmt.Errorf("API key scopes were empty")
+	}
+
+	for _, scope := range request.Scopes {
+		if scope.URI == "" {
+			return nil, fmt.Errorf("API key scope URI was empty")
+		}
+		scope.URI = router.AsRelative(scope.URI)
+	}
+
+	ID, err := randomToken(8)
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey, hash, err := newRawAPIKey(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &ManagedAPIKey{ID: ID, Owner: request.Owner, Hash: hash, Scopes: request.Scopes, Enabled: true, Created: time.Now()}
+	if request.ExpiryInSec > 0 {
+		expiry := key.Created.Add(time.Duration(request.ExpiryInSec) * time.Second)
+		key.Expiry = &expiry
+	}
+
+	err = s.update(ctx, func(repository apiKeyRepository) error {
+		return repository.insert(ctx, key)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIKeyResponse{ID: ID, Key: rawKey, Expiry: key.Expiry}, nil
+}
+
+func (s *apiKeyStore) rotate(ctx context.Context, ID string) (*APIKeyResponse, error) {
+	rawKey, hash, err := newRawAPIKey(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rotated bool
+	err = s.update(ctx, func(repository apiKeyRepository) error {
+		rotated, err = repository.rotate(ctx, ID, hash, time.Now())
+		return err
+	})
+
+	if err != nil || !rotated {
+		return nil, err
+	}
+
+	response := &APIKeyResponse{ID: ID, Key: rawKey}
+	s.mux.RLock()
+	if key := lookupAPIKey(s.keys, ID); key != nil {
+		response.Expiry = key.Expiry
+	}
+	s.mux.RUnlock()
+	return response, nil
+}
+
+func (s *apiKeyStore) revoke(ctx context.Context, ID string) (bool, error) {
+	var revoked bool
+	err := s.update(ctx, func(repository apiKeyRepository) error {
+		var err error
+		revoked, err = repository.revoke(ctx, ID)
+		return err
+	})
+
+	return revoked, err
+}
+
+func lookupAPIKey(keys []*ManagedAPIKey, ID string) *ManagedAPIKey {
+	for _, key := range keys {
+		if key.ID == ID {
+			return key
+		}
+	}
+
+	return nil
+}
+
+//list returns keys without hashes with usage counters
+func (s *apiKeyStore) list() []*ManagedAPIKey {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	result := make([]*ManagedAPIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		aCopy := *key
+		aCopy.Hash = ""
+		aCopy.Usage = s.usage[key.ID].merge(key.Usage)
+		result = append(result, &aCopy)
+	}
+
+	return result
+}
+
+//authorize returns managed key matching the request API key header, error if the key is not permitted
+func (s *apiKeyStore) authorize(request *http.Request, URIPath string) (*ManagedAPIKey, error) {
+	value := request.Header.Get(s.config.Header)
+	if value == "" {
+		return nil, nil
+	}
+
+	s.mux.RLock()
+	key, ok := s.byHash[hashAPIKey(value)]
+	var counter *apiKeyCounter
+	if ok {
+		counter = s.usage[key.ID]
+	}
+	s.mux.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+
+	if !key.Enabled {
+		return key, fmt.Errorf("API key %v was revoked", key.ID)
+	}
+
+	if key.Expiry != nil && time.Now().After(*key.Expiry) {
+		return key, fmt.Errorf("API key %v has expired", key.ID)
+	}
+
+	if !key.permits(request.Method, router.AsRelative(URIPath)) {
+		return key, fmt.Errorf("API key %v is not permitted to %v %v", key.ID, request.Method, URIPath)
+	}
+
+	counter.use()
+	s.flushIfNeeded()
+	return key, nil
+}
+
+//flushIfNeeded persists usage counters in the background once per UsageFlushSec
+func (s *apiKeyStore) flushIfNeeded() {
+	interval := time.Duration(s.config.UsageFlushSec) * time.Second
+	if time.Since(time.Unix(0, atomic.LoadInt64(&s.flushed))) < interval {
+		return
+	}
+
+	if !atomic.CompareAndSwapInt32(&s.flushing, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&s.flushing, 0)
+		if err := s.update(context.Background(), nil); err != nil {
+			fmt.Printf("[WARN] failed to persist API keys usage: %v\n", err.Error())
+		}
+		atomic.StoreInt64(&s.flushed, time.Now().UnixNano())
+	}()
+}
+
+func (s *apiKeyStore) requires(URIPath string) bool {
+	URIPath = router.AsRelative(URIPath)
+	for _, prefix := range s.config.Required {
+		if strings.HasPrefix(URIPath, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k *ManagedAPIKey) permits(method, URIPath string) bool {
+	for _, scope := range k.Scopes {
+		if scope.matches(method, URIPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *APIKeyScope) matches(method, URIPath string) bool {
+	if strings.HasSuffix(s.URI, "*") {
+		if !strings.HasPrefix(URIPath, strings.TrimSuffix(s.URI, "*")) {
+			return false
+		}
+	} else if URIPath != s.URI {
+		return false
+	}
+
+	if len(s.Methods) == 0 {
+		return true
+	}
+
+	for _, candidate := range s.Methods {
+		if strings.EqualFold(candidate, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *apiKeyCounter) use() {
+	if c == nil {
+		return
+	}
+
+	atomic.AddInt64(&c.count, 1)
+	atomic.StoreInt64(&c.lastUsed, time.Now().UnixNano())
+}
+
+//take returns and resets usage not persisted yet
+func (c *apiKeyCounter) take() *APIKeyUsage {
+	count := atomic.SwapInt64(&c.count, 0)
+	if count == 0 {
+		return nil
+	}
+
+	lastUsed := time.Unix(0, atomic.LoadInt64(&c.lastUsed))
+	return &APIKeyUsage{Count: count, LastUsed: &lastUsed}
+}
+
+//restore adds back usage that failed to persist
+func (c *apiKeyCounter) restore(usage *APIKeyUsage) {
+	atomic.AddInt64(&c.count, usage.Count)
+}
+
+//add returns persisted usage with the usage added
+func (u *APIKeyUsage) add(persisted *APIKeyUsage) *APIKeyUsage {
+	result := &APIKeyUsage{}
+	if persisted != nil {
+		*result = *persisted
+	}
+
+	result.Count += u.Count
+	if result.LastUsed == nil || (u.LastUsed != nil && u.LastUsed.After(*result.LastUsed)) {
+		result.LastUsed = u.LastUsed
+	}
+
+	return result
+}
+
+//merge returns persisted usage with usage not persisted yet
+func (c *apiKeyCounter) merge(persisted *APIKeyUsage) *APIKeyUsage {
+	result := &APIKeyUsage{}
+	if persisted != nil {
+		*result = *persisted
+	}
+
+	if c == nil {
+		return result
+	}
+
+	result.Count += atomic.LoadInt64(&c.count)
+	if lastUsed := atomic.LoadInt64(&c.lastUsed); lastUsed != 0 {
+		aTime := time.Unix(0, lastUsed)
+		if result.LastUsed == nil || aTime.After(*result.LastUsed) {
+			result.LastUsed = &aTime
+		}
+	}
+
+	return result
+}
+
+func newRawAPIKey(ID string) (string, string, error) {
+	secret, err := randomToken(24)
+	if err != nil {
+		return "", "", err
+	}
+
+	rawKey := ID + "." + secret
+	return rawKey, hashAPIKey(rawKey), nil
+}
+
+func hashAPIKey(rawKey string) string {
+	hash := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(hash[:])
+}
+
+func randomToken(size int) (string, error) {
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+//authorizeAPIKey checks managed API key, static API keys are checked as fallback when no managed key was presented
+func (r *Router) authorizeAPIKey(request *http.Request, URIPath string, viewPath string) int {
+	if r.apiKeys != nil {
+		key, err := r.apiKeys.authorize(request, URIPath)
+		if err != nil {
+			return http.StatusForbidden
+		}
+
+		if key != nil {
+			return 0
+		}
+	}
+
+	if !r.apiKeyMatches(URIPath, request) || !r.apiKeyMatches(viewPath, request) {
+		return http.StatusForbidden
+	}
+
+	if r.apiKeys != nil && r.apiKeys.requires(URIPath) && !r.hasStaticAPIKey(URIPath) {
+		return http.StatusUnauthorized
+	}
+
+	return 0
+}
+
+func (r *Router) hasStaticAPIKey(URIPath string) bool {
+	if r.apiKeyMatcher == nil {
+		return false
+	}
+
+	matched, err := r.apiKeyMatcher.MatchPrefix("", URIPath)
+	return err == nil && len(matched) > 0
+}
+
+func (r *Router) handleAPIKeys(writer http.ResponseWriter, request *http.Request) {
+	statusCode, err := r.handleAPIKeysWithErr(writer, request)
+	r.handleErrIfNeeded(writer, statusCode, err)
+}
+
+func (r *Router) handleAPIKeysWithErr(writer http.ResponseWriter, request *http.Request) (int, error) {
+	if r.apiKeys == nil {
+		return http.StatusNotFound, nil
+	}
+
+	if !r.authorizeAdmin(request) {
+		return http.StatusUnauthorized, nil
+	}
+
+	ID := strings.Trim(strings.TrimPrefix(router.AsRelative(request.URL.Path), r.metaConfig.APIKeyURI), "/")
+	var result interface{}
+	var rotated *APIKeyResponse
+	var revoked bool
+	var err error
+	switch request.Method {
+	case http.MethodGet:
+		result = r.apiKeys.list()
+	case http.MethodPost:
+		keyRequest := &APIKeyRequest{}
+		if err = json.NewDecoder(request.Body).Decode(keyRequest); err != nil {
+			return http.StatusBadRequest, err
+		}
+
+		if result, err = r.apiKeys.create(request.Context(), keyRequest); err != nil {
+			return http.StatusBadRequest, err
+		}
+	case http.MethodPut:
+		if rotated, err = r.apiKeys.rotate(request.Context(), ID); err != nil {
+			return http.StatusInternalServerError, err
+		}
+
+		if rotated == nil {
+			return http.StatusNotFound, nil
+		}
+		result = rotated
+	case http.MethodDelete:
+		if revoked, err = r.apiKeys.revoke(request.Context(), ID); err != nil {
+			return http.StatusInternalServerError, err
+		}
+
+		if !revoked {
+			return http.StatusNotFound, nil
+		}
+		return http.StatusOK, nil
+	default:
+		return http.StatusMethodNotAllowed, nil
+	}
+
+	JSON, err := json.Marshal(result)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(JSON)
+	return http.StatusOK, nil
+}
@@ -0,0 +1,224 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/option"
+	"github.com/viant/datly/view"
+	"time"
+)
+
+type (
+	//afsAPIKeys stores managed API keys as JSON file
+	afsAPIKeys struct {
+		fs  afs.Service
+		URL string
+	}
+
+	//sqlAPIKeys stores managed API keys in the connector table with the following columns:
+	//ID, OWNER, HASH, SCOPES (JSON), EXPIRY, ENABLED, CREATED, ROTATED, USAGE_COUNT, LAST_USED
+	sqlAPIKeys struct {
+		connector *view.Connector
+		table     string
+	}
+)
+
+func (a *afsAPIKeys) load(ctx context.Context) ([]*ManagedAPIKey, error) {
+	keys, _, err := a.read(ctx)
+	return keys, err
+}
+
+func (a *afsAPIKeys) read(ctx context.Context) ([]*ManagedAPIKey, *option.Generation, error) {
+	var keys []*ManagedAPIKey
+	generation := &option.Generation{}
+	if ok, _ := a.fs.Exists(ctx, a.URL); !ok {
+		return keys, generation, nil
+	}
+
+	data, err := a.fs.DownloadWithURL(ctx, a.URL, generation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = json.Unmarshal(data, &keys); err != nil {
+		return nil, nil, fmt.Errorf("invalid managed API keys %v: %w", a.URL, err)
+	}
+
+	return keys, generation, nil
+}
+
+//modify stores keys modified by fn, upload fails if keys were modified by other instance in the meantime
+//with storages supporting generation preconditions
+func (a *afsAPIKeys) modify(ctx context.Context, fn func(keys []*ManagedAPIKey) ([]*ManagedAPIKey, bool)) (bool, error) {
+	keys, generation, err := a.read(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	keys, modified := fn(keys)
+	if !modified {
+		return false, nil
+	}
+
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return false, err
+	}
+
+	return true, a.fs.Upload(ctx, a.URL, file.DefaultFileOsMode, bytes.NewReader(data), option.NewGeneration(true, generation.Generation))
+}
+
+func (a *afsAPIKeys) insert(ctx context.Context, key *ManagedAPIKey) error {
+	_, err := a.modify(ctx, func(keys []*ManagedAPIKey) ([]*ManagedAPIKey, bool) {
+		return append(keys, key), true
+	})
+
+	return err
+}
+
+func (a *afsAPIKeys) rotate(ctx context.Context, ID string, hash string, rotated time.Time) (bool, error) {
+	return a.modify(ctx, func(keys []*ManagedAPIKey) ([]*ManagedAPIKey, bool) {
+		key := lookupAPIKey(keys, ID)
+		if key == nil {
+			return keys, false
+		}
+
+		key.Hash = hash
+		key.Rotated = &rotated
+		return keys, true
+	})
+}
+
+func (a *afsAPIKeys) revoke(ctx context.Context, ID string) (bool, error) {
+	return a.modify(ctx, func(keys []*ManagedAPIKey) ([]*ManagedAPIKey, bool) {
+		key := lookupAPIKey(keys, ID)
+		if key == nil {
+			return keys, false
+		}
+
+		key.Enabled = false
+		return keys, true
+	})
+}
+
+func (a *afsAPIKeys) addUsage(ctx context.Context, usage map[string]*APIKeyUsage) error {
+	_, err := a.modify(ctx, func(keys []*ManagedAPIKey) ([]*ManagedAPIKey, bool) {
+		for _, key := range keys {
+			if pending, ok := usage[key.ID]; ok {
+				key.Usage = pending.add(key.Usage)
+			}
+		}
+
+		return keys, true
+	})
+
+	return err
+}
+
+func (s *sqlAPIKeys) load(ctx context.Context) ([]*ManagedAPIKey, error) {
+	db, err := s.connector.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT ID, OWNER, HASH, SCOPES, EXPIRY, ENABLED, CREATED, ROTATED, USAGE_COUNT, LAST_USED FROM "+s.table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*ManagedAPIKey
+	for rows.Next() {
+		key := &ManagedAPIKey{}
+		var scopes string
+		var expiry, rotated, lastUsed sql.NullTime
+		var usageCount sql.NullInt64
+		if err = rows.Scan(&key.ID, &key.Owner, &key.Hash, &scopes, &expiry, &key.Enabled, &key.Created, &rotated, &usageCount, &lastUsed); err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+			return nil, fmt.Errorf("invalid API key %v scopes: %w", key.ID, err)
+		}
+
+		key.Expiry = nullTime(expiry)
+		key.Rotated = nullTime(rotated)
+		if usageCount.Valid || lastUsed.Valid {
+			key.Usage = &APIKeyUsage{Count: usageCount.Int64, LastUsed: nullTime(lastUsed)}
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (s *sqlAPIKeys) insert(ctx context.Context, key *ManagedAPIKey) error {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.exec(ctx, "INSERT INTO "+s.table+" (ID, OWNER, HASH, SCOPES, EXPIRY, ENABLED, CREATED, ROTATED, USAGE_COUNT, LAST_USED) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0, NULL)",
+		key.ID, key.Owner, key.Hash, string(scopes), key.Expiry, key.Enabled, key.Created, key.Rotated)
+	return err
+}
+
+func (s *sqlAPIKeys) rotate(ctx context.Context, ID string, hash string, rotated time.Time) (bool, error) {
+	return s.exec(ctx, "UPDATE "+s.table+" SET HASH = ?, ROTATED = ? WHERE ID = ?", hash, rotated, ID)
+}
+
+func (s *sqlAPIKeys) revoke(ctx context.Context, ID string) (bool, error) {
+	return s.exec(ctx, "UPDATE "+s.table+" SET ENABLED = ? WHERE ID = ?", false, ID)
+}
+
+//addUsage increments stored usage counters within a transaction, key columns are never written by usage flush
+func (s *sqlAPIKeys) addUsage(ctx context.Context, usage map[string]*APIKeyUsage) error {
+	db, err := s.connector.DB()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for ID, pending := range usage {
+		if _, err = tx.ExecContext(ctx, "UPDATE "+s.table+" SET USAGE_COUNT = COALESCE(USAGE_COUNT, 0) + ?, LAST_USED = ? WHERE ID = ?", pending.Count, pending.LastUsed, ID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+//exec returns true if statement affected any row
+func (s *sqlAPIKeys) exec(ctx context.Context, SQL string, args ...interface{}) (bool, error) {
+	db, err := s.connector.DB()
+	if err != nil {
+		return false, err
+	}
+
+	result, err := db.ExecContext(ctx, SQL, args...)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func nullTime(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.Time
+}
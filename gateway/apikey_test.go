@@ -0,0 +1,191 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+	"github.com/viant/datly/gateway/runtime/meta"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/view"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestAPIKeyStore_Authorize(t *testing.T) {
+	ctx := context.Background()
+	config := &ManagedAPIKeys{URL: "mem://localhost/datly/apikeys.json", Required: []string{"/v1/api/secure/"}}
+	config.Init()
+	store, err := newAPIKeyStore(ctx, config, afs.New())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	created, err := store.create(ctx, &APIKeyRequest{Owner: "dev", Scopes: []*APIKeyScope{{URI: "/v1/api/dev/*", Methods: []string{"GET"}}}})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	revoked, err := store.create(ctx, &APIKeyRequest{Owner: "ops", Scopes: []*APIKeyScope{{URI: "/v1/api/dev/*"}}})
+	assert.Nil(t, err)
+	_, err = store.revoke(ctx, revoked.ID)
+	assert.Nil(t, err)
+
+	useCases := []struct {
+		description string
+		method      string
+		URI         string
+		key         string
+		expectKey   bool
+		expectErr   bool
+	}{
+		{description: "key within scope", method: "GET", URI: "/v1/api/dev/events", key: created.Key, expectKey: true},
+		{description: "method out of scope", method: "POST", URI: "/v1/api/dev/events", key: created.Key, expectKey: true, expectErr: true},
+		{description: "URI out of scope", method: "GET", URI: "/v1/api/prod/events", key: created.Key, expectKey: true, expectErr: true},
+		{description: "revoked key", method: "GET", URI: "/v1/api/dev/events", key: revoked.Key, expectKey: true, expectErr: true},
+		{description: "unknown key falls back to static keys", method: "GET", URI: "/v1/api/dev/events", key: "abc"},
+	}
+
+	for _, useCase := range useCases {
+		request, _ := http.NewRequest(useCase.method, "http://localhost"+useCase.URI, nil)
+		request.Header.Set(config.Header, useCase.key)
+		key, err := store.authorize(request, useCase.URI)
+		assert.Equal(t, useCase.expectKey, key != nil, useCase.description)
+		assert.Equal(t, useCase.expectErr, err != nil, useCase.description)
+	}
+
+	reloaded, err := newAPIKeyStore(ctx, config, afs.New())
+	assert.Nil(t, err)
+	keys := reloaded.list()
+	assert.Equal(t, 2, len(keys))
+	assert.Equal(t, "", keys[0].Hash)
+	assert.Equal(t, int64(1), store.list()[0].Usage.Count)
+	assert.True(t, store.requires("/v1/api/secure/events"))
+}
+
+func TestAPIKeyStore_Connector(t *testing.T) {
+	ctx := context.Background()
+	dbLocation := "/tmp/datly_api_keys_test.db"
+	_ = os.Remove(dbLocation)
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE DATLY_API_KEYS (ID TEXT PRIMARY KEY, OWNER TEXT, HASH TEXT, SCOPES TEXT, EXPIRY DATETIME, ENABLED BOOLEAN, CREATED DATETIME, ROTATED DATETIME, USAGE_COUNT INTEGER, LAST_USED DATETIME)")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	config := &ManagedAPIKeys{Connector: &view.Connector{Name: "keys", Driver: "sqlite3", DSN: dbLocation}}
+	config.Init()
+	store, err := newAPIKeyStore(ctx, config, afs.New())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	created, err := store.create(ctx, &APIKeyRequest{Owner: "dev", Scopes: []*APIKeyScope{{URI: "/v1/api/dev/*"}}, ExpiryInSec: 3600})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost/v1/api/dev/events", nil)
+	request.Header.Set(config.Header, created.Key)
+	for i := 0; i < 3; i++ {
+		key, err := store.authorize(request, "/v1/api/dev/events")
+		assert.NotNil(t, key)
+		assert.Nil(t, err)
+	}
+
+	other, err := newAPIKeyStore(ctx, config, afs.New())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	_, err = other.revoke(ctx, created.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, store.update(ctx, nil), "usage flush after revoke by other instance")
+
+	reloaded, err := newAPIKeyStore(ctx, config, afs.New())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	keys := reloaded.list()
+	if !assert.Equal(t, 1, len(keys)) {
+		return
+	}
+
+	assert.Equal(t, "dev", keys[0].Owner)
+	assert.False(t, keys[0].Enabled)
+	assert.NotNil(t, keys[0].Expiry)
+	assert.Equal(t, int64(3), keys[0].Usage.Count)
+
+	_, err = store.authorize(request, "/v1/api/dev/events")
+	assert.NotNil(t, err, "revoked by other instance")
+
+	_, err = other.authorize(request, "/v1/api/dev/events")
+	assert.NotNil(t, err)
+	assert.Nil(t, other.update(ctx, nil))
+	keys = other.list()
+	assert.False(t, keys[0].Enabled)
+	assert.Equal(t, int64(3), keys[0].Usage.Count, "usage of revoked key is not counted")
+}
+
+func TestRouter_AuthorizeAPIKey(t *testing.T) {
+	ctx := context.Background()
+	config := &ManagedAPIKeys{URL: "mem://localhost/datly/router_apikeys.json"}
+	config.Init()
+	store, err := newAPIKeyStore(ctx, config, afs.New())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	created, err := store.create(ctx, &APIKeyRequest{Owner: "dev", Scopes: []*APIKeyScope{{URI: "/v1/api/*"}}})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	aRouter := &Router{
+		apiKeys:       store,
+		apiKeyMatcher: newApiKeyMatcher(router.APIKeys{{URI: "/v1/api/dev/", Header: "X-Static-Key", Value: "static"}}),
+		config:        &Config{AdminKey: &AdminKey{Header: defaultAdminKeyHeader, _hash: adminKeyHash("admin")}},
+		metaConfig:    &meta.Config{APIKeyURI: "/v1/api/meta/api-keys"},
+	}
+
+	useCases := []struct {
+		description string
+		URI         string
+		headers     map[string]string
+		expect      int
+	}{
+		{description: "managed key with static key", URI: "/v1/api/dev/events", headers: map[string]string{config.Header: created.Key, "X-Static-Key": "static"}},
+		{description: "managed key replaces static key", URI: "/v1/api/dev/events", headers: map[string]string{config.Header: created.Key}},
+		{description: "static key fallback", URI: "/v1/api/dev/events", headers: map[string]string{"X-Static-Key": "static"}},
+		{description: "unknown managed key with static key fallback", URI: "/v1/api/dev/events", headers: map[string]string{config.Header: "abc", "X-Static-Key": "static"}},
+		{description: "without static key", URI: "/v1/api/dev/events", expect: http.StatusForbidden},
+		{description: "managed key out of scope with static key", URI: "/v2/api/dev/events", headers: map[string]string{config.Header: created.Key, "X-Static-Key": "static"}, expect: http.StatusForbidden},
+		{description: "managed key without static key", URI: "/v1/api/prod/events", headers: map[string]string{config.Header: created.Key}},
+		{description: "admin endpoint without admin key", URI: "/v1/api/meta/api-keys", headers: map[string]string{config.Header: created.Key}, expect: http.StatusUnauthorized},
+		{description: "admin endpoint with invalid admin key", URI: "/v1/api/meta/api-keys", headers: map[string]string{defaultAdminKeyHeader: "abc"}, expect: http.StatusUnauthorized},
+		{description: "admin endpoint with admin key", URI: "/v1/api/meta/api-keys", headers: map[string]string{defaultAdminKeyHeader: "admin"}, expect: http.StatusOK},
+	}
+
+	for _, useCase := range useCases {
+		request, _ := http.NewRequest(http.MethodGet, "http://localhost"+useCase.URI, nil)
+		for k, v := range useCase.headers {
+			request.Header.Set(k, v)
+		}
+
+		actual := aRouter.authorizeAPIKey(request, useCase.URI, useCase.URI)
+		if actual == 0 && useCase.URI == aRouter.metaConfig.APIKeyURI {
+			actual, _ = aRouter.handleAPIKeysWithErr(httptest.NewRecorder(), request)
+		}
+
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
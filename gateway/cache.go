@@ -0,0 +1,208 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/view"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCacheTagsTable     = "DATLY_CACHE_TAGS"
+	defaultCacheTagsRefreshMs = 1000
+)
+
+type (
+	//CachePurge represents purged cache tags
+	CachePurge struct {
+		Purged []string
+	}
+
+	//CacheTags represents cache tag generations shared by datly instances through the connector Table
+	CacheTags struct {
+		Connector *view.Connector
+		Table     string //connector table with TAG (unique) and GENERATION columns, DATLY_CACHE_TAGS by default
+		RefreshMs int    //interval of loading tags purged by other instances, 1000 by default
+	}
+
+	//sqlCacheTags stores cache tag generations in the connector table
+	sqlCacheTags struct {
+		connector *view.Connector
+		table     string
+	}
+)
+
+//Init initializes CacheTags
+func (c *CacheTags) Init() {
+	if c.Table == "" {
+		c.Table = defaultCacheTagsTable
+	}
+
+	if c.RefreshMs == 0 {
+		c.RefreshMs = defaultCacheTagsRefreshMs
+	}
+}
+
+func initCacheTags(ctx context.Context, config *CacheTags) error {
+	if config == nil {
+		return nil
+	}
+
+	if config.Connector == nil {
+		return fmt.Errorf("cache tags connector was empty")
+	}
+
+	if err := config.Connector.Init(ctx, nil); err != nil {
+		return err
+	}
+
+	store := &sqlCacheTags{connector: config.Connector, table: config.Table}
+	return view.CacheTags.UseStore(ctx, store, time.Duration(config.RefreshMs)*time.Millisecond)
+}
+
+//PurgeCache invalidates cached view and route responses tagged with any of the tags
+func PurgeCache(ctx context.Context, tags ...string) error {
+	return view.CacheTags.Purge(ctx, tags...)
+}
+
+//PurgeViewCache invalidates cached data of the views
+func PurgeViewCache(ctx context.Context, viewNames ...string) error {
+	return PurgeCache(ctx, cacheTags(view.TagView, viewNames)...)
+}
+
+//PurgeRouteCache invalidates cached responses of the routes
+func PurgeRouteCache(ctx context.Context, URIs ...string) error {
+	return PurgeCache(ctx, cacheTags(view.TagRoute, URIs)...)
+}
+
+func cacheTags(kind string, values []string) []string {
+	var result []string
+	for _, value := range values {
+		if kind == view.TagTable {
+			value = strings.ToUpper(value)
+		}
+		result = append(result, view.CacheTag(kind, value))
+	}
+
+	return result
+}
+
+func (r *Router) handleCache(writer http.ResponseWriter, request *http.Request) {
+	statusCode, err := r.handleCacheWithErr(writer, request)
+	r.handleErrIfNeeded(writer, statusCode, err)
+}
+
+func (r *Router) handleCacheWithErr(writer http.ResponseWriter, request *http.Request) (int, error) {
+	if !r.authorizeAdmin(request) {
+		return http.StatusUnauthorized, nil
+	}
+
+	var result interface{}
+	switch request.Method {
+	case http.MethodGet:
+		result = view.CacheTags.Snapshot()
+	case http.MethodPost, http.MethodDelete:
+		query := request.URL.Query()
+		tags := query["tag"]
+		tags = append(tags, cacheTags(view.TagView, query["view"])...)
+		tags = append(tags, cacheTags(view.TagTable, query["table"])...)
+		for _, URI := range query["route"] {
+			tags = append(tags, router.RouteCacheTag(URI))
+		}
+
+		if len(tags) == 0 {
+			return http.StatusBadRequest, nil
+		}
+
+		if err := PurgeCache(request.Context(), tags...); err != nil {
+			return http.StatusInternalServerError, err
+		}
+		result = &CachePurge{Purged: tags}
+	default:
+		return http.StatusMethodNotAllowed, nil
+	}
+
+	JSON, err := json.Marshal(result)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(JSON)
+	return http.StatusOK, nil
+}
+
+func (s *sqlCacheTags) Load(ctx context.Context) (map[string]uint64, error) {
+	db, err := s.connector.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT TAG, GENERATION FROM "+s.table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[string]uint64{}
+	for rows.Next() {
+		var tag string
+		var generation uint64
+		if err = rows.Scan(&tag, &generation); err != nil {
+			return nil, err
+		}
+		result[tag] = generation
+	}
+
+	return result, rows.Err()
+}
+
+//Increment increments tags generations, each tag generation is incremented by single atomic statement
+func (s *sqlCacheTags) Increment(ctx context.Context, tags []string) error {
+	db, err := s.connector.DB()
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if err = s.increment(ctx, db, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//increment updates tag generation in place, tag row is inserted if it doesn't exist yet,
+//in case other instance inserted it in the meantime (TAG unique constraint violation) update is repeated
+func (s *sqlCacheTags) increment(ctx context.Context, db *sql.DB, tag string) error {
+	updated, err := s.update(ctx, db, tag)
+	if err != nil || updated {
+		return err
+	}
+
+	if _, err = db.ExecContext(ctx, "INSERT INTO "+s.table+" (TAG, GENERATION) VALUES (?, 1)", tag); err == nil {
+		return nil
+	}
+
+	if updated, _ = s.update(ctx, db, tag); updated {
+		return nil
+	}
+
+	return err
+}
+
+func (s *sqlCacheTags) update(ctx context.Context, db *sql.DB, tag string) (bool, error) {
+	result, err := db.ExecContext(ctx, "UPDATE "+s.table+" SET GENERATION = GENERATION + 1 WHERE TAG = ?", tag)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
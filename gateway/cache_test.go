@@ -0,0 +1,120 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/view"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheTags_Shared(t *testing.T) {
+	ctx := context.Background()
+	dbLocation := "/tmp/datly_cache_tags_test.db"
+	_ = os.Remove(dbLocation)
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE DATLY_CACHE_TAGS (TAG TEXT PRIMARY KEY, GENERATION INTEGER)")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	config := &CacheTags{Connector: &view.Connector{Name: "tags", Driver: "sqlite3", DSN: dbLocation}}
+	config.Init()
+	if !assert.Nil(t, config.Connector.Init(ctx, nil)) {
+		return
+	}
+
+	store := &sqlCacheTags{connector: config.Connector, table: config.Table}
+	first, second := view.NewTagGenerations(), view.NewTagGenerations()
+	assert.Nil(t, first.UseStore(ctx, store, time.Hour))
+	assert.Nil(t, second.UseStore(ctx, store, time.Millisecond))
+
+	tags := []string{"view:events", "table:EVENTS"}
+	assert.Nil(t, first.Purge(ctx, "table:EVENTS", "table:EVENTS"))
+	assert.Equal(t, "table:EVENTS=2", first.Generation(tags))
+	assert.Eventually(t, func() bool {
+		return second.Generation(tags) == "table:EVENTS=2"
+	}, time.Second, 5*time.Millisecond, "purge visible to other instance")
+
+	assert.Nil(t, second.Purge(ctx, "view:events"))
+	assert.Equal(t, "table:EVENTS=2,view:events=1", second.Generation(tags))
+
+	restarted := view.NewTagGenerations()
+	assert.Nil(t, restarted.UseStore(ctx, store, time.Hour))
+	assert.Equal(t, "table:EVENTS=2,view:events=1", restarted.Generation(tags), "generations survive restart")
+}
+
+func TestSqlCacheTags_Increment(t *testing.T) {
+	ctx := context.Background()
+	dbLocation := "/tmp/datly_cache_tags_increment_test.db"
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec("DROP TABLE IF EXISTS DATLY_CACHE_TAGS; CREATE TABLE DATLY_CACHE_TAGS (TAG TEXT PRIMARY KEY, GENERATION INTEGER)")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	instances := 8
+	wg := sync.WaitGroup{}
+	wg.Add(instances)
+	for i := 0; i < instances; i++ {
+		connector := &view.Connector{Name: "tags", Driver: "sqlite3", DSN: dbLocation + "?_busy_timeout=5000"}
+		if !assert.Nil(t, connector.Init(ctx, nil)) {
+			return
+		}
+
+		store := &sqlCacheTags{connector: connector, table: defaultCacheTagsTable}
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, store.Increment(ctx, []string{"table:EVENTS"}), "concurrent increment")
+		}()
+	}
+	wg.Wait()
+
+	var generation uint64
+	assert.Nil(t, db.QueryRow("SELECT GENERATION FROM DATLY_CACHE_TAGS WHERE TAG = ?", "table:EVENTS").Scan(&generation))
+	assert.Equal(t, uint64(instances), generation, "no increment lost")
+}
+
+func TestRouter_HandleCache(t *testing.T) {
+	aRouter := &Router{config: &Config{AdminKey: &AdminKey{Header: defaultAdminKeyHeader, _hash: adminKeyHash("admin")}}}
+	useCases := []struct {
+		description string
+		method      string
+		adminKey    string
+		expect      int
+	}{
+		{description: "purge without admin key", method: http.MethodPost, expect: http.StatusUnauthorized},
+		{description: "purge with invalid admin key", method: http.MethodPost, adminKey: "abc", expect: http.StatusUnauthorized},
+		{description: "purge with admin key", method: http.MethodPost, adminKey: "admin", expect: http.StatusOK},
+		{description: "snapshot without admin key", method: http.MethodGet, expect: http.StatusUnauthorized},
+	}
+
+	for _, useCase := range useCases {
+		request, _ := http.NewRequest(useCase.method, "http://localhost/v1/api/meta/cache?view=events", nil)
+		if useCase.adminKey != "" {
+			request.Header.Set(defaultAdminKeyHeader, useCase.adminKey)
+		}
+
+		actual, err := aRouter.handleCacheWithErr(httptest.NewRecorder(), request)
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+
+	view.CacheTags = view.NewTagGenerations()
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"github.com/viant/afs"
 	"github.com/viant/datly/auth/cognito"
+	"github.com/viant/datly/auth/oidc"
 	"github.com/viant/datly/auth/secret"
 	"github.com/viant/datly/gateway/runtime/meta"
+	"github.com/viant/datly/logger"
 	"github.com/viant/datly/router"
 	"github.com/viant/scy/auth/jwt/signer"
 	"github.com/viant/scy/auth/jwt/verifier"
@@ -29,6 +31,7 @@ type (
 		JWTValidator         *verifier.Config
 		JwtSigner            *signer.Config
 		Cognito              *cognito.Config
+		OIDC                 *oidc.Config
 		Meta                 meta.Config
 		APIKeys              router.APIKeys
 		AutoDiscovery        *bool
@@ -36,12 +39,21 @@ type (
 		DisableCors          bool
 		RevealMetric         *bool
 		CacheConnectorPrefix string
+		SlowQueryLogSize     int
+		ShadowLogSize        int
+		WarmupHistorySize    int //number of recorded scheduled warmup runs
+		Versioning           *Versioning
+		ManagedAPIKeys       *ManagedAPIKeys
+		AdminKey             *AdminKey //credential required by meta endpoints changing gateway state
+		CacheTags            *CacheTags
 	}
 
 	ChangeDetection struct {
 		NumOfRetries     int
 		RetryIntervalInS int
+		DrainTimeoutInS  int
 		_retry           time.Duration
+		_drain           time.Duration
 	}
 )
 
@@ -54,7 +66,12 @@ func (d *ChangeDetection) Init() {
 		d.RetryIntervalInS = 60
 	}
 
+	if d.DrainTimeoutInS == 0 {
+		d.DrainTimeoutInS = 60
+	}
+
 	d._retry = time.Second * time.Duration(d.RetryIntervalInS)
+	d._drain = time.Second * time.Duration(d.DrainTimeoutInS)
 }
 
 func (c *Config) Validate() error {
@@ -74,12 +91,28 @@ func (c *Config) Init() {
 		c.SyncFrequencyMs = 5000
 	}
 
+	if c.SlowQueryLogSize == 0 {
+		c.SlowQueryLogSize = logger.DefaultSlowQueryLogSize
+	}
+
 	if c.ChangeDetection == nil {
 		c.ChangeDetection = &ChangeDetection{}
 	}
 
+	if c.Versioning == nil {
+		c.Versioning = &Versioning{}
+	}
+
 	c.Meta.Init()
 	c.ChangeDetection.Init()
+	c.Versioning.Init()
+	if c.ManagedAPIKeys != nil {
+		c.ManagedAPIKeys.Init()
+	}
+
+	if c.CacheTags != nil {
+		c.CacheTags.Init()
+	}
 }
 
 func NewConfigFromURL(ctx context.Context, URL string) (*Config, error) {
@@ -0,0 +1,211 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/view"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	HealthUp       = "UP"
+	HealthDown     = "DOWN"
+	HealthDegraded = "DEGRADED"
+
+	DependencyConnector = "connector"
+	DependencyCache     = "cache"
+	DependencyRoutes    = "routes"
+)
+
+type (
+	//Health represents liveness or readiness check result
+	Health struct {
+		Status       string
+		Elapsed      string              `json:",omitempty"`
+		Dependencies []*DependencyHealth `json:",omitempty"`
+		Routes       *RoutesStatus       `json:",omitempty"`
+	}
+
+	//DependencyHealth represents single dependency check result
+	DependencyHealth struct {
+		Kind      string
+		Name      string
+		Status    string
+		Latency   string   `json:",omitempty"`
+		LatencyMs int      `json:",omitempty"`
+		Error     string   `json:",omitempty"`
+		Views     []string `json:",omitempty"`
+	}
+
+	healthCheck struct {
+		dependency *DependencyHealth
+		ping       func(ctx context.Context) error
+	}
+)
+
+func (r *Router) handleLiveness(writer http.ResponseWriter) {
+	statusCode, err := r.writeHealth(writer, &Health{Status: HealthUp})
+	r.handleErrIfNeeded(writer, statusCode, err)
+}
+
+func (r *Router) handleReadiness(writer http.ResponseWriter, request *http.Request) {
+	health := r.readiness(request.Context())
+	statusCode, err := r.writeHealth(writer, health)
+	r.handleErrIfNeeded(writer, statusCode, err)
+}
+
+func (r *Router) writeHealth(writer http.ResponseWriter, health *Health) (int, error) {
+	JSON, err := json.Marshal(health)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	if health.Status == HealthDown {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	writer.Write(JSON)
+	return http.StatusOK, nil
+}
+
+func (r *Router) readiness(ctx context.Context) *Health {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, r.metaConfig.ReadinessTimeout())
+	defer cancel()
+
+	checks := r.healthChecks()
+	wg := sync.WaitGroup{}
+	wg.Add(len(checks))
+	for i := range checks {
+		go func(check *healthCheck) {
+			defer wg.Done()
+			check.run(ctx)
+		}(checks[i])
+	}
+	wg.Wait()
+
+	health := &Health{Status: HealthUp}
+	for _, check := range checks {
+		health.Dependencies = append(health.Dependencies, check.dependency)
+		if check.dependency.Status == HealthDown {
+			health.Status = HealthDown
+		}
+	}
+
+	if r.routesStatus != nil {
+		routesStatus := r.routesStatus.snapshot()
+		health.Routes = &routesStatus
+		if dependency := r.routesDependency(&routesStatus); dependency != nil {
+			health.Dependencies = append(health.Dependencies, dependency)
+			if dependency.Status == HealthDown || health.Status == HealthUp {
+				health.Status = dependency.Status
+			}
+		}
+	}
+
+	health.Elapsed = time.Since(start).String()
+	return health
+}
+
+func (r *Router) routesDependency(status *RoutesStatus) *DependencyHealth {
+	if status.Error == "" {
+		return nil
+	}
+
+	dependency := &DependencyHealth{Kind: DependencyRoutes, Name: DependencyRoutes, Status: HealthDegraded, Error: status.Error}
+	if status.LoadedAt == nil {
+		dependency.Status = HealthDown
+	}
+
+	return dependency
+}
+
+func (r *Router) healthChecks() []*healthCheck {
+	connectors := map[string]*healthCheck{}
+	caches := map[string]*healthCheck{}
+	visited := map[*view.View]bool{}
+	for _, route := range r.routes {
+		appendViewHealthChecks(route.View, connectors, caches, visited)
+	}
+
+	result := make([]*healthCheck, 0, len(connectors)+len(caches))
+	for _, check := range connectors {
+		result = append(result, check)
+	}
+
+	for _, check := range caches {
+		result = append(result, check)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].dependency.Kind == result[j].dependency.Kind {
+			return result[i].dependency.Name < result[j].dependency.Name
+		}
+		return result[i].dependency.Kind < result[j].dependency.Kind
+	})
+
+	return result
+}
+
+func appendViewHealthChecks(aView *view.View, connectors, caches map[string]*healthCheck, visited map[*view.View]bool) {
+	if aView == nil || visited[aView] {
+		return
+	}
+	visited[aView] = true
+
+	if connector := aView.Connector; connector != nil {
+		key := connector.Driver + ":" + connector.Name
+		check, ok := connectors[key]
+		if !ok {
+			check = &healthCheck{dependency: &DependencyHealth{Kind: DependencyConnector, Name: connector.Name}, ping: connector.Ping}
+			connectors[key] = check
+		}
+		check.dependency.Views = append(check.dependency.Views, aView.Name)
+	}
+
+	if aCache := aView.Cache; aCache != nil {
+		key := aCache.Provider + ":" + aCache.Location + ":" + aView.Name
+		if _, ok := caches[key]; !ok {
+			caches[key] = &healthCheck{dependency: &DependencyHealth{Kind: DependencyCache, Name: aView.Name, Views: []string{aView.Name}}, ping: aCache.Ping}
+		}
+	}
+
+	for _, relation := range aView.With {
+		appendViewHealthChecks(&relation.Of.View, connectors, caches, visited)
+	}
+
+	if aView.Template == nil {
+		return
+	}
+
+	for _, param := range aView.Template.Parameters {
+		appendViewHealthChecks(param.View(), connectors, caches, visited)
+	}
+}
+
+func (c *healthCheck) run(ctx context.Context) {
+	start := time.Now()
+	err := c.ping(ctx)
+	elapsed := time.Since(start)
+	c.dependency.Latency = elapsed.String()
+	c.dependency.LatencyMs = int(elapsed.Milliseconds())
+	c.dependency.Status = HealthUp
+	if err != nil {
+		c.dependency.Status = HealthDown
+		c.dependency.Error = err.Error()
+	}
+}
+
+func routesCount(routers map[string]*router.Router) int {
+	result := 0
+	for _, aRouter := range routers {
+		result += len(aRouter.Routes(""))
+	}
+
+	return result
+}
@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"database/sql"
+	"encoding/json"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/gateway/runtime/meta"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/view"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRouter_HandleReadiness(t *testing.T) {
+	dbLocation := "/tmp/datly_health_test.db"
+	_ = os.Remove(dbLocation)
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	healthy := &router.Route{View: &view.View{
+		Name:      "events",
+		Connector: &view.Connector{Name: "dev", Driver: "sqlite3", DSN: dbLocation},
+		Cache:     &view.Cache{Provider: "mem://localhost/events"},
+	}}
+	failing := &router.Route{View: &view.View{
+		Name:      "audit",
+		Connector: &view.Connector{Name: "audit", Driver: "sqlite3", DSN: "/tmp/datly_health_test/missing/audit.db"},
+	}}
+
+	useCases := []struct {
+		description  string
+		routes       []*router.Route
+		expectCode   int
+		expectStatus string
+		expect       map[string]string
+	}{
+		{
+			description:  "healthy dependencies",
+			routes:       []*router.Route{healthy},
+			expectCode:   http.StatusOK,
+			expectStatus: HealthUp,
+			expect:       map[string]string{"connector:dev": HealthUp, "cache:events": HealthUp},
+		},
+		{
+			description:  "failing connector",
+			routes:       []*router.Route{healthy, failing},
+			expectCode:   http.StatusServiceUnavailable,
+			expectStatus: HealthDown,
+			expect:       map[string]string{"connector:dev": HealthUp, "connector:audit": HealthDown, "cache:events": HealthUp},
+		},
+	}
+
+	for _, useCase := range useCases {
+		metaConfig := &meta.Config{}
+		metaConfig.Init()
+		aRouter := &Router{routes: useCase.routes, metaConfig: metaConfig}
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, metaConfig.ReadinessURI, nil)
+		aRouter.handleReadiness(recorder, request)
+		assert.Equal(t, useCase.expectCode, recorder.Code, useCase.description)
+
+		health := &Health{}
+		if !assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), health), useCase.description) {
+			continue
+		}
+
+		assert.Equal(t, useCase.expectStatus, health.Status, useCase.description)
+		actual := map[string]string{}
+		for _, dependency := range health.Dependencies {
+			actual[dependency.Kind+":"+dependency.Name] = dependency.Status
+			if dependency.Status == HealthDown {
+				assert.NotEmpty(t, dependency.Error, useCase.description)
+			}
+		}
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
@@ -0,0 +1,20 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"github.com/viant/datly/auth/mtls"
+)
+
+//CertClaim maps verified client certificate header into mtls.Claims
+type CertClaim struct {
+}
+
+func (c *CertClaim) Value(ctx context.Context, raw interface{}, options ...interface{}) (interface{}, error) {
+	asString, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected parameter value type, wanted %T, got %T", asString, raw)
+	}
+
+	return mtls.ParseClaims(asString)
+}
@@ -3,6 +3,7 @@ package registry
 import (
 	"fmt"
 	"github.com/viant/datly/auth/gcp"
+	"github.com/viant/datly/auth/mtls"
 	"github.com/viant/datly/view"
 	"github.com/viant/scy/auth/jwt"
 	"reflect"
@@ -14,6 +15,7 @@ const (
 	CodecKeyAsStrings       = "AsStrings"
 	CodecKeyAsInts          = "AsInts"
 	CodecKeyCSV             = "CSV"
+	CodecKeyCertClaim       = "CertClaim"
 )
 
 var Codecs = view.NewCodecs(
@@ -25,6 +27,12 @@ var Codecs = view.NewCodecs(
 	StructQLFactory(""),
 )
 
+//RegisterCertClaim registers CertClaim codec, it should be called only when the TLS server verifies client certificates
+//and strips client supplied mtls.ClientCertHeader
+func RegisterCertClaim() {
+	Codecs.Register(view.NewCodec(CodecKeyCertClaim, &CertClaim{}, reflect.TypeOf(&mtls.Claims{})))
+}
+
 func unexpectedUseError(on interface{}) error {
 	return fmt.Errorf("unexpected use Value on %T", on)
 }
@@ -0,0 +1,186 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/view"
+	"net/http"
+	"time"
+)
+
+//acquireRouter returns current router generation, the caller has to release it once request is handled
+func (r *Service) acquireRouter() (*Router, bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	mainRouter := r.mainRouter
+	if mainRouter == nil {
+		return nil, false
+	}
+
+	mainRouter.inFlight.Add(1)
+	return mainRouter, true
+}
+
+func (r *Service) swapRouter(routers map[string]*router.Router, resources map[string]*view.Resource, mainRouter *Router) *Router {
+	view.RetainDBs(mainRouter, mainRouter.connectors())
+	r.mux.Lock()
+	previous := r.mainRouter
+	if previous != nil {
+		mainRouter.generation = previous.generation + 1
+	}
+	r.mainRouter = mainRouter
+	r.routersIndex = routers
+	r.dataResourcesIndex = resources
+	r.session = nil
+	r.mux.Unlock()
+
+	r.routesStatus.onLoaded(mainRouter.generation, routesCount(routers))
+	return previous
+}
+
+//drain waits for in-flight requests of the previous generation and releases database connections it retained,
+//connections are released only once the last in-flight request completes, even if it takes longer than drain timeout
+func (r *Service) drain(previous *Router) {
+	if previous == nil {
+		return
+	}
+
+	done := make(chan bool)
+	go func() {
+		previous.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(r.Config.ChangeDetection._drain):
+		fmt.Printf("[WARN] routes generation %v was not drained within %v, connections are released once remaining requests complete\n", previous.generation, r.Config.ChangeDetection._drain)
+		<-done
+	}
+
+	view.ReleaseDBs(previous)
+}
+
+func (r *Service) validateRouters(routers map[string]*router.Router) error {
+	var errors []error
+	var routes []*router.Route
+	for URL, aRouter := range routers {
+		for _, route := range aRouter.Routes("") {
+			routes = append(routes, route)
+			if err := validateView(route.View, map[*view.View]bool{}); err != nil {
+				errors = append(errors, fmt.Errorf("invalid route %v %v (%v): %w", route.Method, route.URI, URL, err))
+			}
+		}
+	}
+
+	for _, versions := range newRouteVersions(routes, make([]int, len(routes))) {
+		if err := versions.validate(); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	return r.combineErrors("routers", errors)
+}
+
+func validateView(aView *view.View, visited map[*view.View]bool) error {
+	if aView == nil {
+		return fmt.Errorf("view was empty")
+	}
+
+	if visited[aView] {
+		return nil
+	}
+	visited[aView] = true
+
+	if aView.Template == nil || aView.Schema == nil {
+		return fmt.Errorf("view %v was not initialized", aView.Name)
+	}
+
+	if aView.Connector == nil {
+		return fmt.Errorf("view %v connector was empty", aView.Name)
+	}
+
+	if _, err := aView.Db(); err != nil {
+		return fmt.Errorf("view %v connector %v: %w", aView.Name, aView.Connector.Name, err)
+	}
+
+	for _, relation := range aView.With {
+		if err := validateView(&relation.Of.View, visited); err != nil {
+			return err
+		}
+	}
+
+	for _, param := range aView.Template.Parameters {
+		if paramView := param.View(); paramView != nil {
+			if err := validateView(paramView, visited); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Router) release() {
+	r.inFlight.Done()
+}
+
+func (r *Router) connectors() []*view.Connector {
+	var result []*view.Connector
+	visited := map[*view.View]bool{}
+	for _, route := range r.routes {
+		appendViewConnectors(route.View, &result, visited)
+	}
+
+	return result
+}
+
+func appendViewConnectors(aView *view.View, connectors *[]*view.Connector, visited map[*view.View]bool) {
+	if aView == nil || visited[aView] {
+		return
+	}
+	visited[aView] = true
+
+	if aView.Connector != nil {
+		*connectors = append(*connectors, aView.Connector)
+	}
+
+	if aView.Cache != nil && aView.Cache.Warmup != nil && aView.Cache.Warmup.Connector != nil {
+		*connectors = append(*connectors, aView.Cache.Warmup.Connector)
+	}
+
+	for _, relation := range aView.With {
+		appendViewConnectors(&relation.Of.View, connectors, visited)
+	}
+
+	if aView.Template == nil {
+		return
+	}
+
+	for _, param := range aView.Template.Parameters {
+		appendViewConnectors(param.View(), connectors, visited)
+	}
+}
+
+func (r *Router) handleReloadStatus(writer http.ResponseWriter) {
+	statusCode, err := r.handleReloadStatusWithErr(writer)
+	r.handleErrIfNeeded(writer, statusCode, err)
+}
+
+func (r *Router) handleReloadStatusWithErr(writer http.ResponseWriter) (int, error) {
+	status := RoutesStatus{Generation: r.generation}
+	if r.routesStatus != nil {
+		status = r.routesStatus.snapshot()
+	}
+
+	JSON, err := json.Marshal(status)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(JSON)
+	return http.StatusOK, nil
+}
@@ -0,0 +1,193 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/router"
+	"github.com/viant/datly/view"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestService_drain(t *testing.T) {
+	useCases := []struct {
+		description string
+		inFlight    bool
+	}{
+		{
+			description: "previous generation without in-flight requests",
+		},
+		{
+			description: "in-flight request outlives drain timeout",
+			inFlight:    true,
+		},
+	}
+
+	newRouter := func(connectors ...*view.Connector) *Router {
+		result := &Router{}
+		for _, connector := range connectors {
+			result.routes = append(result.routes, &router.Route{View: &view.View{Connector: connector}})
+		}
+		return result
+	}
+
+	for i, useCase := range useCases {
+		var dbs []*sql.DB
+		var connectors []*view.Connector
+		for _, name := range []string{"shared", "previous", "current"} {
+			connector := &view.Connector{Name: name, Driver: "sqlite3", DSN: path.Join(os.TempDir(), fmt.Sprintf("datly_drain_%v_%v.db", i, name))}
+			db, err := connector.DB()
+			if !assert.Nil(t, err, useCase.description) {
+				return
+			}
+			connectors = append(connectors, connector)
+			dbs = append(dbs, db)
+		}
+
+		srv := &Service{Config: &Config{ChangeDetection: &ChangeDetection{_drain: 10 * time.Millisecond}}, routesStatus: newRoutesStatus()}
+		srv.swapRouter(nil, nil, newRouter(connectors[0], connectors[1]))
+		previous, _ := srv.acquireRouter()
+		if !useCase.inFlight {
+			previous.release()
+		}
+
+		assert.Equal(t, previous, srv.swapRouter(nil, nil, newRouter(connectors[0], connectors[2])), useCase.description)
+		current, _ := srv.acquireRouter()
+		current.release()
+		assert.Equal(t, previous.generation+1, current.generation, useCase.description)
+
+		drained := make(chan bool)
+		go func() {
+			srv.drain(previous)
+			close(drained)
+		}()
+
+		if useCase.inFlight {
+			time.Sleep(50 * time.Millisecond)
+			assert.Nil(t, dbs[1].Ping(), useCase.description)
+			previous.release()
+		}
+
+		select {
+		case <-drained:
+		case <-time.After(time.Second):
+			assert.Fail(t, "previous generation was not drained", useCase.description)
+			continue
+		}
+
+		assert.Nil(t, dbs[0].Ping(), useCase.description)
+		assert.NotNil(t, dbs[1].Ping(), useCase.description)
+		assert.Nil(t, dbs[2].Ping(), useCase.description)
+	}
+}
+
+func TestService_createRouterIfNeeded(t *testing.T) {
+	dbLocation := "/tmp/datly_reload_test.db"
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS EVENTS",
+		"CREATE TABLE EVENTS (ID INTEGER PRIMARY KEY, NAME TEXT)",
+		"INSERT INTO EVENTS VALUES (1, 'abc')",
+	} {
+		if _, err = db.Exec(SQL); !assert.Nil(t, err) {
+			return
+		}
+	}
+
+	routesURL := path.Join(os.TempDir(), "datly_reload_test")
+	routeURL := path.Join(routesURL, "events.yaml")
+	_ = os.RemoveAll(routesURL)
+	if !assert.Nil(t, os.MkdirAll(routesURL, 0755)) {
+		return
+	}
+	defer os.RemoveAll(routesURL)
+
+	route := func(connector string) string {
+		return fmt.Sprintf(`Routes:
+  - URI: "/v1/api/events"
+    Method: GET
+    View:
+      Ref: events
+
+Resource:
+  Views:
+    - Name: events
+      Connector:
+        Ref: %v
+      Table: EVENTS
+
+  Connectors:
+    - Name: db
+      Driver: sqlite3
+      DSN: %v
+`, connector, dbLocation)
+	}
+
+	if !assert.Nil(t, os.WriteFile(routeURL, []byte(route("db")), 0644)) {
+		return
+	}
+
+	srv, err := New(context.Background(), &Config{RouteURL: routesURL, SyncFrequencyMs: 1}, nil, nil, nil, nil, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer srv.Close()
+
+	useCases := []struct {
+		description      string
+		content          string
+		expectError      bool
+		expectGeneration int
+	}{
+		{
+			description:      "malformed route yaml",
+			content:          "Routes:\n  - URI: [\n",
+			expectError:      true,
+			expectGeneration: 1,
+		},
+		{
+			description:      "route view with unknown connector",
+			content:          route("unknown"),
+			expectError:      true,
+			expectGeneration: 1,
+		},
+		{
+			description:      "fixed route yaml",
+			content:          route("db"),
+			expectGeneration: 2,
+		},
+	}
+
+	for _, useCase := range useCases {
+		time.Sleep(10 * time.Millisecond)
+		if !assert.Nil(t, os.WriteFile(routeURL, []byte(useCase.content), 0644), useCase.description) {
+			continue
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		err = srv.createRouterIfNeeded(context.Background(), nil, nil, nil)
+		srv.routesStatus.onFailure(err)
+		assert.Equal(t, useCase.expectError, err != nil, useCase.description)
+
+		status := srv.RoutesStatus()
+		assert.Equal(t, useCase.expectGeneration, status.Generation, useCase.description)
+		assert.Equal(t, useCase.expectError, status.Error != "", useCase.description)
+
+		recorder := httptest.NewRecorder()
+		srv.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/api/events", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code, useCase.description)
+		assert.Contains(t, recorder.Body.String(), "abc", useCase.description)
+	}
+}
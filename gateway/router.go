@@ -2,9 +2,11 @@ package gateway
 
 import (
 	"encoding/json"
+	"fmt"
 	furl "github.com/viant/afs/url"
 	"github.com/viant/datly/gateway/runtime/meta"
 	"github.com/viant/datly/gateway/warmup"
+	"github.com/viant/datly/logger"
 	"github.com/viant/datly/router"
 	"github.com/viant/datly/router/openapi3"
 	"github.com/viant/datly/view"
@@ -14,13 +16,15 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
 //const wildcard = `{DATLY_WILDCARD}`
 
 type (
 	Router struct {
-		routersIndex    map[string]int
+		versions        map[string]*routeVersions
 		routers         []*router.Router
 		routeMatcher    *router.Matcher
 		config          *Config
@@ -33,6 +37,13 @@ type (
 		availableRoutes []Route
 		apiKeyMatcher   *router.Matcher
 		metaConfig      *meta.Config
+		routesStatus    *routesStatus
+		shadows         *shadowLog
+		apiKeys         *apiKeyStore
+		warmups         *warmup.Scheduler
+		slowQueries     *logger.SlowQueryLog
+		generation      int
+		inFlight        sync.WaitGroup
 	}
 
 	AvailableRoutesError struct {
@@ -88,7 +99,7 @@ func (p *Prefix) CorsEnabled() bool {
 //TODO: http handlers can be chosen by matcher. We can create wrapper for router.Matchable that will handle the request using Route/Routes etc.
 func NewRouter(routersIndex map[string]*router.Router, config *Config, metrics *gmetric.Service, statusHandler http.Handler, authorizer Authorizer) *Router {
 	routers := asRouterSlice(routersIndex)
-	matcher, routes, index := newMatcher(routers)
+	matcher, routes, versions := newMatcher(routers)
 
 	var metaConfig meta.Config
 	if config != nil {
@@ -99,10 +110,18 @@ func NewRouter(routersIndex map[string]*router.Router, config *Config, metrics *
 		metaConfig.StatusURI = router.AsRelative(metaConfig.StatusURI)
 		metaConfig.CacheWarmURI = router.AsRelative(metaConfig.CacheWarmURI)
 		metaConfig.ConfigURI = router.AsRelative(metaConfig.ConfigURI)
-	}
-
-	return &Router{
-		routersIndex:  index,
+		metaConfig.SlowQueryURI = router.AsRelative(metaConfig.SlowQueryURI)
+		metaConfig.LivenessURI = router.AsRelative(metaConfig.LivenessURI)
+		metaConfig.ReadinessURI = router.AsRelative(metaConfig.ReadinessURI)
+		metaConfig.ReloadURI = router.AsRelative(metaConfig.ReloadURI)
+		metaConfig.ShadowURI = router.AsRelative(metaConfig.ShadowURI)
+		metaConfig.APIKeyURI = router.AsRelative(metaConfig.APIKeyURI)
+		metaConfig.CacheURI = router.AsRelative(metaConfig.CacheURI)
+		metaConfig.WarmupURI = router.AsRelative(metaConfig.WarmupURI)
+	}
+
+	aRouter := &Router{
+		versions:      versions,
 		routers:       routers,
 		routeMatcher:  matcher,
 		config:        config,
@@ -116,6 +135,14 @@ func NewRouter(routersIndex map[string]*router.Router, config *Config, metrics *
 			metaConfig.CacheWarmURI,
 			metaConfig.OpenApiURI,
 			metaConfig.ConfigURI,
+			metaConfig.SlowQueryURI,
+			metaConfig.LivenessURI,
+			metaConfig.ReadinessURI,
+			metaConfig.ReloadURI,
+			metaConfig.ShadowURI,
+			metaConfig.APIKeyURI,
+			metaConfig.CacheURI,
+			metaConfig.WarmupURI,
 			config.APIPrefix,
 		}),
 		authorizer:      authorizer,
@@ -123,6 +150,9 @@ func NewRouter(routersIndex map[string]*router.Router, config *Config, metrics *
 		apiKeyMatcher:   newApiKeyMatcher(config.APIKeys),
 		metaConfig:      &metaConfig,
 	}
+
+	aRouter.initVersionCounters()
+	return aRouter
 }
 
 func newApiKeyMatcher(keys router.APIKeys) *router.Matcher {
@@ -188,6 +218,7 @@ func (r *Router) Handle(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
+	request = r.stripVersionPath(request)
 	errStatusCode, err := r.handle(writer, request)
 	r.handleErrIfNeeded(writer, errStatusCode, err)
 }
@@ -196,10 +227,14 @@ func (r *Router) handle(writer http.ResponseWriter, request *http.Request) (int,
 	urlPath := request.URL.Path
 	actualPrefix, viewPath := r.asAPIPrefix(urlPath)
 
-	if (actualPrefix != r.config.APIPrefix && !meta.IsAuthorized(request, r.config.Meta.AllowedSubnet)) || !r.apiKeyMatches(urlPath, request) || !r.apiKeyMatches(viewPath, request) {
+	if actualPrefix != r.config.APIPrefix && !meta.IsAuthorized(request, r.config.Meta.AllowedSubnet) {
 		return http.StatusForbidden, nil
 	}
 
+	if statusCode := r.authorizeAPIKey(request, urlPath, viewPath); statusCode != 0 {
+		return statusCode, nil
+	}
+
 	switch actualPrefix {
 	case r.metaConfig.MetricURI:
 		r.handleMetrics(writer, request)
@@ -207,6 +242,30 @@ func (r *Router) handle(writer http.ResponseWriter, request *http.Request) (int,
 	case r.metaConfig.ConfigURI:
 		r.handleConfig(writer)
 		return http.StatusOK, nil
+	case r.metaConfig.SlowQueryURI:
+		r.handleSlowQueries(writer, request)
+		return http.StatusOK, nil
+	case r.metaConfig.LivenessURI:
+		r.handleLiveness(writer)
+		return http.StatusOK, nil
+	case r.metaConfig.ReadinessURI:
+		r.handleReadiness(writer, request)
+		return http.StatusOK, nil
+	case r.metaConfig.ReloadURI:
+		r.handleReloadStatus(writer)
+		return http.StatusOK, nil
+	case r.metaConfig.ShadowURI:
+		r.handleShadows(writer, request)
+		return http.StatusOK, nil
+	case r.metaConfig.APIKeyURI:
+		r.handleAPIKeys(writer, request)
+		return http.StatusOK, nil
+	case r.metaConfig.CacheURI:
+		r.handleCache(writer, request)
+		return http.StatusOK, nil
+	case r.metaConfig.WarmupURI:
+		r.handleWarmupStatus(writer)
+		return http.StatusOK, nil
 	case r.metaConfig.OpenApiURI:
 		return r.matchByMultiRoutes(writer, request, viewPath)
 	case r.metaConfig.StatusURI:
@@ -222,16 +281,21 @@ func (r *Router) handle(writer http.ResponseWriter, request *http.Request) (int,
 }
 
 func (r *Router) matchByRoute(writer http.ResponseWriter, request *http.Request, viewPath string, actualPrefix string) (int, error) {
-	aRoute, aRouter, err := r.Match(request.Method, viewPath)
+	selected, err := r.matchVersion(request, viewPath)
 	if err != nil {
 		return http.StatusNotFound, r.availableRoutesErr(err)
 	}
 
+	aRoute := selected.route
 	if !r.apiKeyMatches(aRoute.URI, request) {
 		return http.StatusForbidden, nil
 	}
 
-	return r.handleRouteWithPrefix(writer, request, actualPrefix, aRouter, aRoute)
+	if onDone := selected.begin(writer); onDone != nil {
+		defer onDone(time.Now())
+	}
+
+	return r.handleRouteWithShadows(writer, request, actualPrefix, selected)
 }
 
 func (r *Router) handleRouteWithPrefix(writer http.ResponseWriter, request *http.Request, actualPrefix string, aRouter *router.Router, aRoute *router.Route) (int, error) {
@@ -292,7 +356,12 @@ func (r *Router) Match(method, URL string) (*router.Route, *router.Router, error
 		return nil, nil, err
 	}
 
-	return route, r.routers[r.routersIndex[combine(route.Method, route.URI)]], nil
+	versions, ok := r.versions[combine(route.Method, route.URI)]
+	if !ok {
+		return nil, nil, fmt.Errorf("not found route %v %v", route.Method, route.URI)
+	}
+
+	return versions.stable.route, r.routers[versions.stable.routerIndex], nil
 }
 
 func (r *Router) asAPIPrefix(URIPath string) (prefix string, path string) {
@@ -447,6 +516,27 @@ func (r *Router) handleConfigWithErr(writer http.ResponseWriter) (int, error) {
 	return http.StatusOK, nil
 }
 
+func (r *Router) handleSlowQueries(writer http.ResponseWriter, request *http.Request) {
+	statusCode, err := r.handleSlowQueriesWithErr(writer, request)
+	r.handleErrIfNeeded(writer, statusCode, err)
+}
+
+func (r *Router) handleSlowQueriesWithErr(writer http.ResponseWriter, request *http.Request) (int, error) {
+	if request.Method == http.MethodDelete {
+		r.slowQueries.Reset()
+		return http.StatusOK, nil
+	}
+
+	JSON, err := json.Marshal(r.slowQueries.Entries())
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(JSON)
+	return http.StatusOK, nil
+}
+
 func (r *Router) handleCacheWarmup(writer http.ResponseWriter, request *http.Request, route *router.Route) {
 	statusCode, err := r.handleCacheWarmupWithErr(writer, request, route)
 	r.handleErrIfNeeded(writer, statusCode, err)
@@ -464,6 +554,43 @@ func (r *Router) handleCacheWarmupWithErr(writer http.ResponseWriter, request *h
 	return http.StatusOK, nil
 }
 
+func (r *Router) handleWarmupStatus(writer http.ResponseWriter) {
+	statusCode, err := r.handleWarmupStatusWithErr(writer)
+	r.handleErrIfNeeded(writer, statusCode, err)
+}
+
+func (r *Router) handleWarmupStatusWithErr(writer http.ResponseWriter) (int, error) {
+	status := &warmup.Status{Jobs: []*warmup.Job{}, History: []*warmup.Run{}}
+	if r.warmups != nil {
+		status = r.warmups.Status()
+	}
+
+	JSON, err := json.Marshal(status)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(JSON)
+	return http.StatusOK, nil
+}
+
+//CacheableViews returns views with cache warmup of all routes
+func (r *Router) CacheableViews() []*view.View {
+	var result []*view.View
+	index := map[*view.View]bool{}
+	for _, route := range r.routes {
+		for _, aView := range router.ExtractCacheableViews(route) {
+			if !index[aView] {
+				index[aView] = true
+				result = append(result, aView)
+			}
+		}
+	}
+
+	return result
+}
+
 func (r *Router) ensureRequestURL(request *http.Request) error {
 	if request.URL != nil {
 		return nil
@@ -531,25 +658,25 @@ func asRouterSlice(routers map[string]*router.Router) []*router.Router {
 	return result
 }
 
-func newMatcher(routers []*router.Router) (*router.Matcher, []*router.Route, map[string]int) {
-	routesSize := 0
-	for _, r := range routers {
-		routesSize += len(r.Routes(""))
+func newMatcher(routers []*router.Router) (*router.Matcher, []*router.Route, map[string]*routeVersions) {
+	var routes []*router.Route
+	var routerIndexes []int
+	for i, aRouter := range routers {
+		for _, route := range aRouter.Routes("") {
+			routes = append(routes, route)
+			routerIndexes = append(routerIndexes, i)
+		}
 	}
 
-	routes, routerIndex := make([]*router.Route, routesSize), map[string]int{}
-
-	counter := 0
-	for i, aRouter := range routers {
-		routerRoutes := aRouter.Routes("")
-		for routeIndex, route := range routerRoutes {
-			routes[counter] = routerRoutes[routeIndex]
-			routerIndex[combine(route.Method, route.URI)] = i
-			counter++
+	versions := newRouteVersions(routes, routerIndexes)
+	matchables := make([]*router.Route, 0, len(versions))
+	for _, route := range routes {
+		if stable := versions[combine(route.Method, route.URI)].stable; stable != nil && stable.route == route {
+			matchables = append(matchables, route)
 		}
 	}
 
-	return router.NewRouteMatcher(routes), routes, routerIndex
+	return router.NewRouteMatcher(matchables), routes, versions
 }
 
 func combine(method string, uri string) string {
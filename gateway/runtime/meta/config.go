@@ -1,5 +1,7 @@
 package meta
 
+import "time"
+
 const (
 	//MetricURI represents default metric URIPrefix
 	MetricURI = "/v1/api/meta/metric/"
@@ -13,6 +15,24 @@ const (
 	OpenApiURI = "/v1/api/meta/openapi/"
 	//CacheWarmupURI URIPrefix default value
 	CacheWarmupURI = "/v1/api/cache/warmup/"
+	//SlowQueryURI represents default slow query log URIPrefix
+	SlowQueryURI = "/v1/api/meta/slow-query"
+	//LivenessURI represents default liveness probe URIPrefix
+	LivenessURI = "/v1/api/meta/liveness"
+	//ReadinessURI represents default readiness probe URIPrefix
+	ReadinessURI = "/v1/api/meta/readiness"
+	//ReloadURI represents default routes reload status URIPrefix
+	ReloadURI = "/v1/api/meta/reload"
+	//ShadowURI represents default shadow routes comparisons URIPrefix
+	ShadowURI = "/v1/api/meta/shadow"
+	//APIKeyURI represents default managed API keys admin URIPrefix
+	APIKeyURI = "/v1/api/meta/api-keys"
+	//CacheURI represents default cache tags purge URIPrefix
+	CacheURI = "/v1/api/meta/cache"
+	//WarmupURI represents default scheduled cache warmup status URIPrefix
+	WarmupURI = "/v1/api/meta/warmup"
+	//ReadinessTimeoutMs represents default readiness dependencies check timeout
+	ReadinessTimeoutMs = 3000
 )
 
 // Config represents meta config
@@ -24,7 +44,17 @@ type Config struct {
 	ViewURI       string
 	OpenApiURI    string
 	CacheWarmURI  string
+	SlowQueryURI  string
+	LivenessURI   string
+	ReadinessURI  string
+	ReloadURI     string
+	ShadowURI     string
+	APIKeyURI     string
+	CacheURI      string
+	WarmupURI     string
 	AllowedSubnet []string
+
+	ReadinessTimeoutMs int
 }
 
 // Init initialises config
@@ -51,4 +81,45 @@ func (m *Config) Init() {
 	if m.CacheWarmURI == "" {
 		m.CacheWarmURI = CacheWarmupURI
 	}
+
+	if m.SlowQueryURI == "" {
+		m.SlowQueryURI = SlowQueryURI
+	}
+
+	if m.LivenessURI == "" {
+		m.LivenessURI = LivenessURI
+	}
+
+	if m.ReadinessURI == "" {
+		m.ReadinessURI = ReadinessURI
+	}
+
+	if m.ReloadURI == "" {
+		m.ReloadURI = ReloadURI
+	}
+
+	if m.ShadowURI == "" {
+		m.ShadowURI = ShadowURI
+	}
+
+	if m.APIKeyURI == "" {
+		m.APIKeyURI = APIKeyURI
+	}
+
+	if m.CacheURI == "" {
+		m.CacheURI = CacheURI
+	}
+
+	if m.WarmupURI == "" {
+		m.WarmupURI = WarmupURI
+	}
+
+	if m.ReadinessTimeoutMs == 0 {
+		m.ReadinessTimeoutMs = ReadinessTimeoutMs
+	}
+}
+
+// ReadinessTimeout returns readiness dependencies check timeout
+func (m *Config) ReadinessTimeout() time.Duration {
+	return time.Duration(m.ReadinessTimeoutMs) * time.Millisecond
 }
@@ -1,11 +1,14 @@
 package endpoint
 
+import "github.com/viant/datly/auth/mtls"
+
 //Config defines standalone app endpoint
 type Config struct {
 	Port           int
 	ReadTimeoutMs  int
 	WriteTimeoutMs int
 	MaxHeaderBytes int
+	TLS            *mtls.Config
 }
 
 //Init initialises endpoint
@@ -3,6 +3,8 @@ package standalone
 import (
 	"context"
 	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/datly/auth/mtls"
 	"github.com/viant/datly/gateway"
 	"github.com/viant/datly/gateway/registry"
 	"github.com/viant/datly/gateway/runtime/standalone/handler"
@@ -19,6 +21,16 @@ import (
 type Server struct {
 	http.Server
 	Service *gateway.Service
+	tls     *mtls.Service
+}
+
+//ListenAndServe listens on TLS if endpoint TLS was configured, otherwise on plain HTTP
+func (r *Server) ListenAndServe() error {
+	if r.tls == nil {
+		return r.Server.ListenAndServe()
+	}
+
+	return r.Server.ListenAndServeTLS("", "")
 }
 
 // shutdownOnInterrupt server on interupts
@@ -54,6 +66,18 @@ func NewWithAuth(config *Config, auth gateway.Authorizer) (*Server, error) {
 		return nil, fmt.Errorf("gateway config was empty")
 	}
 
+	var certificates *mtls.Service
+	if config.Endpoint.TLS != nil {
+		var err error
+		if certificates, err = mtls.New(context.Background(), config.Endpoint.TLS, afs.New()); err != nil {
+			return nil, err
+		}
+
+		if config.Endpoint.TLS.VerifiesClient() {
+			registry.RegisterCertClaim()
+		}
+	}
+
 	service, err := gateway.SingletonWithConfig(
 		config.Config,
 		handler.NewStatus(config.Version, &config.Meta),
@@ -83,15 +107,20 @@ func NewWithAuth(config *Config, auth gateway.Authorizer) (*Server, error) {
 	//mux.HandleFunc(config.Config.APIPrefix, auth.Auth(service.Handle))
 	server := &Server{
 		Service: service,
+		tls:     certificates,
 		Server: http.Server{
 			Addr:           ":" + strconv.Itoa(config.Endpoint.Port),
-			Handler:        service,
+			Handler:        mtls.Handler(service),
 			ReadTimeout:    time.Millisecond * time.Duration(config.Endpoint.ReadTimeoutMs),
 			WriteTimeout:   time.Millisecond * time.Duration(config.Endpoint.WriteTimeoutMs),
 			MaxHeaderBytes: config.Endpoint.MaxHeaderBytes,
 		},
 	}
 
+	if certificates != nil {
+		server.TLSConfig = certificates.TLSConfig()
+	}
+
 	server.shutdownOnInterrupt()
 	return server, nil
 }
@@ -10,6 +10,8 @@ import (
 	furl "github.com/viant/afs/url"
 	"github.com/viant/cloudless/resource"
 	"github.com/viant/datly/auth/secret"
+	"github.com/viant/datly/gateway/warmup"
+	"github.com/viant/datly/logger"
 	"github.com/viant/datly/router"
 	"github.com/viant/datly/shared"
 	"github.com/viant/datly/view"
@@ -39,29 +41,44 @@ type (
 		cancelFn             context.CancelFunc
 		session              *Session
 		JWTSigner            *signer.Service
+		routesStatus         *routesStatus
+		shadows              *shadowLog
+		apiKeys              *apiKeyStore
+		warmups              *warmup.Scheduler
+		slowQueries          *logger.SlowQueryLog
 	}
 )
 
 func (r *Service) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	aRouter, ok := r.Router()
+	aRouter, ok := r.acquireRouter()
 	if !ok {
 		writer.WriteHeader(http.StatusNotFound)
+		return
 	}
 
+	defer aRouter.release()
 	writer = r.WrapResponseIfNeeded(writer)
 	aRouter.Handle(writer, request)
 }
 
 func (r *Service) Router() (*Router, bool) {
+	r.mux.RLock()
 	mainRouter := r.mainRouter
+	r.mux.RUnlock()
 	return mainRouter, mainRouter != nil
 }
 
+//RoutesStatus returns outcome of the latest routes load
+func (r *Service) RoutesStatus() RoutesStatus {
+	return r.routesStatus.snapshot()
+}
+
 func (r *Service) Close() error {
 	if r.cancelFn != nil {
 		r.cancelFn()
 	}
 
+	r.warmups.Close()
 	return nil
 }
 
@@ -91,6 +108,26 @@ func New(ctx context.Context, config *Config, statusHandler http.Handler, author
 		routersIndex:         map[string]*router.Router{},
 		mainRouter:           NewRouter(map[string]*router.Router{}, config, metrics, statusHandler, authorizer),
 		session:              NewSession(config.ChangeDetection),
+		routesStatus:         newRoutesStatus(),
+		shadows:              newShadowLog(config.ShadowLogSize),
+		warmups:              warmup.NewScheduler(config.WarmupHistorySize),
+		slowQueries:          logger.NewSlowQueryLog(config.SlowQueryLogSize),
+	}
+	srv.mainRouter.routesStatus = srv.routesStatus
+	srv.mainRouter.shadows = srv.shadows
+	srv.mainRouter.warmups = srv.warmups
+	srv.mainRouter.slowQueries = srv.slowQueries
+	if err = initAdminKey(ctx, config); err != nil {
+		return nil, err
+	}
+
+	if srv.apiKeys, err = newAPIKeyStore(ctx, config.ManagedAPIKeys, srv.fs); err != nil {
+		return nil, err
+	}
+	srv.mainRouter.apiKeys = srv.apiKeys
+
+	if err = initCacheTags(ctx, config.CacheTags); err != nil {
+		return nil, err
 	}
 
 	if config.JwtSigner != nil {
@@ -105,6 +142,7 @@ func New(ctx context.Context, config *Config, statusHandler http.Handler, author
 	}
 
 	err = srv.createRouterIfNeeded(ctx, metrics, statusHandler, authorizer)
+	srv.routesStatus.onFailure(err)
 	srv.detectChanges(metrics, statusHandler, authorizer)
 	fmt.Printf("initialised datly: %s\n", time.Now().Sub(start))
 	return srv, err
@@ -134,14 +172,19 @@ func (r *Service) createRouterIfNeeded(ctx context.Context, metrics *gmetric.Ser
 		return err
 	}
 
-	mainRouter := NewRouter(routers, r.Config, metrics, statusHandler, authorizer)
-	r.mux.Lock()
-	r.mainRouter = mainRouter
-	r.routersIndex = routers
-	r.dataResourcesIndex = resources
-	r.session = nil
-	r.mux.Unlock()
+	if err = r.validateRouters(routers); err != nil {
+		return err
+	}
 
+	mainRouter := NewRouter(routers, r.Config, metrics, statusHandler, authorizer)
+	mainRouter.routesStatus = r.routesStatus
+	mainRouter.shadows = r.shadows
+	mainRouter.apiKeys = r.apiKeys
+	mainRouter.warmups = r.warmups
+	mainRouter.slowQueries = r.slowQueries
+	previous := r.swapRouter(routers, resources, mainRouter)
+	r.warmups.Schedule(mainRouter.CacheableViews())
+	go r.drain(previous)
 	return nil
 }
 
@@ -155,6 +198,10 @@ func (r *Service) getRouters(ctx context.Context, fs afs.Service, resources map[
 		return r.routersIndex, false, nil
 	}
 
+	if viewResourcesChanged {
+		updatedMap = r.withAllRouters(updatedMap, removedMap)
+	}
+
 	routers = map[string]*router.Router{}
 	for routerURL := range r.routersIndex {
 		if (updatedMap[routerURL] || removedMap[routerURL]) && !changed {
@@ -189,6 +236,27 @@ func (r *Service) getRouters(ctx context.Context, fs afs.Service, resources map[
 	return routers, true, nil
 }
 
+//withAllRouters marks every loaded router as updated, since routers inherit from changed dependencies
+func (r *Service) withAllRouters(updatedMap, removedMap map[string]bool) map[string]bool {
+	result := map[string]bool{}
+	for URL := range updatedMap {
+		result[URL] = true
+	}
+
+	var reloaded []string
+	for URL := range r.routersIndex {
+		if removedMap[URL] || result[URL] {
+			continue
+		}
+
+		reloaded = append(reloaded, URL)
+		result[URL] = true
+	}
+
+	r.session.OnRouterUpdated(reloaded...)
+	return result
+}
+
 func (r *Service) getDataResources(ctx context.Context, fs afs.Service) (resources map[string]*view.Resource, changed bool, err error) {
 	updatedMap, removedMap, err := r.detectResourceChanges(ctx, fs)
 	if err != nil {
@@ -397,6 +465,7 @@ func (r *Service) detectChanges(metrics *gmetric.Service, statusHandler http.Han
 				break outer
 			default:
 				if err := r.createRouterIfNeeded(context.TODO(), metrics, statusHandler, authorizer); err != nil {
+					r.routesStatus.onFailure(err)
 					fmt.Printf("error occured while recreating routers: %v \n", err.Error())
 				}
 			}
@@ -478,6 +547,8 @@ func (r *Service) loadRouterResource(URL string, resources map[string]*view.Reso
 		routerResource.RevealMetric = r.Config.RevealMetric
 	}
 
+	routerResource.Resource.SlowQueries = r.slowQueries
+
 	return routerResource, routerResource.Init(ctx)
 }
 
@@ -0,0 +1,409 @@
+package gateway
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//DefaultShadowLogSize represents default number of recorded shadow comparisons
+	DefaultShadowLogSize = 100
+
+	maxShadowsInFlight = 16
+	maxShadowDiffs     = 20
+)
+
+type (
+	//ShadowComparison represents primary and shadow route responses comparison
+	ShadowComparison struct {
+		Method           string
+		URI              string
+		URL              string
+		Version          string
+		PrimaryStatus    int
+		ShadowStatus     int
+		PrimaryElapsedMs int
+		ShadowElapsedMs  int
+		DeltaMs          int
+		Matched          bool
+		Diffs            []string `json:",omitempty"`
+		Error            string   `json:",omitempty"`
+		Time             time.Time
+	}
+
+	//ShadowStats represents shadow route comparisons summary
+	ShadowStats struct {
+		Method       string
+		URI          string
+		Version      string
+		Requests     int
+		Mismatches   int
+		Errors       int
+		Skipped      int
+		AvgDeltaMs   int
+		totalDeltaMs int
+	}
+
+	//ShadowReport represents shadow routes stats and recent mismatches
+	ShadowReport struct {
+		Stats       []*ShadowStats
+		Comparisons []*ShadowComparison
+	}
+
+	shadowLog struct {
+		mux         sync.Mutex
+		size        int
+		comparisons []*ShadowComparison
+		stats       map[string]*ShadowStats
+		inFlight    chan bool
+	}
+
+	shadowResponse struct {
+		statusCode int
+		header     http.Header
+		body       []byte
+		elapsed    time.Duration
+	}
+
+	responseRecorder struct {
+		http.ResponseWriter
+		statusCode int
+		body       bytes.Buffer
+	}
+)
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *Router) handleRouteWithShadows(writer http.ResponseWriter, request *http.Request, actualPrefix string, selected *versionedRoute) (int, error) {
+	aRouter := r.routers[selected.routerIndex]
+	shadows := r.sampledShadows(request, actualPrefix, selected)
+	if len(shadows) == 0 {
+		return r.handleRouteWithPrefix(writer, request, actualPrefix, aRouter, selected.route)
+	}
+
+	shadowRequest := request.Clone(context.Background())
+	recorder := &responseRecorder{ResponseWriter: writer, statusCode: http.StatusOK}
+	started := time.Now()
+	statusCode, err := r.handleRouteWithPrefix(recorder, request, actualPrefix, aRouter, selected.route)
+	if err != nil {
+		return statusCode, err
+	}
+
+	primary := &shadowResponse{statusCode: recorder.statusCode, header: writer.Header(), body: recorder.body.Bytes(), elapsed: time.Since(started)}
+	for _, shadow := range shadows {
+		if !r.shadows.acquire(shadow) {
+			continue
+		}
+
+		r.inFlight.Add(1)
+		go r.runShadow(shadowRequest, shadow, primary)
+	}
+
+	return statusCode, nil
+}
+
+func (r *Router) sampledShadows(request *http.Request, actualPrefix string, selected *versionedRoute) []*versionedRoute {
+	if r.shadows == nil || request.Method != http.MethodGet || actualPrefix != r.config.APIPrefix {
+		return nil
+	}
+
+	versions, ok := r.versions[combine(selected.route.Method, selected.route.URI)]
+	if !ok || versions.stable != selected {
+		return nil
+	}
+
+	var result []*versionedRoute
+	for _, shadow := range versions.shadows {
+		if rand.Intn(100) < shadow.route.Shadow.SamplePercent {
+			result = append(result, shadow)
+		}
+	}
+
+	return result
+}
+
+//runShadow handles shadow route, the shadow is counted as in-flight request of the router generation, so that reload drains it
+func (r *Router) runShadow(request *http.Request, shadow *versionedRoute, primary *shadowResponse) {
+	defer r.inFlight.Done()
+	defer r.shadows.release()
+
+	recorder := httptest.NewRecorder()
+	started := time.Now()
+	err := r.routers[shadow.routerIndex].HandleRoute(recorder, request, shadow.route)
+	actual := &shadowResponse{statusCode: recorder.Code, header: recorder.Header(), body: recorder.Body.Bytes(), elapsed: time.Since(started)}
+
+	comparison := &ShadowComparison{
+		Method:           shadow.route.Method,
+		URI:              shadow.route.URI,
+		URL:              request.URL.String(),
+		Version:          shadow.route.Version,
+		PrimaryStatus:    primary.statusCode,
+		ShadowStatus:     actual.statusCode,
+		PrimaryElapsedMs: int(primary.elapsed.Milliseconds()),
+		ShadowElapsedMs:  int(actual.elapsed.Milliseconds()),
+		Time:             started,
+	}
+	comparison.DeltaMs = comparison.ShadowElapsedMs - comparison.PrimaryElapsedMs
+
+	if err == nil {
+		comparison.Diffs, err = compareResponses(primary, actual, shadow.route.Shadow.Ignore)
+	}
+
+	if err != nil {
+		comparison.Error = err.Error()
+	}
+
+	comparison.Matched = err == nil && len(comparison.Diffs) == 0
+	r.shadows.add(comparison)
+}
+
+func compareResponses(primary, shadow *shadowResponse, ignore []string) ([]string, error) {
+	var diffs []string
+	if primary.statusCode != shadow.statusCode {
+		diffs = append(diffs, fmt.Sprintf("status: %v != %v", primary.statusCode, shadow.statusCode))
+	}
+
+	primaryBody, err := decodeBody(primary)
+	if err != nil {
+		return nil, err
+	}
+
+	shadowBody, err := decodeBody(shadow)
+	if err != nil {
+		return nil, err
+	}
+
+	var primaryValue, shadowValue interface{}
+	if json.Unmarshal(primaryBody, &primaryValue) != nil || json.Unmarshal(shadowBody, &shadowValue) != nil {
+		if !bytes.Equal(primaryBody, shadowBody) {
+			diffs = append(diffs, "payload: not equal")
+		}
+		return diffs, nil
+	}
+
+	for _, path := range ignore {
+		segments := strings.Split(path, ".")
+		removePath(primaryValue, segments)
+		removePath(shadowValue, segments)
+	}
+
+	return appendDiffs(diffs, "", primaryValue, shadowValue), nil
+}
+
+func decodeBody(response *shadowResponse) ([]byte, error) {
+	if response.header.Get("Content-Encoding") != "gzip" {
+		return response.body, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(response.body))
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+func removePath(value interface{}, segments []string) {
+	switch actual := value.(type) {
+	case map[string]interface{}:
+		if len(segments) == 1 {
+			delete(actual, segments[0])
+			return
+		}
+
+		removePath(actual[segments[0]], segments[1:])
+	case []interface{}:
+		for _, item := range actual {
+			removePath(item, segments)
+		}
+	}
+}
+
+func appendDiffs(diffs []string, path string, primary, shadow interface{}) []string {
+	if len(diffs) >= maxShadowDiffs {
+		return diffs
+	}
+
+	primaryMap, isPrimaryMap := primary.(map[string]interface{})
+	shadowMap, isShadowMap := shadow.(map[string]interface{})
+	if isPrimaryMap && isShadowMap {
+		keys := make([]string, 0, len(primaryMap))
+		for key := range primaryMap {
+			keys = append(keys, key)
+		}
+
+		for key := range shadowMap {
+			if _, ok := primaryMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+
+		sort.Strings(keys)
+		for _, key := range keys {
+			diffs = appendDiffs(diffs, joinPath(path, key), primaryMap[key], shadowMap[key])
+		}
+
+		return diffs
+	}
+
+	primarySlice, isPrimarySlice := primary.([]interface{})
+	shadowSlice, isShadowSlice := shadow.([]interface{})
+	if isPrimarySlice && isShadowSlice {
+		if len(primarySlice) != len(shadowSlice) {
+			return append(diffs, fmt.Sprintf("%v: len %v != %v", path, len(primarySlice), len(shadowSlice)))
+		}
+
+		for i := range primarySlice {
+			diffs = appendDiffs(diffs, fmt.Sprintf("%v[%v]", path, i), primarySlice[i], shadowSlice[i])
+		}
+
+		return diffs
+	}
+
+	if !reflect.DeepEqual(primary, shadow) {
+		diffs = append(diffs, fmt.Sprintf("%v: %v != %v", path, primary, shadow))
+	}
+
+	return diffs
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func (l *shadowLog) acquire(shadow *versionedRoute) bool {
+	select {
+	case l.inFlight <- true:
+		return true
+	default:
+		l.mux.Lock()
+		l.statsFor(shadow.route.Method, shadow.route.URI, shadow.route.Version).Skipped++
+		l.mux.Unlock()
+		return false
+	}
+}
+
+func (l *shadowLog) release() {
+	<-l.inFlight
+}
+
+func (l *shadowLog) add(comparison *ShadowComparison) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	stats := l.statsFor(comparison.Method, comparison.URI, comparison.Version)
+	stats.Requests++
+	stats.totalDeltaMs += comparison.DeltaMs
+	stats.AvgDeltaMs = stats.totalDeltaMs / stats.Requests
+	if comparison.Error != "" {
+		stats.Errors++
+	} else if !comparison.Matched {
+		stats.Mismatches++
+	}
+
+	if comparison.Matched || l.size == 0 {
+		return
+	}
+
+	l.comparisons = append(l.comparisons, comparison)
+	if len(l.comparisons) > l.size {
+		l.comparisons = l.comparisons[len(l.comparisons)-l.size:]
+	}
+}
+
+func (l *shadowLog) statsFor(method, URI, version string) *ShadowStats {
+	key := combine(method, URI) + ":" + version
+	stats, ok := l.stats[key]
+	if !ok {
+		stats = &ShadowStats{Method: method, URI: URI, Version: version}
+		l.stats[key] = stats
+	}
+
+	return stats
+}
+
+func (l *shadowLog) report() *ShadowReport {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	result := &ShadowReport{Stats: make([]*ShadowStats, 0, len(l.stats)), Comparisons: make([]*ShadowComparison, 0, len(l.comparisons))}
+	for _, stats := range l.stats {
+		statsCopy := *stats
+		result.Stats = append(result.Stats, &statsCopy)
+	}
+
+	sort.Slice(result.Stats, func(i, j int) bool {
+		return result.Stats[i].URI+result.Stats[i].Version < result.Stats[j].URI+result.Stats[j].Version
+	})
+
+	for i := len(l.comparisons) - 1; i >= 0; i-- {
+		result.Comparisons = append(result.Comparisons, l.comparisons[i])
+	}
+
+	return result
+}
+
+func (l *shadowLog) reset() {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.comparisons = nil
+	l.stats = map[string]*ShadowStats{}
+}
+
+func newShadowLog(size int) *shadowLog {
+	if size == 0 {
+		size = DefaultShadowLogSize
+	}
+
+	return &shadowLog{size: size, stats: map[string]*ShadowStats{}, inFlight: make(chan bool, maxShadowsInFlight)}
+}
+
+func (r *Router) handleShadows(writer http.ResponseWriter, request *http.Request) {
+	statusCode, err := r.handleShadowsWithErr(writer, request)
+	r.handleErrIfNeeded(writer, statusCode, err)
+}
+
+func (r *Router) handleShadowsWithErr(writer http.ResponseWriter, request *http.Request) (int, error) {
+	if r.shadows == nil {
+		return http.StatusNotFound, nil
+	}
+
+	if request.Method == http.MethodDelete {
+		r.shadows.reset()
+		return http.StatusOK, nil
+	}
+
+	JSON, err := json.Marshal(r.shadows.report())
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(JSON)
+	return http.StatusOK, nil
+}
@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestCompareResponses(t *testing.T) {
+	useCases := []struct {
+		description string
+		primary     string
+		shadow      string
+		ignore      []string
+		expect      []string
+	}{
+		{
+			description: "equal payloads",
+			primary:     `[{"id":1,"name":"abc"}]`,
+			shadow:      `[{"name":"abc","id":1}]`,
+		},
+		{
+			description: "different values",
+			primary:     `{"Data":[{"id":1,"name":"abc"}]}`,
+			shadow:      `{"Data":[{"id":1,"name":"xyz"}]}`,
+			expect:      []string{"Data[0].name: abc != xyz"},
+		},
+		{
+			description: "ignored paths",
+			primary:     `{"Data":[{"id":1,"updated":"2022-01-01"}]}`,
+			shadow:      `{"Data":[{"id":1,"updated":"2022-01-02"}]}`,
+			ignore:      []string{"Data.updated"},
+		},
+		{
+			description: "different length",
+			primary:     `[{"id":1},{"id":2}]`,
+			shadow:      `[{"id":1}]`,
+			expect:      []string{": len 2 != 1"},
+		},
+		{
+			description: "non JSON payloads",
+			primary:     `id,name`,
+			shadow:      `id`,
+			expect:      []string{"payload: not equal"},
+		},
+	}
+
+	for _, useCase := range useCases {
+		primary := &shadowResponse{statusCode: http.StatusOK, header: http.Header{}, body: []byte(useCase.primary)}
+		shadow := &shadowResponse{statusCode: http.StatusOK, header: http.Header{}, body: []byte(useCase.shadow)}
+		actual, err := compareResponses(primary, shadow, useCase.ignore)
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	//RoutesStatus represents outcome of the latest routes load
+	RoutesStatus struct {
+		Generation int
+		Routes     int
+		LoadedAt   *time.Time `json:",omitempty"`
+		FailedAt   *time.Time `json:",omitempty"`
+		Error      string     `json:",omitempty"`
+	}
+
+	routesStatus struct {
+		mux    sync.RWMutex
+		status RoutesStatus
+	}
+)
+
+func (s *routesStatus) onLoaded(generation, routes int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := time.Now()
+	s.status.Generation = generation
+	s.status.Routes = routes
+	s.status.LoadedAt = &now
+	s.status.FailedAt = nil
+	s.status.Error = ""
+}
+
+func (s *routesStatus) onFailure(err error) {
+	if err == nil {
+		return
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := time.Now()
+	s.status.FailedAt = &now
+	s.status.Error = err.Error()
+}
+
+func (s *routesStatus) snapshot() RoutesStatus {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.status
+}
+
+func newRoutesStatus() *routesStatus {
+	return &routesStatus{}
+}
@@ -0,0 +1,331 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"github.com/viant/datly/gateway/registry"
+	"github.com/viant/datly/router"
+	"github.com/viant/gmetric"
+	"github.com/viant/gmetric/counter"
+	"github.com/viant/gmetric/provider"
+	"github.com/viant/scy/auth/jwt"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//VersionHeader response header with the version of the route that handled the request
+	VersionHeader = "Datly-Route-Version"
+
+	defaultVersionHeader = "Accept-Version"
+)
+
+type (
+	//Versioning represents route version selection config
+	Versioning struct {
+		Header     string //request header selecting route version, Accept-Version by default
+		PathPrefix string //path prefix selecting route version i.e. /version/ for /version/2/v1/api/...
+	}
+
+	routeVersions struct {
+		stable    *versionedRoute
+		byVersion map[string]*versionedRoute
+		canaries  []*versionedRoute
+		shadows   []*versionedRoute
+	}
+
+	versionedRoute struct {
+		route       *router.Route
+		routerIndex int
+		counter     *gmetric.Operation
+	}
+
+	versionKey struct{}
+)
+
+//Init initializes Versioning
+func (v *Versioning) Init() {
+	if v.Header == "" {
+		v.Header = defaultVersionHeader
+	}
+
+	if v.PathPrefix != "" {
+		v.PathPrefix = "/" + strings.Trim(v.PathPrefix, "/") + "/"
+	}
+}
+
+func newRouteVersions(routes []*router.Route, routerIndexes []int) map[string]*routeVersions {
+	result := map[string]*routeVersions{}
+	for i, route := range routes {
+		key := combine(route.Method, route.URI)
+		versions, ok := result[key]
+		if !ok {
+			versions = &routeVersions{byVersion: map[string]*versionedRoute{}}
+			result[key] = versions
+		}
+
+		versions.add(&versionedRoute{route: route, routerIndex: routerIndexes[i]})
+	}
+
+	for _, versions := range result {
+		versions.init()
+	}
+
+	return result
+}
+
+func (v *routeVersions) add(route *versionedRoute) {
+	if route.route.Shadow != nil {
+		v.shadows = append(v.shadows, route)
+		return
+	}
+
+	if route.route.Version != "" {
+		v.byVersion[route.route.Version] = route
+	}
+
+	if route.route.Weight > 0 {
+		v.canaries = append(v.canaries, route)
+		return
+	}
+
+	if v.stable == nil || (v.stable.route.Version != "" && route.route.Version == "") {
+		v.stable = route
+	}
+}
+
+func (v *routeVersions) init() {
+	sort.Slice(v.canaries, func(i, j int) bool {
+		return v.canaries[i].route.Version < v.canaries[j].route.Version
+	})
+
+	if v.stable == nil && len(v.canaries) > 0 {
+		v.stable = v.canaries[0]
+		v.canaries = v.canaries[1:]
+	}
+}
+
+func (v *routeVersions) routes() []*versionedRoute {
+	result := []*versionedRoute{v.stable}
+	for _, route := range v.byVersion {
+		if route != v.stable {
+			result = append(result, route)
+		}
+	}
+
+	for _, route := range v.canaries {
+		if route.route.Version == "" {
+			result = append(result, route)
+		}
+	}
+
+	return result
+}
+
+//validate checks that canary weights of the route versions do not exceed 100
+func (v *routeVersions) validate() error {
+	if v.stable == nil {
+		shadow := v.shadows[0].route
+		return fmt.Errorf("route %v %v has only shadow versions", shadow.Method, shadow.URI)
+	}
+
+	weights := 0
+	for _, canary := range v.canaries {
+		weights += canary.route.Weight
+	}
+
+	weights += v.stable.route.Weight
+
+	if weights > 100 {
+		return fmt.Errorf("route %v %v canary weights sum up to %v, expected at most 100", v.stable.route.Method, v.stable.route.URI, weights)
+	}
+
+	return nil
+}
+
+//weighted selects canary route based on stable bucket of the request principal
+func (v *routeVersions) weighted(bucket int) *versionedRoute {
+	threshold := 0
+	for _, canary := range v.canaries {
+		threshold += canary.route.Weight
+		if bucket < threshold {
+			return canary
+		}
+	}
+
+	return v.stable
+}
+
+func (r *Router) initVersionCounters() {
+	if r.metrics == nil {
+		return
+	}
+
+	for _, versions := range r.versions {
+		if versions.stable == nil || (len(versions.byVersion) == 0 && len(versions.canaries) == 0) {
+			continue
+		}
+
+		for _, route := range versions.routes() {
+			route.counter = r.versionCounter(route.route)
+		}
+	}
+}
+
+func (r *Router) versionCounter(route *router.Route) *gmetric.Operation {
+	version := route.Version
+	if version == "" {
+		version = "default"
+	}
+
+	name := strings.ReplaceAll(route.Method+route.URI+"/"+version, "/", ".")
+	if operation := r.metrics.LookupOperation(name); operation != nil {
+		return operation
+	}
+
+	return r.metrics.MultiOperationCounter(versionMetricLocation(), name, name+" version performance", time.Millisecond, time.Minute, 2, provider.NewBasic())
+}
+
+func (r *Router) matchVersion(request *http.Request, URL string) (*versionedRoute, error) {
+	route, err := r.routeMatcher.MatchOneRoute(request.Method, URL)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, ok := r.versions[combine(route.Method, route.URI)]
+	if !ok {
+		return nil, fmt.Errorf("not found route %v %v", route.Method, route.URI)
+	}
+
+	if version := r.requestedVersion(request); version != "" {
+		selected, ok := versions.byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("unsupported route %v %v version %v", route.Method, route.URI, version)
+		}
+
+		return selected, nil
+	}
+
+	if len(versions.canaries) == 0 {
+		return versions.stable, nil
+	}
+
+	return versions.weighted(requestBucket(request, route.URI)), nil
+}
+
+func (r *Router) requestedVersion(request *http.Request) string {
+	if r.config == nil || r.config.Versioning == nil {
+		return ""
+	}
+
+	if version := request.Header.Get(r.config.Versioning.Header); version != "" {
+		return version
+	}
+
+	version, _ := request.Context().Value(versionKey{}).(string)
+	return version
+}
+
+//stripVersionPath removes version path prefix from the request URL and stores selected version in the request context
+func (r *Router) stripVersionPath(request *http.Request) *http.Request {
+	if r.config == nil || r.config.Versioning == nil || r.config.Versioning.PathPrefix == "" {
+		return request
+	}
+
+	prefix := r.config.Versioning.PathPrefix
+	URLPath := request.URL.Path
+	if !strings.HasPrefix(URLPath, prefix) {
+		return request
+	}
+
+	version := URLPath[len(prefix):]
+	remaining := ""
+	if index := strings.Index(version, "/"); index != -1 {
+		version, remaining = version[:index], version[index:]
+	}
+
+	if version == "" {
+		return request
+	}
+
+	request = request.WithContext(context.WithValue(request.Context(), versionKey{}, version))
+	request.URL.Path = remaining
+	if request.URL.RawPath != "" {
+		request.URL.RawPath = ""
+	}
+
+	return request
+}
+
+func (v *versionedRoute) begin(writer http.ResponseWriter) counter.OnDone {
+	if v.route.Version != "" {
+		writer.Header().Set(VersionHeader, v.route.Version)
+	}
+
+	if v.counter == nil {
+		return nil
+	}
+
+	return v.counter.Begin(time.Now())
+}
+
+//requestBucket returns request principal bucket in range [0, 100), the same principal is always routed to the same version
+func requestBucket(request *http.Request, URI string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(requestPrincipal(request)))
+	hash.Write([]byte(URI))
+	return int(hash.Sum32() % 100)
+}
+
+func requestPrincipal(request *http.Request) string {
+	authorization := request.Header.Get("Authorization")
+	if authorization == "" {
+		return clientIP(request)
+	}
+
+	if jwtCodec, _ := registry.Codecs.Lookup(registry.CodecKeyJwtClaim); jwtCodec != nil {
+		if claim, _ := jwtCodec.Valuer().Value(request.Context(), authorization); claim != nil {
+			if jwtClaim, ok := claim.(*jwt.Claims); ok && jwtClaim != nil {
+				if jwtClaim.UserID != 0 {
+					return strconv.Itoa(jwtClaim.UserID)
+				}
+
+				if jwtClaim.Email != "" {
+					return jwtClaim.Email
+				}
+			}
+		}
+	}
+
+	return authorization
+}
+
+//clientIP returns anonymous client IP without port, so that the same client is routed to the same version across connections
+func clientIP(request *http.Request) string {
+	if forwarded := request.Header.Get("X-Forwarded-For"); forwarded != "" {
+		if index := strings.Index(forwarded, ","); index != -1 {
+			forwarded = forwarded[:index]
+		}
+
+		return strings.TrimSpace(forwarded)
+	}
+
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+
+	return host
+}
+
+type versionMetricsLocation struct{}
+
+func versionMetricLocation() string {
+	return reflect.TypeOf(versionMetricsLocation{}).PkgPath()
+}
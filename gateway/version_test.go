@@ -0,0 +1,170 @@
+package gateway
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/router"
+	"net/http"
+	"testing"
+)
+
+func TestRouteVersions_Weighted(t *testing.T) {
+	useCases := []struct {
+		description string
+		routes      []*router.Route
+		bucket      int
+		expect      string
+	}{
+		{
+			description: "stable route only",
+			routes:      []*router.Route{{Method: "GET", URI: "/events", Version: "1"}},
+			bucket:      5,
+			expect:      "1",
+		},
+		{
+			description: "bucket within canary weight",
+			routes: []*router.Route{
+				{Method: "GET", URI: "/events", Version: "1"},
+				{Method: "GET", URI: "/events", Version: "2", Weight: 10},
+			},
+			bucket: 9,
+			expect: "2",
+		},
+		{
+			description: "bucket outside canary weight",
+			routes: []*router.Route{
+				{Method: "GET", URI: "/events", Version: "1"},
+				{Method: "GET", URI: "/events", Version: "2", Weight: 10},
+			},
+			bucket: 10,
+			expect: "1",
+		},
+		{
+			description: "multiple canaries",
+			routes: []*router.Route{
+				{Method: "GET", URI: "/events", Version: "1"},
+				{Method: "GET", URI: "/events", Version: "3", Weight: 20},
+				{Method: "GET", URI: "/events", Version: "2", Weight: 10},
+			},
+			bucket: 15,
+			expect: "3",
+		},
+		{
+			description: "unversioned route is stable",
+			routes: []*router.Route{
+				{Method: "GET", URI: "/events", Version: "2"},
+				{Method: "GET", URI: "/events"},
+			},
+			bucket: 0,
+			expect: "",
+		},
+	}
+
+	for _, useCase := range useCases {
+		versions := newRouteVersions(useCase.routes, make([]int, len(useCase.routes)))
+		actual := versions[combine("GET", "/events")].weighted(useCase.bucket)
+		assert.Equal(t, useCase.expect, actual.route.Version, useCase.description)
+	}
+}
+
+func TestRouteVersions_Validate(t *testing.T) {
+	useCases := []struct {
+		description string
+		routes      []*router.Route
+		expectErr   bool
+	}{
+		{
+			description: "canary weights within 100",
+			routes: []*router.Route{
+				{Method: "GET", URI: "/events", Version: "1"},
+				{Method: "GET", URI: "/events", Version: "2", Weight: 60},
+				{Method: "GET", URI: "/events", Version: "3", Weight: 40},
+			},
+		},
+		{
+			description: "canary weights exceed 100",
+			routes: []*router.Route{
+				{Method: "GET", URI: "/events", Version: "1"},
+				{Method: "GET", URI: "/events", Version: "2", Weight: 60},
+				{Method: "GET", URI: "/events", Version: "3", Weight: 50},
+			},
+			expectErr: true,
+		},
+		{
+			description: "weighted routes without stable one exceed 100",
+			routes: []*router.Route{
+				{Method: "GET", URI: "/events", Version: "2", Weight: 70},
+				{Method: "GET", URI: "/events", Version: "3", Weight: 70},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		versions := newRouteVersions(useCase.routes, make([]int, len(useCase.routes)))
+		err := versions[combine("GET", "/events")].validate()
+		assert.Equal(t, useCase.expectErr, err != nil, useCase.description)
+	}
+}
+
+func TestRequestBucket(t *testing.T) {
+	useCases := []struct {
+		description string
+		first       *http.Request
+		second      *http.Request
+	}{
+		{
+			description: "anonymous client connections from different ports",
+			first:       &http.Request{RemoteAddr: "10.0.0.1:50001", Header: http.Header{}},
+			second:      &http.Request{RemoteAddr: "10.0.0.1:50002", Header: http.Header{}},
+		},
+		{
+			description: "forwarded client behind different proxies",
+			first:       &http.Request{RemoteAddr: "10.0.0.2:50001", Header: http.Header{"X-Forwarded-For": []string{"192.168.1.1, 10.0.0.2"}}},
+			second:      &http.Request{RemoteAddr: "10.0.0.3:50002", Header: http.Header{"X-Forwarded-For": []string{"192.168.1.1"}}},
+		},
+	}
+
+	for _, useCase := range useCases {
+		assert.Equal(t, requestBucket(useCase.first, "/events"), requestBucket(useCase.second, "/events"), useCase.description)
+	}
+}
+
+func TestRouteVersions_Shadows(t *testing.T) {
+	useCases := []struct {
+		description   string
+		routes        []*router.Route
+		expectVersion []string
+		expectShadows int
+		expectErr     bool
+	}{
+		{
+			description: "shadow is not selectable by version",
+			routes: []*router.Route{
+				{Method: "GET", URI: "/events", Version: "1"},
+				{Method: "GET", URI: "/events", Version: "2", Shadow: &router.Shadow{SamplePercent: 10}},
+			},
+			expectVersion: []string{"1"},
+			expectShadows: 1,
+		},
+		{
+			description: "shadow only route",
+			routes: []*router.Route{
+				{Method: "GET", URI: "/events", Version: "2", Shadow: &router.Shadow{SamplePercent: 10}},
+			},
+			expectShadows: 1,
+			expectErr:     true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		versions := newRouteVersions(useCase.routes, make([]int, len(useCase.routes)))[combine("GET", "/events")]
+		var actualVersions []string
+		for version := range versions.byVersion {
+			actualVersions = append(actualVersions, version)
+		}
+
+		assert.Equal(t, useCase.expectVersion, actualVersions, useCase.description)
+		assert.Equal(t, useCase.expectShadows, len(versions.shadows), useCase.description)
+		assert.Equal(t, useCase.expectErr, versions.validate() != nil, useCase.description)
+	}
+}
@@ -0,0 +1,156 @@
+package warmup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	as "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/types"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/option"
+	"github.com/viant/afs/url"
+	"github.com/viant/datly/view"
+	"github.com/viant/datly/view/cache/mem"
+	"github.com/viant/datly/view/cache/redis"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	aerospikeScheme = "aerospike"
+	lockSet         = "datly_warmup_locks"
+	lockExt         = ".lock"
+)
+
+type (
+	//Locker ensures single runner of the scheduled warmup slot, slot lock is never released, it expires with ttl,
+	//thus runners started late can't repeat the slot
+	Locker interface {
+		TryLock(ctx context.Context, key string, slot time.Time, ttl time.Duration) (bool, error)
+	}
+
+	localLocker struct {
+		mux   sync.Mutex
+		slots map[string]time.Time
+	}
+
+	redisLocker struct {
+		client *goredis.Client
+	}
+
+	aerospikeLocker struct {
+		client    *as.Client
+		namespace string
+	}
+
+	//afsLocker stores the last locked slot of each key in the cache location, lock is atomic with storages supporting generation preconditions
+	afsLocker struct {
+		fs       afs.Service
+		location string
+	}
+)
+
+func newLocalLocker() *localLocker {
+	return &localLocker{slots: map[string]time.Time{}}
+}
+
+func (l *localLocker) TryLock(ctx context.Context, key string, slot time.Time, ttl time.Duration) (bool, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if locked, ok := l.slots[key]; ok && !slot.After(locked) {
+		return false, nil
+	}
+
+	l.slots[key] = slot
+	return true, nil
+}
+
+func newRedisLocker(client *goredis.Client) *redisLocker {
+	return &redisLocker{client: client}
+}
+
+func (l *redisLocker) TryLock(ctx context.Context, key string, slot time.Time, ttl time.Duration) (bool, error) {
+	return l.client.SetNX(ctx, slotKey(key, slot), slot.Unix(), ttl).Result()
+}
+
+func (l *aerospikeLocker) TryLock(ctx context.Context, key string, slot time.Time, ttl time.Duration) (bool, error) {
+	lockKey, err := as.NewKey(l.namespace, lockSet, slotKey(key, slot))
+	if err != nil {
+		return false, err
+	}
+
+	policy := as.NewWritePolicy(0, uint32(math.Ceil(ttl.Seconds())))
+	policy.RecordExistsAction = as.CREATE_ONLY
+	err = l.client.Put(policy, lockKey, as.BinMap{"slot": slot.Unix()})
+	var aerospikeErr types.AerospikeError
+	if errors.As(err, &aerospikeErr) && aerospikeErr.ResultCode() == types.KEY_EXISTS_ERROR {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (l *afsLocker) TryLock(ctx context.Context, key string, slot time.Time, ttl time.Duration) (bool, error) {
+	URL := url.Join(l.location, strings.ReplaceAll(key, ":", "_")+lockExt)
+	exists, err := l.fs.Exists(ctx, URL)
+	if err != nil {
+		return false, err
+	}
+
+	generation := &option.Generation{}
+	if exists {
+		data, err := l.fs.DownloadWithURL(ctx, URL, generation)
+		if err != nil {
+			return false, err
+		}
+
+		if locked, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil && slot.Unix() <= locked {
+			return false, nil
+		}
+	}
+
+	err = l.fs.Upload(ctx, URL, file.DefaultFileOsMode, bytes.NewReader([]byte(strconv.FormatInt(slot.Unix(), 10))), option.NewGeneration(true, generation.Generation))
+	if err != nil && strings.Contains(strings.ToLower(err.Error()), "precondition") {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func slotKey(key string, slot time.Time) string {
+	return key + ":" + strconv.FormatInt(slot.Unix(), 10)
+}
+
+//lockerFor returns locker shared by gateway instances using the view cache provider, views cached in process are locked in process
+func lockerFor(aView *view.View, local Locker) (Locker, error) {
+	switch url.Scheme(aView.Cache.Provider, "") {
+	case mem.Scheme:
+		return local, nil
+	case redis.Scheme:
+		client, err := redis.Client(aView.Cache.Provider)
+		if err != nil {
+			return nil, err
+		}
+
+		return newRedisLocker(client), nil
+	case aerospikeScheme:
+		client, namespace, err := aView.Cache.AerospikeClient()
+		if err != nil {
+			return nil, err
+		}
+
+		return &aerospikeLocker{client: client, namespace: namespace}, nil
+	default:
+		location, err := aView.Cache.ExpandedLocation()
+		if err != nil {
+			return nil, err
+		}
+
+		return &afsLocker{fs: afs.New(), location: location}, nil
+	}
+}
@@ -0,0 +1,76 @@
+package warmup
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+	"testing"
+	"time"
+)
+
+func TestLocker_TryLock(t *testing.T) {
+	server, err := miniredis.Run()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer server.Close()
+
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	fs := afs.New()
+	_ = fs.Delete(context.Background(), "mem://localhost/datly/cache")
+	useCases := []struct {
+		description string
+		newLocker   func() Locker
+		shared      bool
+	}{
+		{
+			description: "local locker",
+			newLocker: func() Locker {
+				return newLocalLocker()
+			},
+		},
+		{
+			description: "redis locker is shared by gateway instances",
+			newLocker: func() Locker {
+				return newRedisLocker(client)
+			},
+			shared: true,
+		},
+		{
+			description: "afs locker is shared by gateway instances",
+			newLocker: func() Locker {
+				return &afsLocker{fs: fs, location: "mem://localhost/datly/cache"}
+			},
+			shared: true,
+		},
+	}
+
+	slot := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, useCase := range useCases {
+		ctx := context.Background()
+		locker := useCase.newLocker()
+		locked, err := locker.TryLock(ctx, "datly:warmup:events", slot, time.Minute)
+		assert.Nil(t, err, useCase.description)
+		assert.True(t, locked, useCase.description)
+
+		locked, err = useCase.newLocker().TryLock(ctx, "datly:warmup:events", slot, time.Minute)
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, !useCase.shared, locked, useCase.description)
+
+		locked, err = locker.TryLock(ctx, "datly:warmup:events", slot, time.Minute)
+		assert.Nil(t, err, useCase.description)
+		assert.False(t, locked, useCase.description)
+
+		locked, err = locker.TryLock(ctx, "datly:warmup:events", slot.Add(time.Minute), time.Minute)
+		assert.Nil(t, err, useCase.description)
+		assert.True(t, locked, useCase.description)
+
+		locked, err = useCase.newLocker().TryLock(ctx, "datly:warmup:users", slot, time.Minute)
+		assert.Nil(t, err, useCase.description)
+		assert.True(t, locked, useCase.description)
+	}
+}
@@ -0,0 +1,211 @@
+package warmup
+
+import (
+	"context"
+	"fmt"
+	"github.com/viant/datly/view"
+	"github.com/viant/datly/warmup"
+	"sync"
+	"time"
+)
+
+const (
+	//DefaultHistorySize represents default number of recorded scheduled warmup runs
+	DefaultHistorySize = 100
+
+	RunStatusOK     = "ok"
+	RunStatusError  = "error"
+	RunStatusLocked = "locked" //run skipped as other runner holds the lock
+
+	minLockTTL = time.Minute
+)
+
+type (
+	//Scheduler periodically refreshes cache of the views with Warmup.Schedule
+	Scheduler struct {
+		mux        sync.Mutex
+		jobs       map[string]*job
+		history    []*Run
+		size       int
+		watermarks *warmup.Watermarks
+		locker     Locker
+	}
+
+	//Run represents scheduled warmup run
+	Run struct {
+		View      string
+		Status    string
+		Error     string `json:",omitempty"`
+		Started   time.Time
+		Elapsed   string
+		TimeTaken time.Duration
+		Indexed   int
+		Rebuilt   int
+		Skipped   int
+	}
+
+	//Job represents scheduled view warmup
+	Job struct {
+		View     string
+		Schedule string
+		NextRun  time.Time
+		Running  bool
+	}
+
+	//Status represents scheduled warmups and recent runs
+	Status struct {
+		Jobs    []*Job
+		History []*Run
+	}
+
+	job struct {
+		view    *view.View
+		nextRun time.Time
+		running bool
+		done    chan bool
+	}
+)
+
+//NewScheduler creates warmup scheduler, runs are locked with the view cache provider, views cached in process are locked in process
+func NewScheduler(historySize int) *Scheduler {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+
+	return &Scheduler{jobs: map[string]*job{}, size: historySize, watermarks: warmup.NewWatermarks(), locker: newLocalLocker()}
+}
+
+//Schedule schedules views with Warmup.Schedule, jobs of views no longer present are stopped
+func (s *Scheduler) Schedule(views []*view.View) {
+	scheduled := map[string]*view.View{}
+	for _, aView := range warmup.FilterCacheViews(views) {
+		if aView.Cache.Warmup.Scheduled() {
+			scheduled[aView.Name] = aView
+		}
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for name, aJob := range s.jobs {
+		if scheduled[name] != aJob.view {
+			close(aJob.done)
+			delete(s.jobs, name)
+		}
+	}
+
+	for name, aView := range scheduled {
+		if _, ok := s.jobs[name]; ok {
+			continue
+		}
+
+		aJob := &job{view: aView, done: make(chan bool)}
+		s.jobs[name] = aJob
+		go s.loop(aJob)
+	}
+}
+
+//Close stops scheduled jobs
+func (s *Scheduler) Close() {
+	s.Schedule(nil)
+}
+
+//Status returns scheduled jobs and recent runs, most recent first
+func (s *Scheduler) Status() *Status {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	result := &Status{Jobs: []*Job{}, History: make([]*Run, 0, len(s.history))}
+	for _, aJob := range s.jobs {
+		result.Jobs = append(result.Jobs, &Job{View: aJob.view.Name, Schedule: aJob.view.Cache.Warmup.Schedule, NextRun: aJob.nextRun, Running: aJob.running})
+	}
+
+	for i := len(s.history) - 1; i >= 0; i-- {
+		result.History = append(result.History, s.history[i])
+	}
+
+	return result
+}
+
+func (s *Scheduler) loop(aJob *job) {
+	for {
+		slot := aJob.view.Cache.Warmup.ScheduledRun(time.Now())
+		nextRun := slot.Add(aJob.view.Cache.Warmup.Jitter())
+		s.mux.Lock()
+		aJob.nextRun = nextRun
+		s.mux.Unlock()
+
+		timer := time.NewTimer(time.Until(nextRun))
+		select {
+		case <-aJob.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.run(aJob, slot)
+		}
+	}
+}
+
+func (s *Scheduler) run(aJob *job, slot time.Time) {
+	s.mux.Lock()
+	aJob.running = true
+	s.mux.Unlock()
+
+	aRun := s.refresh(aJob.view, slot)
+	fmt.Printf("[INFO] scheduled warmup of view %v: %v, elapsed: %v\n", aRun.View, aRun.Status, aRun.Elapsed)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	aJob.running = false
+	s.history = append(s.history, aRun)
+	if len(s.history) > s.size {
+		s.history = s.history[len(s.history)-s.size:]
+	}
+}
+
+//refresh refreshes view cache once per scheduled slot, slot lock is kept until the next slot so that late runners skip the slot
+func (s *Scheduler) refresh(aView *view.View, slot time.Time) *Run {
+	ctx := context.Background()
+	aRun := &Run{View: aView.Name, Started: time.Now(), Status: RunStatusOK}
+	defer func() {
+		aRun.TimeTaken = time.Since(aRun.Started)
+		aRun.Elapsed = aRun.TimeTaken.String()
+	}()
+
+	locker, err := lockerFor(aView, s.locker)
+	if err != nil {
+		aRun.Status, aRun.Error = RunStatusError, err.Error()
+		return aRun
+	}
+
+	lockKey := "datly:warmup:" + aView.Name
+	lockTTL := aView.Cache.Warmup.ScheduledRun(slot).Sub(slot)
+	if lockTTL < minLockTTL {
+		lockTTL = minLockTTL
+	}
+
+	locked, err := locker.TryLock(ctx, lockKey, slot, lockTTL)
+	if err != nil || !locked {
+		aRun.Status = RunStatusLocked
+		if err != nil {
+			aRun.Status, aRun.Error = RunStatusError, err.Error()
+		}
+		return aRun
+	}
+
+	connectors := []*view.Connector{aView.Connector}
+	if aView.Cache.Warmup.Connector != nil {
+		connectors = append(connectors, aView.Cache.Warmup.Connector)
+	}
+
+	view.RetainDBs(aRun, connectors)
+	defer view.ReleaseDBs(aRun)
+	refresh, err := warmup.RefreshCache([]*view.View{aView}, s.watermarks)
+	if refresh != nil {
+		aRun.Indexed, aRun.Rebuilt, aRun.Skipped = refresh.Indexed, refresh.Rebuilt, refresh.Skipped
+	}
+
+	if err != nil {
+		aRun.Status, aRun.Error = RunStatusError, err.Error()
+	}
+
+	return aRun
+}
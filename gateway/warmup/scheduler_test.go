@@ -0,0 +1,162 @@
+package warmup
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/view"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Status(t *testing.T) {
+	aView, err := newScheduledView(t, "/tmp/datly_scheduler_status_test.db", "@every 1s")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	scheduler := NewScheduler(2)
+	scheduler.Schedule([]*view.View{aView})
+	defer scheduler.Close()
+
+	status := scheduler.Status()
+	if assert.Len(t, status.Jobs, 1) {
+		assert.Equal(t, "events", status.Jobs[0].View)
+		assert.Equal(t, "@every 1s", status.Jobs[0].Schedule)
+	}
+
+	if !assert.Eventually(t, func() bool { return len(scheduler.Status().History) == 2 }, 5*time.Second, 10*time.Millisecond, "runs are recorded") {
+		return
+	}
+
+	first := scheduler.Status().History[1]
+	assert.Equal(t, "events", first.View)
+	assert.Equal(t, RunStatusOK, first.Status)
+	assert.Equal(t, 3, first.Indexed)
+	assert.Equal(t, 1, first.Rebuilt)
+
+	assert.Eventually(t, func() bool {
+		history := scheduler.Status().History
+		return len(history) == 2 && history[1] != first
+	}, 5*time.Second, 10*time.Millisecond, "history is trimmed to the history size")
+
+	history := scheduler.Status().History
+	if assert.Len(t, history, 2) {
+		assert.True(t, history[0].Started.After(history[1].Started), "most recent run first")
+		assert.Equal(t, RunStatusOK, history[0].Status)
+		assert.Equal(t, 1, history[0].Skipped, "unchanged watermark is skipped")
+		assert.Equal(t, 0, history[0].Rebuilt)
+	}
+
+	jobs := scheduler.Status().Jobs
+	if assert.Len(t, jobs, 1) {
+		assert.True(t, jobs[0].NextRun.After(history[0].Started), "next run is scheduled")
+	}
+
+	scheduler.Close()
+	assert.Empty(t, scheduler.Status().Jobs)
+}
+
+func TestScheduler_Refresh(t *testing.T) {
+	aView, err := newScheduledView(t, "/tmp/datly_scheduler_refresh_test.db", "@every 1h")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	broken := *aView
+	broken.Name = "broken"
+	broken.Connector = &view.Connector{Name: "broken", Driver: "sqlite3", DSN: "/tmp/datly_scheduler_refresh_test/missing/events.db"}
+
+	slot := time.Now().Truncate(time.Hour)
+	scheduler := NewScheduler(0)
+	useCases := []struct {
+		description string
+		view        *view.View
+		slot        time.Time
+		expect      string
+		expectError bool
+	}{
+		{
+			description: "slot refreshed",
+			view:        aView,
+			slot:        slot,
+			expect:      RunStatusOK,
+		},
+		{
+			description: "slot already refreshed",
+			view:        aView,
+			slot:        slot,
+			expect:      RunStatusLocked,
+		},
+		{
+			description: "next slot refreshed",
+			view:        aView,
+			slot:        slot.Add(time.Hour),
+			expect:      RunStatusOK,
+		},
+		{
+			description: "refresh error",
+			view:        &broken,
+			slot:        slot,
+			expect:      RunStatusError,
+			expectError: true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		aRun := scheduler.refresh(useCase.view, useCase.slot)
+		assert.Equal(t, useCase.view.Name, aRun.View, useCase.description)
+		assert.Equal(t, useCase.expect, aRun.Status, useCase.description)
+		assert.Equal(t, useCase.expectError, aRun.Error != "", useCase.description)
+		assert.Equal(t, aRun.TimeTaken.String(), aRun.Elapsed, useCase.description)
+	}
+}
+
+func newScheduledView(t *testing.T, dbLocation string, schedule string) (*view.View, error) {
+	db, err := sql.Open("sqlite3", dbLocation)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS EVENTS",
+		"CREATE TABLE EVENTS (ID INTEGER PRIMARY KEY, EVENT_TYPE_ID INTEGER, UPDATED INTEGER)",
+		"INSERT INTO EVENTS VALUES (1, 10, 1), (2, 11, 1), (3, 10, 1)",
+	} {
+		if _, err = db.Exec(SQL); err != nil {
+			return nil, err
+		}
+	}
+
+	connector := &view.Connector{Name: "db", Driver: "sqlite3", DSN: dbLocation}
+	resource := view.EmptyResource()
+	resource.Connectors = []*view.Connector{connector}
+	resource.AddViews(&view.View{
+		Name:      "events",
+		Connector: connector,
+		Table:     "EVENTS",
+		Columns: []*view.Column{
+			{Name: "ID", DataType: "int"},
+			{Name: "EVENT_TYPE_ID", DataType: "int"},
+			{Name: "UPDATED", DataType: "int"},
+		},
+		Cache: &view.Cache{
+			Name:         "events",
+			Provider:     "mem://localhost/" + t.Name(),
+			TimeToLiveMs: int(time.Hour.Milliseconds()),
+			Warmup: &view.Warmup{
+				IndexColumn: "EVENT_TYPE_ID",
+				Watermark:   "UPDATED",
+				Schedule:    schedule,
+			},
+		},
+	})
+
+	if err = resource.Init(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return resource.View("events")
+}
@@ -43,10 +43,19 @@ require (
 )
 
 require (
+	github.com/alicebob/miniredis/v2 v2.23.0
 	github.com/francoispqt/gojay v1.2.13
+	github.com/go-redis/redis/v8 v8.11.5
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/viant/dyndb v0.1.4-0.20221214043424-27654ab6ed9c
 )
 
+require (
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
+	github.com/cespare/xxhash/v2 v2.1.2 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+)
+
 require (
 	cloud.google.com/go v0.104.0 // indirect
 	cloud.google.com/go/compute v1.12.1 // indirect
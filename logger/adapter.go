@@ -21,6 +21,7 @@ type (
 		objectReconciling ObjectReconciling
 		columnsDetection  ColumnsDetection
 		log               Log
+		slowQuery         SlowQueryLogger
 	}
 )
 
@@ -78,12 +79,21 @@ func (l *Adapter) Log(message string, args ...interface{}) {
 	l.log(message, args...)
 }
 
+func (l *Adapter) SlowQuery(query *SlowQuery) {
+	if l.slowQuery == nil {
+		return
+	}
+
+	l.slowQuery(query)
+}
+
 func (l *Adapter) Inherit(adapter *Adapter) {
 	l.readTime = adapter.readTime
 	l.readingData = adapter.readingData
 	l.objectReconciling = adapter.objectReconciling
 	l.columnsDetection = adapter.columnsDetection
 	l.log = adapter.log
+	l.slowQuery = adapter.slowQuery
 }
 
 func (l *Adapter) LogDatabaseErr(SQL string, err error) {
@@ -97,7 +107,7 @@ func NewLogger(name string, logger Logger) *Adapter {
 		}
 	}
 
-	return &Adapter{
+	adapter := &Adapter{
 		Name:              name,
 		Reference:         shared.Reference{},
 		readTime:          logger.ViewReadTime(),
@@ -106,6 +116,12 @@ func NewLogger(name string, logger Logger) *Adapter {
 		columnsDetection:  logger.ColumnsDetection(),
 		log:               logger.Log(),
 	}
+
+	if slowQueryAware, ok := logger.(SlowQueryAware); ok {
+		adapter.slowQuery = slowQueryAware.SlowQuery()
+	}
+
+	return adapter
 }
 
 func Default() *Adapter {
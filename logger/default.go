@@ -42,6 +42,14 @@ func (d *defaultLogger) ReadingData() ReadingData {
 	}
 }
 
+func (d *defaultLogger) SlowQuery() SlowQueryLogger {
+	return d.logSlowQuery
+}
+
+func (d *defaultLogger) logSlowQuery(query *SlowQuery) {
+	fmt.Printf("[LOGGER] slow query in view %v took %v, SQL: %v, args: %v, rows: %v, err: %v\n", query.View, query.Elapsed, query.SQL, query.Args, query.Rows, query.Error)
+}
+
 func (d *defaultLogger) logOverallReadTime(viewName string, start *time.Time, end *time.Time, err error) {
 	fmt.Printf("[LOGGER] Overall reading view from main View %v took: %v, err: %v\n", viewName, end.Sub(*start), err)
 }
@@ -9,6 +9,7 @@ type Log func(message string, args ...interface{})
 type ObjectReconciling func(dst, item, parent interface{}, index int)
 type ReadingData func(duration time.Duration, sql string, read int, params []interface{}, err error)
 type ReadTime func(viewName string, start *time.Time, end *time.Time, err error)
+type SlowQueryLogger func(query *SlowQuery)
 
 type Logger interface {
 	ColumnsDetection() ColumnsDetection
@@ -18,3 +19,8 @@ type Logger interface {
 	OverallReadTime() ReadTime
 	Log() Log
 }
+
+//SlowQueryAware represents optional Logger extension notified about slow queries
+type SlowQueryAware interface {
+	SlowQuery() SlowQueryLogger
+}
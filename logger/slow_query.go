@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+//DefaultSlowQueryLogSize represents default number of slow queries kept in memory
+const DefaultSlowQueryLogSize = 100
+
+type (
+	//SlowQuery represents captured slow query
+	SlowQuery struct {
+		View      string
+		Mode      string                   `json:",omitempty"`
+		SQL       string                   `json:",omitempty"`
+		Args      []interface{}            `json:",omitempty"`
+		Rows      int                      `json:",omitempty"`
+		Elapsed   string                   `json:",omitempty"`
+		ElapsedMs int                      `json:",omitempty"`
+		Plan      []map[string]interface{} `json:",omitempty"`
+		PlanError string                   `json:",omitempty"`
+		Error     string                   `json:",omitempty"`
+		Time      time.Time
+	}
+
+	//SlowQueryLog represents bounded in memory slow queries ring buffer
+	SlowQueryLog struct {
+		mux     sync.RWMutex
+		entries []*SlowQuery
+		next    int
+		full    bool
+	}
+)
+
+//Add adds slow query to the log, overriding the oldest one if log is full
+func (l *SlowQueryLog) Add(query *SlowQuery) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if len(l.entries) == 0 {
+		return
+	}
+
+	l.entries[l.next] = query
+	l.next++
+	if l.next == len(l.entries) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+//Entries returns slow queries starting from the most recent one
+func (l *SlowQueryLog) Entries() []*SlowQuery {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	size := l.next
+	if l.full {
+		size = len(l.entries)
+	}
+
+	result := make([]*SlowQuery, 0, size)
+	for i := 1; i <= size; i++ {
+		index := l.next - i
+		if index < 0 {
+			index += len(l.entries)
+		}
+
+		result = append(result, l.entries[index])
+	}
+
+	return result
+}
+
+//Resize changes log capacity, only the most recent entries are preserved
+func (l *SlowQueryLog) Resize(size int) {
+	if size < 0 {
+		size = 0
+	}
+
+	entries := l.Entries()
+	if len(entries) > size {
+		entries = entries[:size]
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.entries = make([]*SlowQuery, size)
+	l.next = 0
+	l.full = false
+	for i := len(entries) - 1; i >= 0; i-- {
+		l.entries[l.next] = entries[i]
+		l.next++
+	}
+
+	if size > 0 && l.next == size {
+		l.next = 0
+		l.full = true
+	}
+}
+
+//Reset removes all entries
+func (l *SlowQueryLog) Reset() {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.entries = make([]*SlowQuery, len(l.entries))
+	l.next = 0
+	l.full = false
+}
+
+//NewSlowQueryLog creates slow query log with given capacity
+func NewSlowQueryLog(size int) *SlowQueryLog {
+	if size < 0 {
+		size = 0
+	}
+
+	return &SlowQueryLog{entries: make([]*SlowQuery, size)}
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSlowQueryLog_Entries(t *testing.T) {
+	useCases := []struct {
+		description string
+		size        int
+		added       []string
+		resize      int
+		expect      []string
+	}{
+		{
+			description: "not full log",
+			size:        3,
+			added:       []string{"v1", "v2"},
+			expect:      []string{"v2", "v1"},
+		},
+		{
+			description: "full log overrides the oldest entries",
+			size:        3,
+			added:       []string{"v1", "v2", "v3", "v4", "v5"},
+			expect:      []string{"v5", "v4", "v3"},
+		},
+		{
+			description: "empty log",
+			size:        0,
+			added:       []string{"v1"},
+			expect:      []string{},
+		},
+		{
+			description: "shrink keeps the most recent entries",
+			size:        4,
+			added:       []string{"v1", "v2", "v3", "v4"},
+			resize:      2,
+			expect:      []string{"v4", "v3"},
+		},
+		{
+			description: "grow keeps all entries",
+			size:        2,
+			added:       []string{"v1", "v2", "v3"},
+			resize:      5,
+			expect:      []string{"v3", "v2"},
+		},
+	}
+
+	for _, useCase := range useCases {
+		log := NewSlowQueryLog(useCase.size)
+		for _, viewName := range useCase.added {
+			log.Add(&SlowQuery{View: viewName})
+		}
+
+		if useCase.resize != 0 {
+			log.Resize(useCase.resize)
+		}
+
+		actual := make([]string, 0)
+		for _, entry := range log.Entries() {
+			actual = append(actual, entry.View)
+		}
+
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
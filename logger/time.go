@@ -43,6 +43,10 @@ func (t *TimeLogger) ReadingData() ReadingData {
 	return nil
 }
 
+func (t *TimeLogger) SlowQuery() SlowQueryLogger {
+	return t.defaultLogger.logSlowQuery
+}
+
 func (t *TimeLogger) ViewReadTime() ReadTime {
 	return func(viewName string, start *time.Time, end *time.Time, err error) {
 		if end.Sub(*start) < t.view {
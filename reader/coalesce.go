@@ -0,0 +1,129 @@
+package reader
+
+import (
+	"reflect"
+	"sync"
+)
+
+type (
+	//coalescer shares database rows of identical concurrent view reads
+	coalescer struct {
+		mux   sync.Mutex
+		calls map[string]*coalescedRead
+	}
+
+	coalescedRead struct {
+		wg   sync.WaitGroup
+		rows []interface{}
+		err  error
+		//shared is false when rows can't be replayed, i.e. unmapped columns were resolved by the leader collector
+		shared bool
+	}
+)
+
+var reads = newCoalescer()
+
+func newCoalescer() *coalescer {
+	return &coalescer{calls: map[string]*coalescedRead{}}
+}
+
+//do runs fn once for concurrent calls with the same key, returns false to the caller that executed fn
+func (c *coalescer) do(key string, fn func() ([]interface{}, bool, error)) (*coalescedRead, bool) {
+	c.mux.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.mux.Unlock()
+		call.wg.Wait()
+		return call, true
+	}
+
+	call := &coalescedRead{}
+	call.wg.Add(1)
+	c.calls[key] = call
+	c.mux.Unlock()
+
+	call.rows, call.shared, call.err = fn()
+	c.mux.Lock()
+	delete(c.calls, key)
+	c.mux.Unlock()
+	call.wg.Done()
+	return call, false
+}
+
+//snapshot returns deep copy of the fetched row, taken before relations are assigned to the row,
+//callers mask and modify their rows in place, thus they can't share pointer, slice or map values
+func snapshot(row interface{}) interface{} {
+	value := reflect.ValueOf(row)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return row
+	}
+
+	return deepCopy(value).Interface()
+}
+
+//replay copies shared row into the row created by the caller collector
+func replay(row interface{}, newRow func() interface{}) interface{} {
+	result := newRow()
+	target := reflect.ValueOf(result)
+	if target.Kind() != reflect.Ptr {
+		return row
+	}
+
+	target.Elem().Set(deepCopy(reflect.ValueOf(row).Elem()))
+	return result
+}
+
+func deepCopy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.New(value.Type().Elem())
+		result.Elem().Set(deepCopy(value.Elem()))
+		return result
+	case reflect.Struct:
+		result := reflect.New(value.Type()).Elem()
+		result.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if field := result.Field(i); field.CanSet() {
+				field.Set(deepCopy(value.Field(i)))
+			}
+		}
+
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(deepCopy(value.Index(i)))
+		}
+
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iterator := value.MapRange()
+		for iterator.Next() {
+			result.SetMapIndex(iterator.Key(), deepCopy(iterator.Value()))
+		}
+
+		return result
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.New(value.Type()).Elem()
+		result.Set(deepCopy(value.Elem()))
+		return result
+	}
+
+	return value
+}
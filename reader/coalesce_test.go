@@ -0,0 +1,85 @@
+package reader
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalescer_Do(t *testing.T) {
+	type row struct {
+		ID   int
+		Name *string
+		Tags []string
+	}
+
+	newRow := func(name string) *row {
+		return &row{ID: 1, Name: &name, Tags: []string{"a"}}
+	}
+
+	useCases := []struct {
+		description string
+		callers     int
+		shared      bool
+	}{
+		{description: "single caller", callers: 1, shared: true},
+		{description: "concurrent callers", callers: 10, shared: true},
+		{description: "rows not shared", callers: 5},
+	}
+
+	for _, useCase := range useCases {
+		aCoalescer := newCoalescer()
+		executed := int32(0)
+		release := make(chan bool)
+		wg := sync.WaitGroup{}
+		results := make([][]interface{}, useCase.callers)
+		for i := 0; i < useCase.callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				read, _ := aCoalescer.do("events", func() ([]interface{}, bool, error) {
+					atomic.AddInt32(&executed, 1)
+					<-release
+					fetched := newRow("abc")
+					shared := snapshot(fetched)
+					*fetched.Name, fetched.Tags[0] = "masked", "masked"
+					return []interface{}{shared}, useCase.shared, nil
+				})
+
+				if !read.shared {
+					return
+				}
+
+				for _, item := range read.rows {
+					replayed := replay(item, func() interface{} { return &row{} }).(*row)
+					results[i] = append(results[i], replayed)
+					if i > 0 {
+						*replayed.Name, replayed.Tags[0] = strconv.Itoa(i), strconv.Itoa(i)
+					}
+				}
+			}(i)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), executed, useCase.description)
+		for i, result := range results {
+			if !useCase.shared {
+				continue
+			}
+
+			expect := newRow("abc")
+			if i > 0 {
+				expect = newRow(strconv.Itoa(i))
+				expect.Tags[0] = strconv.Itoa(i)
+			}
+
+			assert.Equal(t, []interface{}{expect}, result, useCase.description)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package reader_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/reader"
+	"github.com/viant/datly/view"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestService_ReadRowFiltered(t *testing.T) {
+	dbLocation := "/tmp/datly_row_filter_test.db"
+	_ = os.Remove(dbLocation)
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	for _, DDL := range []string{
+		"CREATE TABLE accounts (id INTEGER PRIMARY KEY, tenant_id INTEGER, name TEXT)",
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, account_id INTEGER, tenant_id INTEGER, name TEXT)",
+		"INSERT INTO accounts VALUES (1, 10, 'acc1'), (2, 20, 'acc2'), (3, 10, 'acc3')",
+		"INSERT INTO orders VALUES (1, 1, 10, 'o1'), (2, 1, 20, 'o2'), (3, 2, 20, 'o3'), (4, 3, 10, 'o4')",
+	} {
+		if _, err = db.Exec(DDL); !assert.Nil(t, err, DDL) {
+			return
+		}
+	}
+
+	type order struct {
+		Id        int
+		AccountId int
+		TenantId  int
+		Name      string
+	}
+
+	type account struct {
+		Id       int
+		TenantId int
+		Name     string
+		Orders   []*order
+	}
+
+	useCases := []struct {
+		description string
+		source      string
+		tenantId    int
+		criteria    string
+		expect      string
+		expectErr   bool
+	}{
+		{
+			description: "reader path",
+			source:      "SELECT * FROM accounts",
+			tenantId:    10,
+			expect:      `[{"Id":1,"TenantId":10,"Name":"acc1","Orders":[{"Id":1,"AccountId":1,"TenantId":10,"Name":"o1"}]},{"Id":3,"TenantId":10,"Name":"acc3","Orders":[{"Id":4,"AccountId":3,"TenantId":10,"Name":"o4"}]}]`,
+		},
+		{
+			description: "criteria can't bypass row filter",
+			source:      "SELECT * FROM accounts",
+			tenantId:    20,
+			criteria:    "1 = 1 OR 1 = 1",
+			expect:      `[{"Id":2,"TenantId":20,"Name":"acc2","Orders":[{"Id":3,"AccountId":2,"TenantId":20,"Name":"o3"}]}]`,
+		},
+		{
+			description: "$ROW_FILTER template",
+			source:      "SELECT * FROM accounts t WHERE $ROW_FILTER",
+			tenantId:    20,
+			expect:      `[{"Id":2,"TenantId":20,"Name":"acc2","Orders":[{"Id":3,"AccountId":2,"TenantId":20,"Name":"o3"}]}]`,
+		},
+		{
+			description: "missing row filter value",
+			source:      "SELECT * FROM accounts",
+			expectErr:   true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		connector := &view.Connector{Name: "db", Driver: "sqlite3", DSN: dbLocation}
+		aView := &view.View{
+			Name:      "accounts",
+			Connector: connector,
+			Schema:    view.NewSchema(reflect.TypeOf(&account{})),
+			Template: &view.Template{
+				Source: useCase.source,
+				Parameters: []*view.Parameter{
+					{Name: "TenantId", In: &view.Location{Kind: view.KindQuery, Name: "tenantId"}, Schema: &view.Schema{DataType: "int"}},
+				},
+			},
+			RowFilters: view.RowFilters{{Column: "tenant_id", Param: "TenantId"}},
+			With: []*view.Relation{
+				{
+					Name: "account_orders",
+					Of: &view.ReferenceView{
+						View:   view.View{Name: "orders", Connector: connector, Table: "orders"},
+						Column: "account_id",
+					},
+					Cardinality: view.Many,
+					Column:      "id",
+					Holder:      "Orders",
+				},
+			},
+		}
+
+		resource := view.EmptyResource()
+		resource.AddViews(aView)
+		if !assert.Nil(t, resource.Init(context.TODO()), useCase.description) {
+			continue
+		}
+
+		ordersView := &aView.With[0].Of.View
+		assert.Equal(t, 1, len(ordersView.RowFilters), useCase.description)
+
+		selectors := &view.Selectors{Index: map[string]*view.Selector{}, RWMutex: sync.RWMutex{}}
+		if useCase.tenantId != 0 {
+			for _, aView := range []*view.View{aView, ordersView} {
+				selector := selectors.Lookup(aView)
+				param, err := aView.ParamByName("TenantId")
+				if !assert.Nil(t, err, useCase.description) || !assert.Nil(t, param.Set(selector, useCase.tenantId), useCase.description) {
+					continue
+				}
+			}
+		}
+		selectors.Lookup(aView).Criteria = useCase.criteria
+
+		dest := new([]*account)
+		err := reader.New().Read(context.TODO(), &reader.Session{Dest: dest, View: aView, Selectors: selectors})
+		if useCase.expectErr {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		actual, _ := json.Marshal(dest)
+		assert.Equal(t, useCase.expect, string(actual), useCase.description)
+	}
+}
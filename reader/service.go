@@ -3,6 +3,7 @@ package reader
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/viant/datly/shared"
 	"github.com/viant/datly/template/expand"
@@ -211,7 +212,7 @@ func (s *Service) queryMeta(ctx context.Context, session *Session, aView *view.V
 	selector := &selectorDeref
 
 	var indexed *cache.ParmetrizedQuery
-	var cacheStats *cache.Stats
+	var cacheStats *CacheStats
 	var metaOptions []option.Option
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
@@ -229,13 +230,13 @@ func (s *Service) queryMeta(ctx context.Context, session *Session, aView *view.V
 			return
 		}
 
-		cacheService, err := aView.Cache.Service()
+		cacheService, err := aView.Cache.TaggedService(selector)
 		if err != nil {
 			return
 		}
 
-		cacheStats = &cache.Stats{}
-		metaOptions = []option.Option{cacheService, cacheMatcher, cacheStats}
+		cacheStats = &CacheStats{Stats: &cache.Stats{}}
+		metaOptions = []option.Option{cacheService, cacheMatcher, cacheStats.Stats}
 	}()
 
 	var err error
@@ -280,15 +281,18 @@ func (s *Service) queryMeta(ctx context.Context, session *Session, aView *view.V
 		_ = stmt.Close()
 	}()
 
+	metaRows := 0
 	err = reader.QueryAll(ctx, func(row interface{}) error {
+		metaRows++
 		return collector.AddMeta(row)
 	}, args...)
 
+	finished := Now()
+	aView.LogSlowQueryIfNeeded(ctx, db, view.SQLQueryMode, SQL, args, metaRows, finished.Sub(now), err)
 	if err != nil {
 		return nil, err
 	}
 
-	finished := Now()
 	aView.Logger.Log("reading view %v meta took %v, SQL: %v , Args: %v\n", aView.Name, finished.Sub(now).String(), SQL, args)
 
 	return s.NewStats(session, indexed, cacheStats, cacheErr), nil
@@ -362,19 +366,20 @@ func (s *Service) queryObjects(ctx context.Context, session *Session, aView *vie
 		return nil, err
 	}
 
-	begin := time.Now()
-
-	var cacheStats *cache.Stats
+	var cacheStats *CacheStats
+	var cacheService cache.Cache
+	revalidation := &view.Revalidation{}
 	var options = []option.Option{io.Resolve(collector.Resolve)}
 	if session.IsCacheEnabled(aView) {
-		service, err := aView.Cache.Service()
+		service, err := aView.Cache.TaggedService(selector)
 		if err != nil {
 			fmt.Printf("err: %v\n", err.Error())
 		}
 
 		if err == nil {
-			cacheStats = &cache.Stats{}
-			options = append(options, service, cacheStats)
+			cacheService = service
+			cacheStats = &CacheStats{Stats: &cache.Stats{}}
+			options = append(options, aView.Cache.RevalidatingService(service, revalidation), cacheStats.Stats)
 		}
 	}
 
@@ -384,8 +389,67 @@ func (s *Service) queryObjects(ctx context.Context, session *Session, aView *vie
 	}
 
 	stats := s.NewStats(session, fullMatcher, cacheStats, err)
+	newRow := collector.NewItem()
+	visit := func(row interface{}) error {
+		row, err := aView.UnwrapDatabaseType(ctx, row)
+		if err != nil {
+			return err
+		}
+
+		if fetcher, ok := row.(OnFetcher); ok {
+			if err = fetcher.OnFetch(ctx); err != nil {
+				return err
+			}
+		}
+		return visitor(row)
+	}
+
+	if cacheStats == nil || columnInMatcher != nil {
+		return s.readRows(ctx, session, aView, db, fullMatcher, newRow, options, stats, visit)
+	}
+
+	var leaderErr error
+	read, coalesced := reads.do(coalesceKey(aView, fullMatcher), func() ([]interface{}, bool, error) {
+		var rows []interface{}
+		_, leaderErr = s.readRows(ctx, session, aView, db, fullMatcher, newRow, options, stats, func(row interface{}) error {
+			rows = append(rows, snapshot(row))
+			return visit(row)
+		})
+
+		return rows, !collector.HasResolved(), leaderErr
+	})
+
+	if !coalesced {
+		if leaderErr != nil {
+			return nil, leaderErr
+		}
+
+		if refreshService, ok := revalidation.BeginRefresh(cacheService); ok {
+			view.RetainDBs(revalidation, []*view.Connector{aView.Connector})
+			go s.revalidate(aView, db, fullMatcher, refreshService, revalidation)
+		}
+
+		cacheStats.Stale = revalidation.Stale
+		return stats, nil
+	}
+
+	if read.err != nil || !read.shared {
+		return s.readRows(ctx, session, aView, db, fullMatcher, newRow, options, stats, visit)
+	}
+
+	cacheStats.Coalesced = true
+	for _, row := range read.rows {
+		if err = visit(replay(row, newRow)); err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
 
-	reader, err := read.New(ctx, db, fullMatcher.SQL, collector.NewItem(), options...)
+func (s *Service) readRows(ctx context.Context, session *Session, aView *view.View, db *sql.DB, fullMatcher *cache.ParmetrizedQuery, newRow func() interface{}, options []option.Option, stats *Stats, visitor view.VisitorFn) (*Stats, error) {
+	begin := time.Now()
+	reader, err := read.New(ctx, db, fullMatcher.SQL, newRow, options...)
 	if err != nil {
 		return s.HandleSQLError(err, session, aView, fullMatcher, stats)
 	}
@@ -401,21 +465,12 @@ func (s *Service) queryObjects(ctx context.Context, session *Session, aView *vie
 
 	readData := 0
 	err = reader.QueryAll(ctx, func(row interface{}) error {
-		row, err = aView.UnwrapDatabaseType(ctx, row)
-		if err != nil {
-			return err
-		}
-
 		readData++
-		if fetcher, ok := row.(OnFetcher); ok {
-			if err = fetcher.OnFetch(ctx); err != nil {
-				return err
-			}
-		}
 		return visitor(row)
 	}, fullMatcher.Args...)
 	end := time.Now()
 	aView.Logger.ReadingData(end.Sub(begin), fullMatcher.SQL, readData, fullMatcher.Args, err)
+	aView.LogSlowQueryIfNeeded(ctx, db, view.SQLQueryMode, fullMatcher.SQL, fullMatcher.Args, readData, end.Sub(begin), err)
 	if err != nil {
 		return s.HandleSQLError(err, session, aView, fullMatcher, stats)
 	}
@@ -423,6 +478,33 @@ func (s *Service) queryObjects(ctx context.Context, session *Session, aView *vie
 	return stats, nil
 }
 
+//revalidate refreshes stale cache entry in the background, view database connection is retained by the caller, so that reload does not close it
+func (s *Service) revalidate(aView *view.View, db *sql.DB, fullMatcher *cache.ParmetrizedQuery, service cache.Cache, revalidation *view.Revalidation) {
+	defer view.ReleaseDBs(revalidation)
+	defer revalidation.EndRefresh()
+	ctx := context.Background()
+	collector := aView.Collector(new(interface{}), nil, false)
+	reader, err := read.New(ctx, db, fullMatcher.SQL, collector.NewItem(), io.Resolve(collector.Resolve), service)
+	if err == nil {
+		err = reader.QueryAll(ctx, func(row interface{}) error {
+			return nil
+		}, fullMatcher.Args...)
+
+		if stmt := reader.Stmt(); stmt != nil {
+			_ = stmt.Close()
+		}
+	}
+
+	if err != nil {
+		aView.Logger.LogDatabaseErr(fullMatcher.SQL, err)
+	}
+}
+
+func coalesceKey(aView *view.View, fullMatcher *cache.ParmetrizedQuery) string {
+	args, _ := json.Marshal(fullMatcher.Args)
+	return fmt.Sprintf("%p:%v:%s", aView, fullMatcher.SQL, args)
+}
+
 func (s *Service) HandleSQLError(err error, session *Session, aView *view.View, matcher *cache.ParmetrizedQuery, stats *Stats) (*Stats, error) {
 	if session.IncludeSQL {
 		return nil, err
@@ -433,7 +515,7 @@ func (s *Service) HandleSQLError(err error, session *Session, aView *view.View,
 	return nil, fmt.Errorf("database error occured while fetching data for view %v", aView.Name)
 }
 
-func (s *Service) NewStats(session *Session, index *cache.ParmetrizedQuery, cacheStats *cache.Stats, cacheError error) *Stats {
+func (s *Service) NewStats(session *Session, index *cache.ParmetrizedQuery, cacheStats *CacheStats, cacheError error) *Stats {
 	var SQL string
 	var args []interface{}
 	if session.IncludeSQL {
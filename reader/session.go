@@ -47,10 +47,17 @@ type (
 	Stats struct {
 		SQL        string        `json:",omitempty"`
 		Args       []interface{} `json:",omitempty"`
-		CacheStats *cache.Stats  `json:",omitempty"`
+		CacheStats *CacheStats   `json:",omitempty"`
 		Error      string        `json:",omitempty"`
 		CacheError string        `json:",omitempty"`
 	}
+
+	//CacheStats represents view cache stats
+	CacheStats struct {
+		*cache.Stats
+		Stale     bool `json:",omitempty"` //expired entry was served while refreshed in the background
+		Coalesced bool `json:",omitempty"` //rows were shared with identical concurrent read
+	}
 )
 
 func (s *Info) Name() string {
@@ -68,7 +68,12 @@ func (b *Builder) Build(aView *view.View, selector *view.Selector, batchData *vi
 
 	sb.WriteString(fromFragment)
 	sb.WriteString(template)
-	b.appendViewAlias(&sb, aView)
+	if len(aView.RowFilters) > 0 {
+		sb.WriteString(asFragment)
+		sb.WriteString(aView.RowFilterAlias())
+	} else {
+		b.appendViewAlias(&sb, aView)
+	}
 
 	columnsInMeta := hasKeyword(template, keywords.ColumnsIn)
 	commonParams := view.CriteriaParam{}
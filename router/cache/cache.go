@@ -4,10 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	goredis "github.com/go-redis/redis/v8"
 	"github.com/goccy/go-json"
 	"github.com/google/uuid"
 	"github.com/viant/afs"
 	"github.com/viant/afs/file"
+	"github.com/viant/datly/view"
+	"github.com/viant/datly/view/cache/redis"
 	"hash/fnv"
 	"io"
 	"net/http"
@@ -16,13 +19,20 @@ import (
 	"time"
 )
 
+const defaultTenantClaim = "account_id"
+
 type (
 	Cache struct {
 		TimeToLiveMs int
-		Location     string
-
-		_ttl time.Duration
-		afs  afs.Service
+		Location     string //afs location or redis://host:port/db[/prefix]
+		Scope        view.CacheScope `json:",omitempty"`
+		TenantClaim  string          `json:",omitempty"` //claim identifying tenant, account_id by default
+		UserClaim    string          `json:",omitempty"` //claim identifying user, user_id, email, username or sub by default
+
+		_ttl   time.Duration
+		afs    afs.Service
+		_redis *goredis.Client
+		_keys  string
 	}
 
 	LineReadCloser struct {
@@ -52,8 +62,21 @@ func (c *LineReadCloser) ReadLine() ([]byte, error) {
 func (c *Cache) Init(ctx context.Context) error {
 	c._ttl = time.Duration(c.TimeToLiveMs) * time.Millisecond
 	c.afs = afs.New()
+	if c.TenantClaim == "" {
+		c.TenantClaim = defaultTenantClaim
+	}
 
-	return nil
+	if !strings.HasPrefix(c.Location, redis.Scheme+"://") {
+		return nil
+	}
+
+	var err error
+	if c._redis, err = redis.Client(c.Location); err != nil {
+		return err
+	}
+
+	_, c._keys, err = redis.Split(c.Location)
+	return err
 }
 
 func (c *Cache) Get(ctx context.Context, selectors []byte, viewName string) (*Entry, error) {
@@ -67,7 +90,7 @@ func (c *Cache) Get(ctx context.Context, selectors []byte, viewName string) (*En
 		meta: Meta{
 			View:      viewName,
 			Selectors: selectors,
-			url:       c.Location + strconv.Itoa(int(key)) + ".json",
+			url:       c.entryURL(key),
 		},
 		id:  strings.ReplaceAll(uuid.New().String(), "-", ""),
 		key: key,
@@ -76,8 +99,20 @@ func (c *Cache) Get(ctx context.Context, selectors []byte, viewName string) (*En
 	return entry, c.read(ctx, entry)
 }
 
+func (c *Cache) entryURL(key uint64) string {
+	if c._redis != nil && c._keys != "" {
+		return c._keys + ":" + strconv.FormatUint(key, 10)
+	}
+
+	if c._redis != nil {
+		return strconv.FormatUint(key, 10)
+	}
+
+	return c.Location + strconv.Itoa(int(key)) + ".json"
+}
+
 func (c *Cache) close(ctx context.Context, entry *Entry) error {
-	if entry.reader == nil {
+	if entry.reader == nil || c._redis != nil {
 		return nil
 	}
 
@@ -100,6 +135,12 @@ func (c *Cache) Put(ctx context.Context, entry *Entry, response []byte, compress
 		return err
 	}
 
+	if c._redis != nil {
+		value := make([]byte, 0, len(metaBytes)+1+len(response))
+		value = append(append(append(value, metaBytes...), '\n'), response...)
+		return c._redis.Set(ctx, entry.meta.url, value, c._ttl).Err()
+	}
+
 	writeCloser, err := c.afs.NewWriter(ctx, entry.meta.url, file.DefaultFileOsMode)
 	if err != nil {
 		return err
@@ -132,16 +173,8 @@ func (c *Cache) Combine(location string, selectors []byte, name string) (uint64,
 }
 
 func (c *Cache) read(ctx context.Context, entry *Entry) error {
-	if ok, err := c.afs.Exists(ctx, entry.meta.url); !ok || err != nil {
-		return nil
-	}
-
-	readCloser, err := c.afs.OpenURL(ctx, entry.meta.url)
-	if isRateError(err) || isPreConditionError(err) {
-		return nil
-	}
-
-	if err != nil {
+	readCloser, err := c.open(ctx, entry.meta.url)
+	if readCloser == nil || err != nil {
 		return err
 	}
 
@@ -172,12 +205,43 @@ func (c *Cache) validateMeta(ctx context.Context, entry *Entry, line []byte) (bo
 
 	now := Now()
 	if now.After(cachedMeta.ExpireAt) || !bytes.Equal(entry.meta.Selectors, cachedMeta.Selectors) || entry.meta.View != cachedMeta.View {
-		return false, c.afs.Delete(ctx, entry.meta.url)
+		return false, c.delete(ctx, entry.meta.url)
 	}
 
 	return true, nil
 }
 
+//open returns cached entry reader, nil if entry was not found or storage was not available
+func (c *Cache) open(ctx context.Context, URL string) (io.ReadCloser, error) {
+	if c._redis != nil {
+		value, err := c._redis.Get(ctx, URL).Bytes()
+		if err != nil {
+			return nil, nil
+		}
+
+		return io.NopCloser(bytes.NewReader(value)), nil
+	}
+
+	if ok, err := c.afs.Exists(ctx, URL); !ok || err != nil {
+		return nil, nil
+	}
+
+	readCloser, err := c.afs.OpenURL(ctx, URL)
+	if isRateError(err) || isPreConditionError(err) {
+		return nil, nil
+	}
+
+	return readCloser, err
+}
+
+func (c *Cache) delete(ctx context.Context, URL string) error {
+	if c._redis != nil {
+		return c._redis.Del(ctx, URL).Err()
+	}
+
+	return c.afs.Delete(ctx, URL)
+}
+
 func (c *Cache) write(writer io.WriteCloser, data ...[]byte) error {
 	var err error
 	for _, value := range data {
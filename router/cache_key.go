@@ -0,0 +1,126 @@
+package router
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/viant/datly/auth/mtls"
+	"github.com/viant/datly/view"
+	"github.com/viant/toolbox"
+	"net/http"
+)
+
+var defaultUserClaims = []string{"user_id", "email", "username", "sub"}
+
+//cacheKey represents response cache key, Params include parameters skipped by the selectors JSON
+type cacheKey struct {
+	Selectors []*view.Selector
+	Params    []map[string]interface{} `json:",omitempty"`
+	Principal string                   `json:",omitempty"`
+	Tags      string                   `json:",omitempty"`
+}
+
+//RouteCacheTag returns route cache tag
+func RouteCacheTag(URI string) string {
+	return view.CacheTag(view.TagRoute, URI)
+}
+
+//cacheTags returns route response cache tags: route tag and tags of the views used by the route
+func (r *Route) cacheTags(selectors *view.Selectors) ([]string, error) {
+	tags := []string{RouteCacheTag(r.URI)}
+	for viewName, selector := range selectors.Index {
+		details, ok := r.Index.viewByName(viewName)
+		if !ok {
+			continue
+		}
+
+		viewTags, err := details.View.CacheTags(selector)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, viewTags...)
+	}
+
+	return tags, nil
+}
+
+//invalidateCache purges cache tags declared by the route, once the route executed successfully
+func (r *Route) invalidateCache(ctx context.Context, selectors *view.Selectors) error {
+	if len(r.Invalidates) == 0 {
+		return nil
+	}
+
+	tags, err := r.View.ExpandCacheTags(r.Invalidates, selectors.Lookup(r.View))
+	if err != nil {
+		return err
+	}
+
+	return view.CacheTags.Purge(ctx, tags...)
+}
+
+func (r *Route) initCacheScope() error {
+	if r.Cache.Scope == "" {
+		r.Cache.Scope = r.View.DefaultCacheScope()
+	}
+
+	if err := r.View.ValidateCacheScope(r.Cache.Scope); err != nil {
+		return fmt.Errorf("invalid route %v %v cache: %w", r.Method, r.URI, err)
+	}
+
+	return nil
+}
+
+//cachePrincipal returns hashed principal identity for tenant and user cache scopes, false if principal can't be identified
+func (r *Route) cachePrincipal(request *http.Request) (string, bool) {
+	if r.Cache.Scope == view.CacheScopePublic {
+		return "", true
+	}
+
+	var claims map[string]interface{}
+	if authorization := request.Header.Get(HeaderAuthorization); authorization != "" {
+		claims, _ = requestClaims(request, authorization)
+	}
+
+	var identity string
+	switch r.Cache.Scope {
+	case view.CacheScopeTenant:
+		identity = claimString(claims, r.Cache.TenantClaim)
+	case view.CacheScopeUser:
+		userClaims := defaultUserClaims
+		if r.Cache.UserClaim != "" {
+			userClaims = []string{r.Cache.UserClaim}
+		}
+
+		for _, claim := range userClaims {
+			if identity = claimString(claims, claim); identity != "" {
+				break
+			}
+		}
+
+		if identity == "" {
+			identity = mtls.VerifiedCertificate(request.Context())
+		}
+	}
+
+	if identity == "" {
+		return "", false
+	}
+
+	hash := sha256.Sum256([]byte(string(r.Cache.Scope) + ":" + identity))
+	return hex.EncodeToString(hash[:]), true
+}
+
+func claimString(claims map[string]interface{}, path string) string {
+	if claims == nil {
+		return ""
+	}
+
+	value, ok := claimValue(claims, path)
+	if !ok || value == nil {
+		return ""
+	}
+
+	return toolbox.AsString(value)
+}
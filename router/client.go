@@ -0,0 +1,86 @@
+package router
+
+import (
+	"github.com/viant/datly/view"
+	"reflect"
+)
+
+type (
+	//clientParam represents route parameter set by generated clients
+	clientParam struct {
+		rType    reflect.Type
+		required bool
+		param    *view.Parameter
+	}
+
+	//clientSelector represents view selector query parameters set by generated clients
+	clientSelector struct {
+		view     *view.View
+		mainView bool
+		options  []*clientSelectorOption
+	}
+
+	clientSelectorOption struct {
+		option string
+		name   string
+	}
+)
+
+//clientParams appends view and relations parameters sent with HTTP request, inputs dedupes parameters sharing location
+func clientParams(aView *view.View, params *[]*clientParam, inputs map[string]bool) {
+	if aView.Template != nil {
+		for _, param := range aView.Template.Parameters {
+			switch param.In.Kind {
+			case view.DataViewKind, view.RequestBodyKind, view.EnvironmentKind, view.LiteralKind, view.KindStructQL:
+				continue
+			}
+
+			inputKey := string(param.In.Kind) + ":" + param.In.Name
+			if inputs[inputKey] {
+				continue
+			}
+			inputs[inputKey] = true
+
+			rType := param.Schema.Type()
+			if param.Output != nil {
+				rType = reflect.TypeOf("")
+			}
+
+			*params = append(*params, &clientParam{rType: rType, required: param.IsRequired() || param.In.Kind == view.PathKind, param: param})
+		}
+	}
+
+	for _, relation := range aView.With {
+		clientParams(&relation.Of.View, params, inputs)
+	}
+}
+
+//clientSelectors appends view and relations selectors with at least one selector parameter
+func clientSelectors(aView *view.View, mainView bool, selectors *[]*clientSelector) {
+	if aView.Selector != nil {
+		selector := &clientSelector{view: aView, mainView: mainView}
+		for _, option := range []struct {
+			option string
+			param  *view.Parameter
+		}{
+			{option: "fields", param: aView.Selector.FieldsParam},
+			{option: "criteria", param: aView.Selector.CriteriaParam},
+			{option: "orderBy", param: aView.Selector.OrderByParam},
+			{option: "limit", param: aView.Selector.LimitParam},
+			{option: "offset", param: aView.Selector.OffsetParam},
+			{option: "page", param: aView.Selector.PageParam},
+		} {
+			if option.param != nil {
+				selector.options = append(selector.options, &clientSelectorOption{option: option.option, name: view.FirstNotEmpty(option.param.In.Name, option.param.Name)})
+			}
+		}
+
+		if len(selector.options) > 0 {
+			*selectors = append(*selectors, selector)
+		}
+	}
+
+	for _, relation := range aView.With {
+		clientSelectors(&relation.Of.View, false, selectors)
+	}
+}
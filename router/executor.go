@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"fmt"
 	"github.com/viant/datly/executor"
 	"net/http"
 )
@@ -42,11 +43,18 @@ func (r *Router) executorHandlerWithError(route *Route, request *http.Request) (
 
 	anExecutor := executor.New()
 
-	err = anExecutor.Exec(ctx, session)
-	if err != nil || route.ResponseBody == nil {
+	if err = anExecutor.Exec(ctx, session); err != nil {
 		return nil, err
 	}
 
+	if err = route.invalidateCache(ctx, selectors); err != nil {
+		fmt.Printf("[WARN] route %v %v executed, but failed to invalidate cache: %v\n", route.Method, route.URI, err.Error())
+	}
+
+	if route.ResponseBody == nil {
+		return nil, nil
+	}
+
 	body, err := route.execResponseBody(parameters, session)
 	if err != nil {
 		return nil, err
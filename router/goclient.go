@@ -0,0 +1,701 @@
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/viant/datly/router/marshal/json"
+	"github.com/viant/datly/view"
+	"github.com/viant/toolbox/format"
+	goFormat "go/format"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const goClientSource = `// Code generated by datly. DO NOT EDIT.
+
+package %v
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type (
+	//Client represents datly routes HTTP client
+	Client struct {
+		BaseURL    string
+		HTTPClient *http.Client
+		Header     http.Header
+	}
+
+	//Error represents route error response
+	Error struct {
+		StatusCode int
+		Status     string
+		Message    interface{}
+		Body       []byte
+	}
+
+	//Selector represents view selector, empty options are not sent
+	Selector struct {
+		Fields   []string
+		Criteria string
+		OrderBy  string
+		Limit    int
+		Offset   int
+		Page     int
+	}
+
+	//selectorNames represents view selector query parameter names, empty name means the view does not support the option
+	selectorNames struct {
+		fields   string
+		criteria string
+		orderBy  string
+		limit    string
+		offset   string
+		page     string
+	}
+)
+
+//New creates a client calling routes served under baseURL
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), HTTPClient: http.DefaultClient, Header: http.Header{}}
+}
+
+func (e *Error) Error() string {
+	if e.Message == nil {
+		return fmt.Sprintf("status %%v: %%s", e.StatusCode, e.Body)
+	}
+
+	if message, ok := e.Message.(string); ok {
+		return fmt.Sprintf("status %%v: %%v", e.StatusCode, message)
+	}
+
+	message, _ := json.Marshal(e.Message)
+	return fmt.Sprintf("status %%v: %%s", e.StatusCode, message)
+}
+
+//NewSelector creates a view selector
+func NewSelector() *Selector {
+	return &Selector{}
+}
+
+//WithFields adds fields returned by the view
+func (s *Selector) WithFields(fields ...string) *Selector {
+	s.Fields = append(s.Fields, fields...)
+	return s
+}
+
+//WithCriteria sets view criteria
+func (s *Selector) WithCriteria(criteria string) *Selector {
+	s.Criteria = criteria
+	return s
+}
+
+//WithOrderBy sets view order by column
+func (s *Selector) WithOrderBy(orderBy string) *Selector {
+	s.OrderBy = orderBy
+	return s
+}
+
+//WithLimit sets view limit
+func (s *Selector) WithLimit(limit int) *Selector {
+	s.Limit = limit
+	return s
+}
+
+//WithOffset sets view offset
+func (s *Selector) WithOffset(offset int) *Selector {
+	s.Offset = offset
+	return s
+}
+
+//WithPage sets view page
+func (s *Selector) WithPage(page int) *Selector {
+	s.Page = page
+	return s
+}
+
+func (s *Selector) encode(query url.Values, names selectorNames) error {
+	if s == nil {
+		return nil
+	}
+
+	if len(s.Fields) > 0 {
+		if err := setSelectorOption(query, "fields", names.fields, strings.Join(s.Fields, ",")); err != nil {
+			return err
+		}
+	}
+
+	if s.Criteria != "" {
+		if err := setSelectorOption(query, "criteria", names.criteria, s.Criteria); err != nil {
+			return err
+		}
+	}
+
+	if s.OrderBy != "" {
+		if err := setSelectorOption(query, "orderby", names.orderBy, s.OrderBy); err != nil {
+			return err
+		}
+	}
+
+	if s.Limit != 0 {
+		if err := setSelectorOption(query, "limit", names.limit, formatValue(s.Limit)); err != nil {
+			return err
+		}
+	}
+
+	if s.Offset != 0 {
+		if err := setSelectorOption(query, "offset", names.offset, formatValue(s.Offset)); err != nil {
+			return err
+		}
+	}
+
+	if s.Page != 0 {
+		if err := setSelectorOption(query, "page", names.page, formatValue(s.Page)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setSelectorOption(query url.Values, option, name, value string) error {
+	if name == "" {
+		return fmt.Errorf("view does not support %%v selector", option)
+	}
+
+	query.Set(name, value)
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, URI string, query url.Values, header http.Header, cookies []*http.Cookie, body interface{}, response interface{}) error {
+	URL := c.BaseURL + URI
+	if len(query) > 0 {
+		URL += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, URL, reader)
+	if err != nil {
+		return err
+	}
+
+	for key, values := range c.Header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	for key, values := range header {
+		request.Header[key] = values
+	}
+
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	httpResponse, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	data, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return err
+	}
+
+	if httpResponse.StatusCode < http.StatusOK || httpResponse.StatusCode >= http.StatusMultipleChoices {
+		responseErr := &Error{StatusCode: httpResponse.StatusCode, Body: data}
+		_ = json.Unmarshal(data, &responseErr.Message)
+		if response != nil {
+			_ = json.Unmarshal(data, response)
+		}
+
+		return responseErr
+	}
+
+	if response == nil || len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, response)
+}
+
+//statusError returns error for comprehensive style response with error status
+func statusError(err error, status string, message interface{}) error {
+	if status != "error" {
+		return err
+	}
+
+	result := &Error{StatusCode: http.StatusOK, Status: status, Message: message}
+	if actual, ok := err.(*Error); ok {
+		result.StatusCode = actual.StatusCode
+		result.Body = actual.Body
+	}
+
+	return result
+}
+
+func formatValue(value interface{}) string {
+	switch actual := value.(type) {
+	case string:
+		return actual
+	case time.Time:
+		return actual.Format(time.RFC3339)
+	}
+
+	rValue := reflect.ValueOf(value)
+	switch rValue.Kind() {
+	case reflect.Invalid:
+		return ""
+	case reflect.Ptr:
+		if rValue.IsNil() {
+			return ""
+		}
+		return formatValue(rValue.Elem().Interface())
+	case reflect.Slice:
+		items := make([]string, rValue.Len())
+		for i := range items {
+			items[i] = formatValue(rValue.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	case reflect.Struct, reflect.Map:
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+
+	return fmt.Sprint(value)
+}
+`
+
+type (
+	goClient struct {
+		packageName string
+		typeNames   map[string]bool
+		methodNames map[string]bool
+		types       map[goClientTypeKey]string
+		decls       *bytes.Buffer
+		methods     *bytes.Buffer
+	}
+
+	//goClientTypeKey identifies generated type, output types are formatted and filtered with route settings
+	goClientTypeKey struct {
+		rType   reflect.Type
+		caser   format.Case
+		output  bool
+		exclude string
+		path    string
+	}
+
+	goClientParam struct {
+		field  string
+		goType string
+		*clientParam
+	}
+
+	goClientSelector struct {
+		field string
+		names []string
+	}
+)
+
+//GenerateGoClient generates Go client package source with request, response types and a client method for each route
+func GenerateGoClient(packageName string, routes ...*Route) ([]byte, error) {
+	client := &goClient{
+		packageName: packageName,
+		typeNames:   map[string]bool{"Client": true, "Error": true, "Selector": true, "New": true, "NewSelector": true},
+		methodNames: map[string]bool{"do": true},
+		types:       map[goClientTypeKey]string{},
+		decls:       &bytes.Buffer{},
+		methods:     &bytes.Buffer{},
+	}
+
+	for _, route := range routes {
+		if err := client.addRoute(route); err != nil {
+			return nil, fmt.Errorf("failed to generate %v %v client: %w", route.Method, route.URI, err)
+		}
+	}
+
+	source := &bytes.Buffer{}
+	source.WriteString(fmt.Sprintf(goClientSource, packageName))
+	source.Write(client.decls.Bytes())
+	source.Write(client.methods.Bytes())
+	return goFormat.Source(source.Bytes())
+}
+
+func (c *goClient) addRoute(route *Route) error {
+	name := goClientName(route.View.Name)
+	if c.methodNames[name] {
+		name = goClientName(strings.ToLower(route.Method)) + name
+	}
+
+	name = uniqueName(c.methodNames, name)
+	requestType := uniqueName(c.typeNames, name+"Request")
+
+	fields := map[string]bool{}
+	params, err := c.params(route, requestType, fields)
+	if err != nil {
+		return err
+	}
+
+	selectors := c.selectors(route, fields)
+
+	var bodyField, bodyType string
+	if route.Method != http.MethodGet && route._requestBodyType != nil {
+		if bodyType, err = c.typeExpr(route, route._requestBodyType, name+"Body", "", false); err != nil {
+			return err
+		}
+
+		bodyField = uniqueName(fields, "Body")
+		if !strings.HasPrefix(bodyType, "*") && !strings.HasPrefix(bodyType, "[]") && !strings.HasPrefix(bodyType, "map[") && bodyType != "interface{}" {
+			bodyType = "*" + bodyType
+		}
+	}
+
+	decl := c.decls
+	decl.WriteString(fmt.Sprintf("\n//%v represents %v %v request\ntype %v struct {\n", requestType, route.Method, route.URI, requestType))
+	for _, param := range params {
+		decl.WriteString(fmt.Sprintf("\t%v %v\n", param.field, param.goType))
+	}
+
+	for _, selector := range selectors {
+		decl.WriteString(fmt.Sprintf("\t%v *Selector\n", selector.field))
+	}
+
+	if bodyField != "" {
+		decl.WriteString(fmt.Sprintf("\t%v %v\n", bodyField, bodyType))
+	}
+	decl.WriteString("}\n")
+
+	return c.addMethod(route, name, requestType, params, selectors, bodyField)
+}
+
+func (c *goClient) addMethod(route *Route, name string, requestType string, params []*goClientParam, selectors []*goClientSelector, bodyField string) error {
+	hasResult := route.Service != ExecutorServiceType || route.ResponseBody != nil
+	var resultType, envelopeType, envelopeField string
+	var err error
+	switch {
+	case !hasResult:
+	case route._responseSetter != nil:
+		responseType := route.responseType()
+		if envelopeType, err = c.typeExpr(route, responseType, name+"Response", "", true); err != nil {
+			return err
+		}
+
+		field, ok := responseType.FieldByName(route.ResponseField)
+		if !ok {
+			return fmt.Errorf("failed to lookup response field %v", route.ResponseField)
+		}
+
+		envelopeField = goClientFieldName(field.Name)
+		if resultType, err = c.typeExpr(route, field.Type, envelopeType+envelopeField, field.Name, true); err != nil {
+			return err
+		}
+	case route.Service == ExecutorServiceType:
+		if resultType, err = c.typeExpr(route, route.ResponseBody._bodyType, name+"Response", "", true); err != nil {
+			return err
+		}
+	default:
+		rType := route.View.Schema.Type()
+		if route.Cardinality == view.Many {
+			rType = reflect.SliceOf(rType)
+		} else if rType.Kind() == reflect.Struct {
+			rType = reflect.PtrTo(rType)
+		}
+
+		if resultType, err = c.typeExpr(route, rType, goClientName(route.View.Name), "", true); err != nil {
+			return err
+		}
+	}
+
+	sb := c.methods
+	returnValues := "err"
+	if hasResult {
+		returnValues = "result, err"
+		sb.WriteString(fmt.Sprintf("\n//%v calls %v %v\nfunc (c *Client) %v(ctx context.Context, request *%v) (result %v, err error) {\n", name, route.Method, route.URI, name, requestType, resultType))
+	} else {
+		sb.WriteString(fmt.Sprintf("\n//%v calls %v %v\nfunc (c *Client) %v(ctx context.Context, request *%v) (err error) {\n", name, route.Method, route.URI, name, requestType))
+	}
+
+	sb.WriteString(fmt.Sprintf("\tif request == nil {\n\t\trequest = &%v{}\n\t}\n", requestType))
+	sb.WriteString(fmt.Sprintf("\tURI := %v\n\tquery := url.Values{}\n\theader := http.Header{}\n\tvar cookies []*http.Cookie\n", strconv.Quote(route.URI)))
+	for _, param := range params {
+		c.appendParamPlacement(sb, param)
+	}
+
+	for _, selector := range selectors {
+		sb.WriteString(fmt.Sprintf("\tif err = request.%v.encode(query, selectorNames{%v}); err != nil {\n\t\treturn %v\n\t}\n", selector.field, strings.Join(selector.names, ", "), returnValues))
+	}
+
+	body := "nil"
+	if bodyField != "" {
+		body = "body"
+		sb.WriteString(fmt.Sprintf("\tvar body interface{}\n\tif request.%v != nil {\n\t\tbody = request.%v\n\t}\n", bodyField, bodyField))
+	}
+
+	call := fmt.Sprintf("c.do(ctx, %v, URI, query, header, cookies, %v, ", strconv.Quote(route.Method), body)
+	switch {
+	case !hasResult:
+		sb.WriteString("\treturn " + call + "nil)\n")
+	case envelopeType != "":
+		sb.WriteString(fmt.Sprintf("\tvar response %v\n\terr = %v&response)\n", strings.TrimPrefix(envelopeType, "*"), call))
+		sb.WriteString("\tif err = statusError(err, response.Status, response.Message); err != nil {\n\t\treturn result, err\n\t}\n")
+		sb.WriteString(fmt.Sprintf("\treturn response.%v, nil\n", envelopeField))
+	default:
+		sb.WriteString("\terr = " + call + "&result)\n\treturn result, err\n")
+	}
+
+	sb.WriteString("}\n")
+	return nil
+}
+
+func (c *goClient) appendParamPlacement(sb *bytes.Buffer, param *goClientParam) {
+	value := "request." + param.field
+	var placement string
+	switch param.param.In.Kind {
+	case view.PathKind:
+		sb.WriteString(fmt.Sprintf("\tURI = strings.Replace(URI, %v, url.PathEscape(formatValue(%v)), 1)\n", strconv.Quote("{"+param.param.In.Name+"}"), value))
+		return
+	case view.HeaderKind:
+		placement = fmt.Sprintf("header.Set(%v, formatValue(%v))", strconv.Quote(param.param.In.Name), value)
+	case view.CookieKind:
+		placement = fmt.Sprintf("cookies = append(cookies, &http.Cookie{Name: %v, Value: formatValue(%v)})", strconv.Quote(param.param.In.Name), value)
+	default:
+		placement = fmt.Sprintf("query.Set(%v, formatValue(%v))", strconv.Quote(param.param.In.Name), value)
+	}
+
+	switch {
+	case param.required:
+		sb.WriteString("\t" + placement + "\n")
+	case strings.HasPrefix(param.goType, "[]"):
+		sb.WriteString(fmt.Sprintf("\tif len(%v) > 0 {\n\t\t%v\n\t}\n", value, placement))
+	default:
+		sb.WriteString(fmt.Sprintf("\tif %v != nil {\n\t\t%v\n\t}\n", value, placement))
+	}
+}
+
+func (c *goClient) params(route *Route, requestType string, fields map[string]bool) ([]*goClientParam, error) {
+	var params []*clientParam
+	clientParams(route.View, &params, map[string]bool{})
+	result := make([]*goClientParam, 0, len(params))
+	for _, param := range params {
+		field := uniqueName(fields, goClientName(param.param.Name))
+		goType, err := c.typeExpr(route, param.rType, requestType+field, "", false)
+		if err != nil {
+			return nil, err
+		}
+
+		if !param.required {
+			switch param.rType.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			default:
+				goType = "*" + goType
+			}
+		}
+
+		result = append(result, &goClientParam{field: field, goType: goType, clientParam: param})
+	}
+
+	return result, nil
+}
+
+func (c *goClient) selectors(route *Route, fields map[string]bool) []*goClientSelector {
+	var selectors []*clientSelector
+	clientSelectors(route.View, true, &selectors)
+	result := make([]*goClientSelector, 0, len(selectors))
+	for _, selector := range selectors {
+		field := "Selector"
+		if !selector.mainView {
+			field = goClientName(selector.view.Name) + "Selector"
+		}
+
+		names := make([]string, 0, len(selector.options))
+		for _, option := range selector.options {
+			names = append(names, option.option+": "+strconv.Quote(option.name))
+		}
+
+		result = append(result, &goClientSelector{field: uniqueName(fields, field), names: names})
+	}
+
+	return result
+}
+
+func (c *goClient) typeExpr(route *Route, rType reflect.Type, hint string, path string, output bool) (string, error) {
+	switch rType.Kind() {
+	case reflect.Ptr:
+		elem, err := c.typeExpr(route, rType.Elem(), hint, path, output)
+		return "*" + elem, err
+	case reflect.Slice:
+		if rType.Elem().Kind() == reflect.Uint8 {
+			return "[]byte", nil
+		}
+		elem, err := c.typeExpr(route, rType.Elem(), hint, path, output)
+		return "[]" + elem, err
+	case reflect.Array:
+		elem, err := c.typeExpr(route, rType.Elem(), hint, path, output)
+		return fmt.Sprintf("[%v]%v", rType.Len(), elem), err
+	case reflect.Map:
+		key, err := c.typeExpr(route, rType.Key(), hint+"Key", path, output)
+		if err != nil {
+			return "", err
+		}
+		elem, err := c.typeExpr(route, rType.Elem(), hint, path, output)
+		return "map[" + key + "]" + elem, err
+	case reflect.Interface:
+		return "interface{}", nil
+	case reflect.Struct:
+		if rType == timeType {
+			return "time.Time", nil
+		}
+		return c.structType(route, rType, hint, path, output)
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rType.Kind().String(), nil
+	}
+
+	return "", fmt.Errorf("unsupported Go client type %v", rType.String())
+}
+
+func (c *goClient) structType(route *Route, rType reflect.Type, hint string, path string, output bool) (string, error) {
+	key := goClientTypeKey{rType: rType, caser: *route._caser, output: output}
+	if output && len(route.Exclude) > 0 {
+		key.exclude = strings.Join(route.Exclude, ",")
+		key.path = path
+	}
+
+	if name, ok := c.types[key]; ok {
+		return name, nil
+	}
+
+	name := view.FirstNotEmpty(rType.Name(), hint)
+	if route._resource != nil {
+		if typeName, ok := route._resource.TypeName(rType); ok {
+			name = typeName
+		}
+	}
+
+	name = uniqueName(c.typeNames, goClientName(name))
+	c.types[key] = name
+
+	decl := &bytes.Buffer{}
+	decl.WriteString(fmt.Sprintf("\ntype %v struct {\n", name))
+	for i := 0; i < rType.NumField(); i++ {
+		field := rType.Field(i)
+		if field.Anonymous {
+			fieldType, err := c.typeExpr(route, field.Type, name+field.Name, path, output)
+			if err != nil {
+				return "", err
+			}
+
+			decl.WriteString("\t" + fieldType + "\n")
+			continue
+		}
+
+		tag := json.Parse(field.Tag.Get(json.TagName))
+		if tag.FieldName == "-" || (field.PkgPath != "" && tag.FieldName == "") {
+			continue
+		}
+
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		if output && route._excluded[fieldPath] {
+			continue
+		}
+
+		jsonName := tag.FieldName
+		if jsonName == "" {
+			jsonName = field.Name
+			if *route._caser != 0 {
+				jsonName = json.FormatName(jsonName, *route._caser)
+			}
+		}
+
+		if tag.OmitEmpty || route.OmitEmpty {
+			jsonName += ",omitempty"
+		}
+
+		goFieldName := goClientFieldName(field.Name)
+		fieldType, err := c.typeExpr(route, field.Type, name+goFieldName, fieldPath, output)
+		if err != nil {
+			return "", err
+		}
+
+		decl.WriteString(fmt.Sprintf("\t%v %v `json:%v`\n", goFieldName, fieldType, strconv.Quote(jsonName)))
+	}
+	decl.WriteString("}\n")
+
+	c.decls.Write(decl.Bytes())
+	return name, nil
+}
+
+func goClientFieldName(name string) string {
+	if name == "" || unicode.IsUpper(rune(name[0])) {
+		return name
+	}
+
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func goClientName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+
+	if caseFormat, err := format.NewCase(view.DetectCase(name)); err == nil {
+		name = caseFormat.Format(name, format.CaseUpperCamel)
+	}
+
+	name = strings.ReplaceAll(name, "_", "")
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+
+	return goClientFieldName(name)
+}
+
+func uniqueName(names map[string]bool, name string) string {
+	candidate := name
+	for i := 1; names[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+
+	names[candidate] = true
+	return candidate
+}
@@ -0,0 +1,130 @@
+package router
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/view"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateGoClient(t *testing.T) {
+	type Product struct {
+		ID       int
+		Name     *string
+		VendorID int
+	}
+
+	type Vendor struct {
+		ID       int
+		Name     string `json:",omitempty"`
+		Products []*Product
+		Has      *struct{ Name bool } `json:"-"`
+	}
+
+	productsView := view.View{
+		Name:   "products",
+		Schema: view.NewSchema(reflect.TypeOf(&Product{})),
+		Selector: &view.Config{
+			CriteriaParam: &view.Parameter{Name: "_criteria", In: view.NewQueryLocation("pr_criteria")},
+		},
+	}
+
+	newVendorView := func() *view.View {
+		return &view.View{
+			Name:   "vendor",
+			Schema: view.NewSchema(reflect.TypeOf(&Vendor{})),
+			Template: &view.Template{Parameters: []*view.Parameter{
+				{Name: "vendorID", In: &view.Location{Kind: view.PathKind, Name: "vendorID"}, Schema: view.NewSchema(reflect.TypeOf(0))},
+				{Name: "name", In: view.NewQueryLocation("name"), Schema: view.NewSchema(reflect.TypeOf(""))},
+				{Name: "Jwt", In: &view.Location{Kind: view.HeaderKind, Name: "Authorization"}, Schema: view.NewSchema(reflect.TypeOf(""))},
+			}},
+			Selector: &view.Config{
+				FieldsParam:  &view.Parameter{Name: "_fields", In: view.NewQueryLocation("_fields")},
+				OrderByParam: &view.Parameter{Name: "_orderby", In: view.NewQueryLocation("_orderby")},
+			},
+			With: []*view.Relation{{Name: "products", Of: &view.ReferenceView{View: productsView}}},
+		}
+	}
+
+	useCases := []struct {
+		description string
+		route       *Route
+		expect      []string
+	}{
+		{
+			description: "comprehensive style reader",
+			route: &Route{
+				Method: http.MethodGet,
+				URI:    "/v1/api/vendors/{vendorID}",
+				View:   newVendorView(),
+				Output: Output{Cardinality: view.One, CaseFormat: "lc", Style: ComprehensiveStyle, ResponseField: "Data", Exclude: []string{"Data.Products.VendorID"}},
+			},
+			expect: []string{
+				"type VendorRequest struct {\n\tVendorID         int\n\tName             *string\n\tJwt              *string\n\tSelector         *Selector\n\tProductsSelector *Selector\n}",
+				"func (c *Client) Vendor(ctx context.Context, request *VendorRequest) (result Vendor, err error) {",
+				"URI = strings.Replace(URI, \"{vendorID}\", url.PathEscape(formatValue(request.VendorID)), 1)",
+				"if request.Name != nil {\n\t\tquery.Set(\"name\", formatValue(request.Name))\n\t}",
+				"header.Set(\"Authorization\", formatValue(request.Jwt))",
+				"request.Selector.encode(query, selectorNames{fields: \"_fields\", orderBy: \"_orderby\"})",
+				"request.ProductsSelector.encode(query, selectorNames{criteria: \"pr_criteria\"})",
+				"type VendorResponse struct {\n\tResponseStatus\n\tData Vendor `json:\"data\"`\n}",
+				"type Vendor struct {\n\tID       int        `json:\"id\"`\n\tName     string     `json:\"name,omitempty\"`\n\tProducts []*Product `json:\"products\"`\n}",
+				"type Product struct {\n\tID   int     `json:\"id\"`\n\tName *string `json:\"name\"`\n}",
+				"if err = statusError(err, response.Status, response.Message); err != nil {",
+				"return response.Data, nil",
+			},
+		},
+		{
+			description: "basic style reader",
+			route: &Route{
+				Method: http.MethodGet,
+				URI:    "/v1/api/vendors",
+				View:   newVendorView(),
+				Output: Output{Cardinality: view.Many},
+			},
+			expect: []string{
+				"func (c *Client) Vendor(ctx context.Context, request *VendorRequest) (result []*Vendor, err error) {",
+				"type Vendor struct {\n\tID       int        `json:\"ID\"`\n\tName     string     `json:\"Name,omitempty\"`\n\tProducts []*Product `json:\"Products\"`\n}",
+				"err = c.do(ctx, \"GET\", URI, query, header, cookies, nil, &result)",
+			},
+		},
+		{
+			description: "executor with request body",
+			route: &Route{
+				Method:           http.MethodPost,
+				URI:              "/v1/api/products",
+				Service:          ExecutorServiceType,
+				View:             &productsView,
+				_requestBodyType: reflect.TypeOf(Product{}),
+			},
+			expect: []string{
+				"type ProductsRequest struct {\n\tSelector *Selector\n\tBody     *Product\n}",
+				"func (c *Client) Products(ctx context.Context, request *ProductsRequest) (err error) {",
+				"return c.do(ctx, \"POST\", URI, query, header, cookies, body, nil)",
+			},
+		},
+	}
+
+	for _, useCase := range useCases {
+		route := useCase.route
+		route._resource = view.EmptyResource()
+		if !assert.Nil(t, route.initCaser(), useCase.description) || !assert.Nil(t, route.initStyle(), useCase.description) {
+			continue
+		}
+		route.indexExcluded()
+
+		source, err := GenerateGoClient("vendor", route)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		_, err = parser.ParseFile(token.NewFileSet(), "client.go", source, 0)
+		assert.Nil(t, err, useCase.description)
+		for _, expect := range useCase.expect {
+			assert.Contains(t, string(source), expect, useCase.description)
+		}
+	}
+}
@@ -256,7 +256,7 @@ func (j *Marshaller) newFieldMarshaller(marshallers *[]*fieldMarshaller, field r
 	if tag.FieldName != "" {
 		jsonName = tag.FieldName
 	} else if config.CaseFormat != 0 {
-		jsonName = formatName(jsonName, config.CaseFormat)
+		jsonName = FormatName(jsonName, config.CaseFormat)
 	}
 
 	path, outputPath = addToPath(path, field.Name), addToPath(outputPath, jsonName)
@@ -280,7 +280,8 @@ func (j *Marshaller) newFieldMarshaller(marshallers *[]*fieldMarshaller, field r
 	return nil
 }
 
-func formatName(jsonName string, caseFormat format.Case) string {
+//FormatName returns JSON field name formatted with caseFormat
+func FormatName(jsonName string, caseFormat format.Case) string {
 	if jsonName == "ID" {
 		switch caseFormat {
 		case format.CaseLowerUnderscore, format.CaseLower, format.CaseLowerCamel:
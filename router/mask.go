@@ -0,0 +1,305 @@
+package router
+
+import (
+	"fmt"
+	"github.com/viant/datly/router/marshal/json"
+	"github.com/viant/datly/shared"
+	"github.com/viant/datly/view"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+type (
+	viewMasks struct {
+		view      *view.View
+		columns   []*columnMask
+		relations []*relationMasks
+	}
+
+	relationMasks struct {
+		holder string
+		masks  *viewMasks
+	}
+
+	columnMask struct {
+		column *view.Column
+		mask   *view.Mask
+		policy *Policy
+		usage  *regexp.Regexp
+	}
+
+	//maskSession represents masks applied to the request caller
+	maskSession struct {
+		route  *Route
+		active map[*columnMask]bool
+	}
+)
+
+func (r *Route) initMasks() error {
+	masks, err := newViewMasks(r.View, map[*view.View]bool{})
+	if err != nil {
+		return err
+	}
+
+	r._masks = masks
+	return nil
+}
+
+func newViewMasks(aView *view.View, visited map[*view.View]bool) (*viewMasks, error) {
+	if visited[aView] {
+		return nil, nil
+	}
+	visited[aView] = true
+
+	result := &viewMasks{view: aView}
+	for _, column := range aView.Columns {
+		if column.Mask == nil {
+			continue
+		}
+
+		policy := &Policy{Roles: column.Mask.Roles, Scopes: column.Mask.Scopes, Claims: column.Mask.Claims}
+		if err := policy.Init(nil); err != nil {
+			return nil, fmt.Errorf("invalid view %v column %v mask: %w", aView.Name, column.Name, err)
+		}
+
+		result.columns = append(result.columns, &columnMask{
+			column: column,
+			mask:   column.Mask,
+			policy: policy,
+			usage:  regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(column.Name) + `\b`),
+		})
+	}
+
+	for _, relation := range aView.With {
+		relationMask, err := newViewMasks(&relation.Of.View, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		if relationMask != nil {
+			result.relations = append(result.relations, &relationMasks{holder: relation.Holder, masks: relationMask})
+		}
+	}
+
+	if len(result.columns) == 0 && len(result.relations) == 0 {
+		return nil, nil
+	}
+
+	return result, nil
+}
+
+//newMaskSession evaluates column masks against request caller claims
+func (r *Route) newMaskSession(request *http.Request) *maskSession {
+	if r._masks == nil {
+		return nil
+	}
+
+	var claims map[string]interface{}
+	if authorization := request.Header.Get(HeaderAuthorization); authorization != "" {
+		claims, _ = requestClaims(request, authorization)
+	}
+
+	session := &maskSession{route: r, active: map[*columnMask]bool{}}
+	session.init(r._masks, claims, request)
+	return session
+}
+
+func (s *maskSession) init(masks *viewMasks, claims map[string]interface{}, request *http.Request) {
+	for _, column := range masks.columns {
+		if claims == nil || !column.policy.hasRequirements() || column.policy.AuthorizeClaims(claims, request, s.route) != nil {
+			s.active[column] = true
+		}
+	}
+
+	for _, relation := range masks.relations {
+		s.init(relation.masks, claims, request)
+	}
+}
+
+//validate checks that masked columns are not used by criteria, order by or projection
+func (s *maskSession) validate(selectors *view.Selectors) error {
+	if s == nil || len(s.active) == 0 {
+		return nil
+	}
+
+	selectors.Lock()
+	defer selectors.Unlock()
+	return s.validateView(s.route._masks, selectors)
+}
+
+func (s *maskSession) validateView(masks *viewMasks, selectors *view.Selectors) error {
+	if selector, ok := selectors.Index[masks.view.Name]; ok {
+		for _, column := range masks.columns {
+			if !s.active[column] {
+				continue
+			}
+
+			if column.orderedBy(selector.OrderBy) || column.usage.MatchString(selector.Criteria) {
+				return &AuthError{StatusCode: http.StatusForbidden, Message: fmt.Sprintf("column %v of view %v is masked and can't be used in criteria or order by", column.column.Name, masks.view.Name)}
+			}
+
+			if column.mask.Strategy == view.MaskDrop && selector.Has(column.column.FieldName()) {
+				return &AuthError{StatusCode: http.StatusForbidden, Message: fmt.Sprintf("column %v of view %v is not permitted", column.column.Name, masks.view.Name)}
+			}
+		}
+	}
+
+	for _, relation := range masks.relations {
+		if err := s.validateView(relation.masks, selectors); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//orderedBy returns true if order by list i.e. "name, ssn desc" uses the masked column
+func (c *columnMask) orderedBy(orderBy string) bool {
+	for _, item := range strings.Split(orderBy, ",") {
+		fields := strings.Fields(item)
+		if len(fields) == 0 {
+			continue
+		}
+
+		name := fields[0]
+		if index := strings.LastIndex(name, "."); index != -1 {
+			name = name[index+1:]
+		}
+
+		if strings.EqualFold(name, c.column.Name) || strings.EqualFold(name, c.column.FieldName()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//apply masks read values
+func (s *maskSession) apply(value reflect.Value) {
+	if s == nil || len(s.active) == 0 {
+		return
+	}
+
+	s.applyView(s.route._masks, value)
+}
+
+func (s *maskSession) applyView(masks *viewMasks, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return
+		}
+		s.applyView(masks, value.Elem())
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			s.applyView(masks, value.Index(i))
+		}
+	case reflect.Struct:
+		for _, column := range masks.columns {
+			if !s.active[column] || column.column.Field() == nil {
+				continue
+			}
+
+			column.mask.Apply(value.FieldByIndex(column.column.Field().Index))
+		}
+
+		for _, relation := range masks.relations {
+			if holder := value.FieldByName(relation.holder); holder.IsValid() {
+				s.applyView(relation.masks, holder)
+			}
+		}
+	}
+}
+
+//filters excludes dropped columns from the JSON output
+func (s *maskSession) filters(route *Route, filters []*json.FilterEntry) []*json.FilterEntry {
+	if s == nil || len(s.active) == 0 {
+		return filters
+	}
+
+	return s.appendFilters(route, s.route._masks, filters)
+}
+
+func (s *maskSession) appendFilters(route *Route, masks *viewMasks, filters []*json.FilterEntry) []*json.FilterEntry {
+	dropped := map[string]bool{}
+	for _, column := range masks.columns {
+		if s.active[column] && column.mask.Strategy == view.MaskDrop {
+			dropped[column.column.FieldName()] = true
+		}
+	}
+
+	if len(dropped) > 0 {
+		aPath := ""
+		if details, ok := route.Index.viewByName(masks.view.Name); ok {
+			aPath = details.Path
+		}
+
+		var entry *json.FilterEntry
+		for _, candidate := range filters {
+			if candidate.Path == aPath {
+				entry = candidate
+			}
+		}
+
+		if entry == nil {
+			entry = &json.FilterEntry{Path: aPath, Fields: viewFields(masks.view)}
+			filters = append(filters, entry)
+		}
+
+		fields := make([]string, 0, len(entry.Fields))
+		for _, field := range entry.Fields {
+			if !dropped[field] {
+				fields = append(fields, field)
+			}
+		}
+		entry.Fields = fields
+	}
+
+	for _, relation := range masks.relations {
+		filters = s.appendFilters(route, relation.masks, filters)
+	}
+
+	return filters
+}
+
+//lookup returns mask of the view struct field
+func (m *viewMasks) lookup(rType reflect.Type, fieldName string) *view.Mask {
+	if m == nil {
+		return nil
+	}
+
+	if m.view.Schema != nil && shared.Elem(m.view.Schema.Type()) == rType {
+		for _, column := range m.columns {
+			if field := column.column.Field(); field != nil && field.Name == fieldName {
+				return column.mask
+			}
+		}
+	}
+
+	for _, relation := range m.relations {
+		if mask := relation.masks.lookup(rType, fieldName); mask != nil {
+			return mask
+		}
+	}
+
+	return nil
+}
+
+func viewFields(aView *view.View) []string {
+	fields := make([]string, 0, len(aView.Columns)+len(aView.With))
+	for _, column := range aView.Columns {
+		fields = append(fields, column.FieldName())
+	}
+
+	for _, relation := range aView.With {
+		fields = append(fields, relation.Holder)
+	}
+
+	return fields
+}
+
+func (p *Policy) hasRequirements() bool {
+	return len(p.Roles) > 0 || len(p.Groups) > 0 || len(p.Scopes) > 0 || len(p._predicates) > 0
+}
@@ -0,0 +1,39 @@
+package router
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/view"
+	"regexp"
+	"testing"
+)
+
+func TestMaskSession_Validate(t *testing.T) {
+	useCases := []struct {
+		description string
+		orderBy     string
+		criteria    string
+		expectErr   bool
+	}{
+		{description: "not masked column", orderBy: "name"},
+		{description: "masked column", orderBy: "ssn", expectErr: true},
+		{description: "masked column different case with direction", orderBy: "SSN desc", expectErr: true},
+		{description: "masked column in order by list", orderBy: "name asc, ssn", expectErr: true},
+		{description: "masked column with alias", orderBy: "t.ssn", expectErr: true},
+		{description: "column with masked column prefix", orderBy: "ssn_verified"},
+		{description: "masked column in criteria", criteria: "SSN = '123'", expectErr: true},
+	}
+
+	for _, useCase := range useCases {
+		column := &columnMask{
+			column: &view.Column{Name: "ssn"},
+			mask:   &view.Mask{Strategy: view.MaskNull},
+			usage:  regexp.MustCompile(`(?i)\bssn\b`),
+		}
+
+		route := &Route{_masks: &viewMasks{view: &view.View{Name: "users"}, columns: []*columnMask{column}}}
+		session := &maskSession{route: route, active: map[*columnMask]bool{column: true}}
+		selectors := &view.Selectors{Index: map[string]*view.Selector{"users": {OrderBy: useCase.orderBy, Criteria: useCase.criteria}}}
+		err := session.validate(selectors)
+		assert.Equal(t, useCase.expectErr, err != nil, useCase.description)
+	}
+}
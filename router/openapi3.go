@@ -28,6 +28,8 @@ const (
 	int64Format  = "int64"
 	doubleFormat = "double"
 	empty        = ""
+
+	bearerSecurityScheme = "bearerAuth"
 )
 
 var (
@@ -60,6 +62,7 @@ func (g *generator) GenerateSpec(info openapi3.Info, route ...*Route) (*openapi3
 
 	components.Schemas = g.commonSchemas
 	components.Parameters = g.commonParameters
+	components.SecuritySchemes = g.securitySchemes(route)
 
 	return &openapi3.OpenAPI{
 		OpenAPI:      "3.1.0",
@@ -260,6 +263,10 @@ func (g *generator) addToSchema(schema *openapi3.Schema, route *Route, rType ref
 				return err
 			}
 
+			if mask := route._masks.lookup(rType, aField.Name); mask != nil {
+				schema.Properties[fieldName].Description = mask.Description()
+			}
+
 			if defaultTag.IsRequired() {
 				schema.Required = append(schema.Required, fieldName)
 			}
@@ -316,7 +323,11 @@ func (g *generator) generatePaths(routes []*Route) (openapi3.Paths, error) {
 	paths := openapi3.Paths{}
 
 	for _, route := range routes {
-		pathItem := &openapi3.PathItem{}
+		pathItem, ok := paths[route.URI]
+		if !ok {
+			pathItem = &openapi3.PathItem{}
+		}
+
 		operation, err := g.generateOperation(route, route.Method)
 		if err != nil {
 			return nil, err
@@ -327,6 +338,12 @@ func (g *generator) generatePaths(routes []*Route) (openapi3.Paths, error) {
 			pathItem.Get = operation
 		case http.MethodPost:
 			pathItem.Post = operation
+		case http.MethodPut:
+			pathItem.Put = operation
+		case http.MethodPatch:
+			pathItem.Patch = operation
+		case http.MethodDelete:
+			pathItem.Delete = operation
 		}
 
 		paths[route.URI] = pathItem
@@ -361,6 +378,7 @@ func (g *generator) generateOperation(route *Route, method string) (*openapi3.Op
 		Responses:   responses,
 	}
 
+	g.addSecurity(operation, route)
 	return operation, nil
 }
 
@@ -499,7 +517,7 @@ func (g *generator) indexParameters(parameters []*openapi3.Parameter) openapi3.P
 }
 
 func (g *generator) requestBody(route *Route, method string) (*openapi3.RequestBody, error) {
-	if method != http.MethodPost || route._requestBodyType == nil {
+	if (method != http.MethodPost && method != http.MethodPut && method != http.MethodPatch) || route._requestBodyType == nil {
 		return nil, nil
 	}
 
@@ -557,6 +575,40 @@ func (g *generator) responses(route *Route, method string) (openapi3.Responses,
 	return responses, nil
 }
 
+func (g *generator) securitySchemes(routes []*Route) openapi3.SecuritySchemes {
+	for _, route := range routes {
+		if route.Auth != nil {
+			return openapi3.SecuritySchemes{
+				bearerSecurityScheme: &openapi3.SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) addSecurity(operation *openapi3.Operation, route *Route) {
+	policy := route.Auth
+	if policy == nil {
+		return
+	}
+
+	scopes := policy.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	operation.Security = &openapi3.SecurityRequirements{{bearerSecurityScheme: scopes}}
+	if operation.Description != "" {
+		operation.Description += "\n\n"
+	}
+	operation.Description += policy.Description()
+	if operation.Responses != nil {
+		operation.Responses["401"] = &openapi3.Response{Description: stringPtr("Unauthorized, authorization token was missing or invalid")}
+		operation.Responses["403"] = &openapi3.Response{Description: stringPtr("Forbidden, authorization policy requirements were not satisfied")}
+	}
+}
+
 func stringPtr(value string) *string {
 	return &value
 }
@@ -0,0 +1,418 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/datly/gateway/registry"
+	"github.com/viant/toolbox"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultRolesClaim  = "roles"
+	defaultGroupsClaim = "groups"
+	scopeClaim         = "scope"
+	dataClaim          = "dat"
+
+	claimsSource = "claims"
+	pathSource   = "path"
+	querySource  = "query"
+	headerSource = "header"
+)
+
+type (
+	//Policy represents route authorization policy
+	Policy struct {
+		Name        string   `json:",omitempty"`
+		Ref         string   `json:",omitempty"`
+		Roles       []string `json:",omitempty"` //at least one of the roles is required
+		Groups      []string `json:",omitempty"` //at least one of the groups is required
+		Scopes      []string `json:",omitempty"` //all scopes are required
+		Claims      []string `json:",omitempty"` //claim predicates i.e. claims.tenantId == path.tenantId
+		RolesClaim  string   `json:",omitempty"`
+		GroupsClaim string   `json:",omitempty"`
+
+		initialized bool
+		_predicates []*claimPredicate
+	}
+
+	//Policies represents shared policies library
+	Policies []*Policy
+
+	claimPredicate struct {
+		expression string
+		left       *claimOperand
+		right      *claimOperand
+		negated    bool
+	}
+
+	claimOperand struct {
+		source string
+		name   string
+	}
+
+	//AuthError represents authorization error
+	AuthError struct {
+		StatusCode int
+		Message    string
+	}
+)
+
+func (e *AuthError) Error() string {
+	return e.Message
+}
+
+func (r *Route) initAuth(resource *Resource) error {
+	if r.Auth == nil {
+		return nil
+	}
+
+	var policies Policies
+	if resource != nil {
+		policies = resource.Policies
+	}
+
+	if err := r.Auth.Init(policies); err != nil {
+		return fmt.Errorf("invalid route %v %v auth policy: %w", r.Method, r.URI, err)
+	}
+
+	return nil
+}
+
+//Lookup returns policy with given name
+func (p Policies) Lookup(name string) (*Policy, error) {
+	for _, policy := range p {
+		if policy.Name == name {
+			return policy, nil
+		}
+	}
+
+	return nil, fmt.Errorf("not found policy %v", name)
+}
+
+//Init initializes policy, inherits from shared policy if Ref is specified
+func (p *Policy) Init(policies Policies) error {
+	if p.initialized {
+		return nil
+	}
+	p.initialized = true
+
+	if p.Ref != "" {
+		shared, err := policies.Lookup(p.Ref)
+		if err != nil {
+			return err
+		}
+
+		if err = shared.Init(policies); err != nil {
+			return err
+		}
+
+		p.inherit(shared)
+	}
+
+	if p.RolesClaim == "" {
+		p.RolesClaim = defaultRolesClaim
+	}
+
+	if p.GroupsClaim == "" {
+		p.GroupsClaim = defaultGroupsClaim
+	}
+
+	p._predicates = make([]*claimPredicate, 0, len(p.Claims))
+	for _, expression := range p.Claims {
+		predicate, err := newClaimPredicate(expression)
+		if err != nil {
+			return err
+		}
+
+		p._predicates = append(p._predicates, predicate)
+	}
+
+	return nil
+}
+
+func (p *Policy) inherit(policy *Policy) {
+	if len(p.Roles) == 0 {
+		p.Roles = policy.Roles
+	}
+
+	if len(p.Groups) == 0 {
+		p.Groups = policy.Groups
+	}
+
+	if len(p.Scopes) == 0 {
+		p.Scopes = policy.Scopes
+	}
+
+	if len(p.Claims) == 0 {
+		p.Claims = policy.Claims
+	}
+
+	if p.RolesClaim == "" {
+		p.RolesClaim = policy.RolesClaim
+	}
+
+	if p.GroupsClaim == "" {
+		p.GroupsClaim = policy.GroupsClaim
+	}
+}
+
+//Authorize verifies if request fulfills policy requirements
+func (p *Policy) Authorize(request *http.Request, route *Route) error {
+	authorization := request.Header.Get(HeaderAuthorization)
+	if authorization == "" {
+		return &AuthError{StatusCode: http.StatusUnauthorized, Message: "authorization was empty"}
+	}
+
+	claims, err := requestClaims(request, authorization)
+	if err != nil {
+		return &AuthError{StatusCode: http.StatusUnauthorized, Message: err.Error()}
+	}
+
+	return p.AuthorizeClaims(claims, request, route)
+}
+
+//AuthorizeClaims verifies if claims fulfill policy requirements
+func (p *Policy) AuthorizeClaims(claims map[string]interface{}, request *http.Request, route *Route) error {
+	if len(p.Roles) > 0 && !containsAny(claimValues(claims, p.RolesClaim), p.Roles) {
+		return &AuthError{StatusCode: http.StatusForbidden, Message: "required role is missing"}
+	}
+
+	if len(p.Groups) > 0 && !containsAny(claimValues(claims, p.GroupsClaim), p.Groups) {
+		return &AuthError{StatusCode: http.StatusForbidden, Message: "required group is missing"}
+	}
+
+	if len(p.Scopes) > 0 && !containsAll(claimValues(claims, scopeClaim), p.Scopes) {
+		return &AuthError{StatusCode: http.StatusForbidden, Message: "required scope is missing"}
+	}
+
+	if len(p._predicates) == 0 {
+		return nil
+	}
+
+	pathParams, _ := toolbox.ExtractURIParameters(route.URI, request.URL.Path)
+	for _, predicate := range p._predicates {
+		if !predicate.matches(claims, pathParams, request) {
+			return &AuthError{StatusCode: http.StatusForbidden, Message: fmt.Sprintf("claim predicate %v was not satisfied", predicate.expression)}
+		}
+	}
+
+	return nil
+}
+
+//Description returns human readable policy requirements
+func (p *Policy) Description() string {
+	var requirements []string
+	if len(p.Roles) > 0 {
+		requirements = append(requirements, "roles: any of "+strings.Join(p.Roles, ", "))
+	}
+
+	if len(p.Groups) > 0 {
+		requirements = append(requirements, "groups: any of "+strings.Join(p.Groups, ", "))
+	}
+
+	if len(p.Scopes) > 0 {
+		requirements = append(requirements, "scopes: all of "+strings.Join(p.Scopes, ", "))
+	}
+
+	if len(p.Claims) > 0 {
+		requirements = append(requirements, "claims: "+strings.Join(p.Claims, " && "))
+	}
+
+	if len(requirements) == 0 {
+		return "Requires authorization"
+	}
+
+	return "Requires authorization with " + strings.Join(requirements, "; ")
+}
+
+func requestClaims(request *http.Request, authorization string) (map[string]interface{}, error) {
+	jwtCodec, _ := registry.Codecs.Lookup(registry.CodecKeyJwtClaim)
+	if jwtCodec == nil {
+		return nil, fmt.Errorf("%v codec was not registered", registry.CodecKeyJwtClaim)
+	}
+
+	claim, err := jwtCodec.Valuer().Value(request.Context(), authorization)
+	if err != nil {
+		return nil, err
+	}
+
+	if claim == nil {
+		return nil, fmt.Errorf("invalid authorization token")
+	}
+
+	data, err := json.Marshal(claim)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := map[string]interface{}{}
+	return claims, json.Unmarshal(data, &claims)
+}
+
+func claimValue(claims map[string]interface{}, path string) (interface{}, bool) {
+	if value, ok := lookupPath(claims, path); ok {
+		return value, ok
+	}
+
+	if data, ok := claims[dataClaim].(map[string]interface{}); ok {
+		return lookupPath(data, path)
+	}
+
+	return nil, false
+}
+
+func lookupPath(values map[string]interface{}, path string) (interface{}, bool) {
+	segments := strings.Split(path, ".")
+	var current interface{} = values
+	for _, segment := range segments {
+		aMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if current, ok = aMap[segment]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+func claimValues(claims map[string]interface{}, path string) []string {
+	value, ok := claimValue(claims, path)
+	if !ok || value == nil {
+		return nil
+	}
+
+	switch actual := value.(type) {
+	case string:
+		return strings.FieldsFunc(actual, func(r rune) bool {
+			return r == ' ' || r == ','
+		})
+	case []interface{}:
+		result := make([]string, 0, len(actual))
+		for _, item := range actual {
+			result = append(result, toolbox.AsString(item))
+		}
+		return result
+	default:
+		return []string{toolbox.AsString(actual)}
+	}
+}
+
+func containsAny(actual []string, expected []string) bool {
+	for _, candidate := range expected {
+		if containsString(actual, candidate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsAll(actual []string, expected []string) bool {
+	for _, candidate := range expected {
+		if !containsString(actual, candidate) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newClaimPredicate(expression string) (*claimPredicate, error) {
+	operator := "=="
+	index := strings.Index(expression, operator)
+	if notEqual := strings.Index(expression, "!="); notEqual != -1 {
+		operator, index = "!=", notEqual
+	}
+
+	if index == -1 {
+		return nil, fmt.Errorf("unsupported claim predicate %v, expected == or != operator", expression)
+	}
+
+	left, err := newClaimOperand(expression[:index])
+	if err != nil {
+		return nil, fmt.Errorf("invalid claim predicate %v: %w", expression, err)
+	}
+
+	right, err := newClaimOperand(expression[index+len(operator):])
+	if err != nil {
+		return nil, fmt.Errorf("invalid claim predicate %v: %w", expression, err)
+	}
+
+	return &claimPredicate{expression: expression, left: left, right: right, negated: operator == "!="}, nil
+}
+
+func newClaimOperand(operand string) (*claimOperand, error) {
+	operand = strings.TrimSpace(operand)
+	if operand == "" {
+		return nil, fmt.Errorf("operand was empty")
+	}
+
+	if len(operand) >= 2 && (operand[0] == '\'' || operand[0] == '"') && operand[len(operand)-1] == operand[0] {
+		return &claimOperand{name: operand[1 : len(operand)-1]}, nil
+	}
+
+	if _, err := strconv.ParseFloat(operand, 64); err == nil {
+		return &claimOperand{name: operand}, nil
+	}
+
+	if index := strings.Index(operand, "."); index != -1 && index < len(operand)-1 {
+		source := operand[:index]
+		switch source {
+		case claimsSource, pathSource, querySource, headerSource:
+			return &claimOperand{source: source, name: operand[index+1:]}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported operand %v, expected %v., %v., %v., %v. prefixed name, quoted string or number", operand, claimsSource, pathSource, querySource, headerSource)
+}
+
+func (p *claimPredicate) matches(claims map[string]interface{}, pathParams map[string]string, request *http.Request) bool {
+	left, leftOk := p.left.value(claims, pathParams, request)
+	right, rightOk := p.right.value(claims, pathParams, request)
+	if !leftOk || !rightOk {
+		return false
+	}
+
+	return (left == right) != p.negated
+}
+
+func (o *claimOperand) value(claims map[string]interface{}, pathParams map[string]string, request *http.Request) (string, bool) {
+	switch o.source {
+	case claimsSource:
+		value, ok := claimValue(claims, o.name)
+		if !ok || value == nil {
+			return "", false
+		}
+		return toolbox.AsString(value), true
+	case pathSource:
+		value, ok := pathParams[o.name]
+		return value, ok
+	case querySource:
+		values, ok := request.URL.Query()[o.name]
+		if !ok || len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	case headerSource:
+		value := request.Header.Get(o.name)
+		return value, value != ""
+	}
+
+	return o.name, true
+}
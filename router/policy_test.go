@@ -0,0 +1,94 @@
+package router
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPolicy_AuthorizeClaims(t *testing.T) {
+	useCases := []struct {
+		description string
+		policy      *Policy
+		policies    Policies
+		claims      map[string]interface{}
+		URL         string
+		expect      int
+		expectErr   bool
+	}{
+		{
+			description: "role matched",
+			policy:      &Policy{Roles: []string{"admin", "editor"}},
+			claims:      map[string]interface{}{"dat": map[string]interface{}{"roles": []interface{}{"editor"}}},
+			expect:      http.StatusOK,
+		},
+		{
+			description: "role missing",
+			policy:      &Policy{Roles: []string{"admin"}},
+			claims:      map[string]interface{}{"roles": []interface{}{"viewer"}},
+			expect:      http.StatusForbidden,
+		},
+		{
+			description: "all scopes required",
+			policy:      &Policy{Scopes: []string{"read", "write"}},
+			claims:      map[string]interface{}{"scope": "read"},
+			expect:      http.StatusForbidden,
+		},
+		{
+			description: "claim predicate matched",
+			policy:      &Policy{Claims: []string{"claims.tenantId == path.tenantId"}},
+			claims:      map[string]interface{}{"dat": map[string]interface{}{"tenantId": float64(10)}},
+			URL:         "/v1/api/tenants/10/events",
+			expect:      http.StatusOK,
+		},
+		{
+			description: "claim predicate not matched",
+			policy:      &Policy{Claims: []string{"claims.tenantId == path.tenantId"}},
+			claims:      map[string]interface{}{"dat": map[string]interface{}{"tenantId": float64(10)}},
+			URL:         "/v1/api/tenants/20/events",
+			expect:      http.StatusForbidden,
+		},
+		{
+			description: "shared policy",
+			policy:      &Policy{Ref: "tenant"},
+			policies:    Policies{{Name: "tenant", Claims: []string{"claims.account_id != '0'"}, Groups: []string{"dev"}}},
+			claims:      map[string]interface{}{"account_id": float64(3), "groups": "dev,ops"},
+			expect:      http.StatusOK,
+		},
+		{
+			description: "numeric literal operand",
+			policy:      &Policy{Claims: []string{"claims.level == 3"}},
+			claims:      map[string]interface{}{"level": float64(3)},
+			expect:      http.StatusOK,
+		},
+		{
+			description: "operand without source",
+			policy:      &Policy{Claims: []string{"claims.tenantId == tenantId"}},
+			expectErr:   true,
+		},
+	}
+
+	route := &Route{Method: http.MethodGet, URI: "/v1/api/tenants/{tenantId}/events"}
+	for _, useCase := range useCases {
+		err := useCase.policy.Init(useCase.policies)
+		if useCase.expectErr {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		assert.Nil(t, err, useCase.description)
+
+		URL := useCase.URL
+		if URL == "" {
+			URL = "/v1/api/tenants/1/events"
+		}
+
+		err = useCase.policy.AuthorizeClaims(useCase.claims, httptest.NewRequest(http.MethodGet, URL, nil), route)
+		actual := http.StatusOK
+		if err != nil {
+			actual = err.(*AuthError).StatusCode
+		}
+
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
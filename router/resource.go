@@ -35,6 +35,7 @@ type (
 		Cache        *cache.Cache
 		Logger       *Logger //connect, dataview, time, SQL with params if exceeded time
 		Cors         *Cors
+		Policies     Policies //shared authorization policies, referenced by route Auth.Ref
 
 		ColumnsCache     *discover.Cache
 		RevealMetric     *bool
@@ -50,6 +51,7 @@ type (
 
 	Logger struct {
 		MinExecutionMs *int
+		SlowQuery      *view.SlowQuery
 	}
 
 	Compression struct {
@@ -172,7 +174,17 @@ func (r *Resource) addLoggersIfNeeded() error {
 		return nil
 	}
 
+	if r.Logger.SlowQuery != nil {
+		for _, aRoute := range r.Routes {
+			r.addSlowQuery(aRoute.View, r.Logger.SlowQuery)
+		}
+	}
+
 	if r.Logger.MinExecutionMs == nil {
+		if r.Logger.SlowQuery != nil {
+			return nil
+		}
+
 		return fmt.Errorf("unspecified logger MinExecutionMs")
 	}
 
@@ -196,6 +208,16 @@ func (r *Resource) addLogger(aView *view.View, timeLogger *logger.Adapter) {
 	}
 }
 
+func (r *Resource) addSlowQuery(aView *view.View, slowQuery *view.SlowQuery) {
+	if aView.SlowQuery == nil {
+		aView.SlowQuery = slowQuery
+	}
+
+	for _, relation := range aView.With {
+		r.addSlowQuery(&relation.Of.View, slowQuery)
+	}
+}
+
 func NewResourceFromURL(ctx context.Context, fs afs.Service, URL string, useColumnCache bool, options ...interface{}) (*Resource, error) {
 	resource, err := LoadResource(ctx, fs, URL, useColumnCache, options...)
 	if err != nil {
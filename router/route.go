@@ -33,7 +33,8 @@ const (
 	JSONFormat     = "application/json"
 	FormatQuery    = "_format"
 
-	HeaderContentType = "Content-Type"
+	HeaderContentType   = "Content-Type"
+	HeaderAuthorization = "Authorization"
 )
 
 type (
@@ -43,6 +44,11 @@ type (
 		URI         string
 		APIKey      *APIKey
 		Method      string
+		Version     string     `json:",omitempty"`
+		Weight      int        `json:",omitempty"`
+		Shadow      *Shadow    `json:",omitempty"`
+		Auth        *Policy    `json:",omitempty"`
+		Signature   *Signature `json:",omitempty"`
 		Service     ServiceType
 		View        *view.View
 		Cors        *Cors
@@ -53,6 +59,7 @@ type (
 
 		ParamStatusError *int
 		Cache            *cache.Cache
+		Invalidates      []string `json:",omitempty" yaml:",omitempty"` //cache tags purged once executor route succeeded, i.e. table:EVENTS or view:events
 		Compression      *Compression
 
 		_resource *view.Resource
@@ -62,6 +69,7 @@ type (
 		_requestBodyType          reflect.Type
 		_requestBodySlice         *xunsafe.Slice
 		_inputMarshaller          *json.Marshaller
+		_masks                    *viewMasks
 	}
 
 	Output struct {
@@ -138,6 +146,23 @@ func (r *Route) Init(ctx context.Context, resource *Resource) error {
 	if err := r.initCardinality(); err != nil {
 		return err
 	}
+
+	if r.Weight < 0 || r.Weight > 100 {
+		return fmt.Errorf("route %v %v weight has to be between 0 and 100 but was %v", r.Method, r.URI, r.Weight)
+	}
+
+	if err := r.initShadow(); err != nil {
+		return err
+	}
+
+	if err := r.initAuth(resource); err != nil {
+		return err
+	}
+
+	if err := r.initSignature(ctx); err != nil {
+		return err
+	}
+
 	r.View.Standalone = true
 	if r.View.Name == "" {
 		r.View.Name = r.View.Ref
@@ -198,7 +223,7 @@ func (r *Route) Init(ctx context.Context, resource *Resource) error {
 	}
 
 	r.initDebugStyleIfNeeded()
-	return nil
+	return r.initMasks()
 }
 
 func (r *Route) initView(ctx context.Context, resource *Resource) error {
@@ -504,6 +529,10 @@ func (r *Route) initCache(ctx context.Context) error {
 		return nil
 	}
 
+	if err := r.initCacheScope(); err != nil {
+		return err
+	}
+
 	return r.Cache.Init(ctx)
 }
 
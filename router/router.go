@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 	"unsafe"
 )
 
@@ -72,6 +73,8 @@ type (
 		Request       *http.Request
 		Response      http.ResponseWriter
 		Selectors     *view.Selectors
+
+		masks *maskSession
 	}
 )
 
@@ -155,6 +158,28 @@ func (r *Router) HandleRoute(response http.ResponseWriter, request *http.Request
 		return nil
 	}
 
+	if route.Auth != nil {
+		if err := route.Auth.Authorize(request, route); err != nil {
+			statusCode := http.StatusUnauthorized
+			if authErr, ok := err.(*AuthError); ok {
+				statusCode = authErr.StatusCode
+			}
+			r.writeErr(response, route, err, statusCode)
+			return nil
+		}
+	}
+
+	if route.Signature != nil {
+		if err := route.Signature.Verify(request, time.Now()); err != nil {
+			statusCode := http.StatusBadRequest
+			if authErr, ok := err.(*AuthError); ok {
+				statusCode = authErr.StatusCode
+			}
+			r.writeErr(response, route, err, statusCode)
+			return nil
+		}
+	}
+
 	switch route.Service {
 	case ReaderServiceType:
 		r.viewHandler(route)(response, request)
@@ -315,12 +340,18 @@ func (r *Router) buildSession(ctx context.Context, response http.ResponseWriter,
 		return nil, http.StatusBadRequest, err
 	}
 
+	masks := route.newMaskSession(request)
+	if err = masks.validate(selectors); err != nil {
+		return nil, http.StatusForbidden, err
+	}
+
 	return &ReaderSession{
 		RequestParams: requestParams,
 		Route:         route,
 		Request:       request,
 		Response:      response,
 		Selectors:     selectors,
+		masks:         masks,
 	}, http.StatusOK, nil
 }
 
@@ -346,6 +377,8 @@ func (r *Router) readAndWriteResponse(ctx context.Context, session *ReaderSessio
 		return -1, nil
 	}
 
+	session.masks.apply(rValue)
+
 	resultMarshalled, statusCode, err := r.marshalResult(session, rValue, viewMeta, readerStats)
 	if err != nil {
 		return statusCode, err
@@ -417,7 +450,7 @@ func (r *Router) updateCache(ctx context.Context, route *Route, cacheEntry *cach
 }
 
 func (r *Router) cacheEntry(ctx context.Context, session *ReaderSession) (*cache.Entry, error) {
-	if session.Route.Cache == nil {
+	if session.Route.Cache == nil || (session.Route._masks != nil && session.Route.Cache.Scope != view.CacheScopeUser) {
 		return nil, nil
 	}
 
@@ -471,6 +504,8 @@ func (r *Router) marshalResult(session *ReaderSession, destValue reflect.Value,
 		return nil, http.StatusBadRequest, err
 	}
 
+	filters = session.masks.filters(session.Route, filters)
+
 	formatType := session.RequestParams.queryParam(FormatQuery, "")
 	switch strings.ToLower(formatType) {
 	case CSVQueryFormat:
@@ -619,12 +654,36 @@ func (r *Router) createCacheEntry(ctx context.Context, session *ReaderSession) (
 	session.Selectors.RWMutex.RLock()
 	defer session.Selectors.RWMutex.RUnlock()
 
-	selectorSlice := make([]*view.Selector, len(session.Selectors.Index))
-	for viewName := range session.Selectors.Index {
+	principal, ok := session.Route.cachePrincipal(session.Request)
+	if !ok {
+		return nil, nil
+	}
+
+	tags, err := session.Route.cacheTags(session.Selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &cacheKey{
+		Selectors: make([]*view.Selector, len(session.Selectors.Index)),
+		Params:    make([]map[string]interface{}, len(session.Selectors.Index)),
+		Principal: principal,
+		Tags:      view.CacheTags.Generation(tags),
+	}
+
+	for viewName, selector := range session.Selectors.Index {
 		index, _ := session.Route.viewIndex(viewName)
-		selectorSlice[index] = session.Selectors.Index[viewName]
+		key.Selectors[index] = selector
+		if details, ok := session.Route.Index.viewByName(viewName); ok {
+			params, err := details.View.CacheKeyValues(selector)
+			if err != nil {
+				return nil, err
+			}
+			key.Params[index] = params
+		}
 	}
-	marshalled, err := goJson.Marshal(selectorSlice)
+
+	marshalled, err := goJson.Marshal(key)
 	if err != nil {
 		return nil, err
 	}
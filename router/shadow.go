@@ -0,0 +1,36 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+)
+
+//Shadow represents route mirroring sampled live GET traffic, shadow route response is only compared with the primary one
+type Shadow struct {
+	SamplePercent int      `json:",omitempty"` //percentage of the primary route traffic mirrored to the shadow route
+	Ignore        []string `json:",omitempty"` //response paths ignored by comparison, i.e. Data.updated
+}
+
+func (r *Route) initShadow() error {
+	if r.Shadow == nil {
+		return nil
+	}
+
+	if r.Method != http.MethodGet {
+		return fmt.Errorf("route %v %v shadow is supported only with %v method", r.Method, r.URI, http.MethodGet)
+	}
+
+	if r.Version == "" {
+		return fmt.Errorf("route %v %v shadow version was empty", r.Method, r.URI)
+	}
+
+	if r.Weight != 0 {
+		return fmt.Errorf("route %v %v shadow can't define weight", r.Method, r.URI)
+	}
+
+	if r.Shadow.SamplePercent < 0 || r.Shadow.SamplePercent > 100 {
+		return fmt.Errorf("route %v %v shadow sample percent has to be between 0 and 100 but was %v", r.Method, r.URI, r.Shadow.SamplePercent)
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package router
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/viant/scy"
+	"hash"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSignatureHeader          = "X-Signature"
+	defaultSignatureTimestampHeader = "X-Signature-Timestamp"
+	defaultSignatureToleranceSec    = 300
+	defaultSignatureMaxBodyBytes    = 1024 * 1024
+
+	SignatureEncodingHex    = "hex"
+	SignatureEncodingBase64 = "base64"
+)
+
+type (
+	//Signature represents HMAC request signature verification, signature is computed over timestamp + "." + raw body
+	Signature struct {
+		Secret          *scy.Resource
+		Algorithm       string `json:",omitempty"` //sha256 (default), sha1 or sha512
+		Header          string `json:",omitempty"` //X-Signature by default
+		TimestampHeader string `json:",omitempty"` //X-Signature-Timestamp by default, unix seconds
+		Prefix          string `json:",omitempty"` //signature value prefix i.e. sha256=
+		Encoding        string `json:",omitempty"` //hex (default) or base64
+		ToleranceSec    int    `json:",omitempty"`
+		MaxBodyBytes    int    `json:",omitempty"` //max signed body size, 1MB by default
+
+		_key     []byte
+		_hash    func() hash.Hash
+		_replays *replays
+	}
+
+	//replays represents signatures seen within the tolerance window
+	replays struct {
+		sync.Mutex
+		seen   map[string]bool
+		expiry replayQueue
+	}
+
+	//replayQueue represents signatures ordered by expiry time
+	replayQueue []*replay
+
+	replay struct {
+		signature string
+		expiry    time.Time
+	}
+)
+
+func (r *Route) initSignature(ctx context.Context) error {
+	if r.Signature == nil {
+		return nil
+	}
+
+	if err := r.Signature.Init(ctx); err != nil {
+		return fmt.Errorf("invalid route %v %v signature: %w", r.Method, r.URI, err)
+	}
+
+	return nil
+}
+
+//Init loads signature secret and initializes defaults
+func (s *Signature) Init(ctx context.Context) error {
+	if s.Secret == nil {
+		return fmt.Errorf("signature secret was empty")
+	}
+
+	switch strings.ToLower(s.Algorithm) {
+	case "", "sha256":
+		s._hash = sha256.New
+	case "sha1":
+		s._hash = sha1.New
+	case "sha512":
+		s._hash = sha512.New
+	default:
+		return fmt.Errorf("unsupported signature algorithm %v", s.Algorithm)
+	}
+
+	switch strings.ToLower(s.Encoding) {
+	case "":
+		s.Encoding = SignatureEncodingHex
+	case SignatureEncodingHex, SignatureEncodingBase64:
+		s.Encoding = strings.ToLower(s.Encoding)
+	default:
+		return fmt.Errorf("unsupported signature encoding %v", s.Encoding)
+	}
+
+	if s.Header == "" {
+		s.Header = defaultSignatureHeader
+	}
+
+	if s.TimestampHeader == "" {
+		s.TimestampHeader = defaultSignatureTimestampHeader
+	}
+
+	if s.ToleranceSec == 0 {
+		s.ToleranceSec = defaultSignatureToleranceSec
+	}
+
+	if s.MaxBodyBytes == 0 {
+		s.MaxBodyBytes = defaultSignatureMaxBodyBytes
+	}
+
+	if s._key == nil {
+		secret, err := scy.New().Load(ctx, s.Secret)
+		if err != nil {
+			return err
+		}
+
+		s._key = []byte(strings.TrimSpace(secret.String()))
+	}
+
+	if len(s._key) == 0 {
+		return fmt.Errorf("signature secret was empty")
+	}
+
+	s._replays = &replays{seen: map[string]bool{}}
+	return nil
+}
+
+//Verify verifies request signature, request body is restored for the further processing
+func (s *Signature) Verify(request *http.Request, now time.Time) error {
+	signature := strings.TrimPrefix(request.Header.Get(s.Header), s.Prefix)
+	if signature == "" {
+		return &AuthError{StatusCode: http.StatusUnauthorized, Message: "missing request signature"}
+	}
+
+	timestamp := request.Header.Get(s.TimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return &AuthError{StatusCode: http.StatusUnauthorized, Message: "invalid request signature timestamp"}
+	}
+
+	signedAt := time.Unix(unix, 0)
+	if math.Abs(now.Sub(signedAt).Seconds()) > float64(s.ToleranceSec) {
+		return &AuthError{StatusCode: http.StatusUnauthorized, Message: "request signature timestamp outside of tolerance window"}
+	}
+
+	var body []byte
+	if request.Body != nil {
+		original := request.Body
+		if body, err = io.ReadAll(io.LimitReader(original, int64(s.MaxBodyBytes)+1)); err != nil {
+			return err
+		}
+
+		if len(body) > s.MaxBodyBytes {
+			request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), original), Closer: original}
+			return &AuthError{StatusCode: http.StatusRequestEntityTooLarge, Message: "signed request body too large"}
+		}
+		_ = original.Close()
+	}
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	expected := s.sign(timestamp, body)
+	actual, err := s.decode(signature)
+	if err != nil || !hmac.Equal(expected, actual) {
+		return &AuthError{StatusCode: http.StatusUnauthorized, Message: "invalid request signature"}
+	}
+
+	if !s._replays.add(string(expected), signedAt.Add(time.Duration(s.ToleranceSec)*time.Second), now) {
+		return &AuthError{StatusCode: http.StatusUnauthorized, Message: "request signature was already used"}
+	}
+
+	return nil
+}
+
+//Sign returns encoded request signature
+func (s *Signature) Sign(timestamp string, body []byte) string {
+	signature := s.sign(timestamp, body)
+	if s.Encoding == SignatureEncodingBase64 {
+		return s.Prefix + base64.StdEncoding.EncodeToString(signature)
+	}
+
+	return s.Prefix + hex.EncodeToString(signature)
+}
+
+func (s *Signature) sign(timestamp string, body []byte) []byte {
+	mac := hmac.New(s._hash, s._key)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+func (s *Signature) decode(signature string) ([]byte, error) {
+	if s.Encoding == SignatureEncodingBase64 {
+		return base64.StdEncoding.DecodeString(signature)
+	}
+
+	return hex.DecodeString(strings.ToLower(signature))
+}
+
+//add registers signature, returns false if signature was already seen
+func (r *replays) add(signature string, expiry time.Time, now time.Time) bool {
+	r.Lock()
+	defer r.Unlock()
+	for len(r.expiry) > 0 && now.After(r.expiry[0].expiry) {
+		expired := heap.Pop(&r.expiry).(*replay)
+		delete(r.seen, expired.signature)
+	}
+
+	if r.seen[signature] {
+		return false
+	}
+
+	r.seen[signature] = true
+	heap.Push(&r.expiry, &replay{signature: signature, expiry: expiry})
+	return true
+}
+
+func (q replayQueue) Len() int {
+	return len(q)
+}
+
+func (q replayQueue) Less(i, j int) bool {
+	return q[i].expiry.Before(q[j].expiry)
+}
+
+func (q replayQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *replayQueue) Push(x interface{}) {
+	*q = append(*q, x.(*replay))
+}
+
+func (q *replayQueue) Pop() interface{} {
+	old := *q
+	last := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return last
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
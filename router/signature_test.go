@@ -0,0 +1,77 @@
+package router
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/scy"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignature_Verify(t *testing.T) {
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	body := `{"ID":1,"Status":"paid"}`
+
+	signature := &Signature{Secret: &scy.Resource{}, Prefix: "sha256=", ToleranceSec: 60, MaxBodyBytes: 64, _key: []byte("partner-secret")}
+	if !assert.Nil(t, signature.Init(context.Background())) {
+		return
+	}
+
+	valid := signature.Sign(timestamp, []byte(body))
+	useCases := []struct {
+		description string
+		signature   string
+		timestamp   string
+		body        string
+		expectErr   bool
+	}{
+		{description: "valid signature", signature: valid, timestamp: timestamp, body: body},
+		{description: "replayed signature", signature: valid, timestamp: timestamp, body: body, expectErr: true},
+		{description: "tampered body", signature: signature.Sign(timestamp, []byte(body)), timestamp: timestamp, body: `{"ID":1,"Status":"refunded"}`, expectErr: true},
+		{description: "stale timestamp", signature: signature.Sign("1000", []byte(body)), timestamp: "1000", body: body, expectErr: true},
+		{description: "missing signature", timestamp: timestamp, body: body, expectErr: true},
+		{description: "body too large", signature: signature.Sign(timestamp, []byte(strings.Repeat("x", 65))), timestamp: timestamp, body: strings.Repeat("x", 65), expectErr: true},
+	}
+
+	for _, useCase := range useCases {
+		request, _ := http.NewRequest(http.MethodPost, "http://localhost/v1/api/webhook", strings.NewReader(useCase.body))
+		request.Header.Set(signature.Header, useCase.signature)
+		request.Header.Set(signature.TimestampHeader, useCase.timestamp)
+		err := signature.Verify(request, now)
+		assert.Equal(t, useCase.expectErr, err != nil, useCase.description)
+
+		restored, _ := io.ReadAll(request.Body)
+		assert.Equal(t, useCase.body, string(restored), useCase.description)
+	}
+}
+
+func TestReplays_Add(t *testing.T) {
+	now := time.Now()
+	aReplays := &replays{seen: map[string]bool{}}
+	useCases := []struct {
+		description string
+		signature   string
+		expiry      time.Time
+		now         time.Time
+		expect      bool
+		expectSeen  int
+	}{
+		{description: "new signature", signature: "s1", expiry: now.Add(2 * time.Second), now: now, expect: true, expectSeen: 1},
+		{description: "signature with earlier expiry", signature: "s2", expiry: now.Add(time.Second), now: now, expect: true, expectSeen: 2},
+		{description: "replayed signature", signature: "s1", expiry: now.Add(2 * time.Second), now: now, expect: false, expectSeen: 2},
+		{description: "earliest signature expired", signature: "s3", expiry: now.Add(3 * time.Second), now: now.Add(1500 * time.Millisecond), expect: true, expectSeen: 2},
+		{description: "expired signature can be reused", signature: "s2", expiry: now.Add(4 * time.Second), now: now.Add(1500 * time.Millisecond), expect: true, expectSeen: 3},
+		{description: "all but latest expired", signature: "s1", expiry: now.Add(5 * time.Second), now: now.Add(3500 * time.Millisecond), expect: true, expectSeen: 2},
+	}
+
+	for _, useCase := range useCases {
+		assert.Equal(t, useCase.expect, aReplays.add(useCase.signature, useCase.expiry, useCase.now), useCase.description)
+		assert.Equal(t, useCase.expectSeen, len(aReplays.seen), useCase.description)
+		assert.Equal(t, useCase.expectSeen, len(aReplays.expiry), useCase.description)
+	}
+}
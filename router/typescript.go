@@ -0,0 +1,503 @@
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/viant/datly/router/marshal/json"
+	"github.com/viant/datly/view"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const typeScriptClientSource = `// Code generated by datly. DO NOT EDIT.
+
+export interface Selector {
+  fields?: string[];
+  criteria?: string;
+  orderBy?: string;
+  limit?: number;
+  offset?: number;
+  page?: number;
+}
+
+export interface ClientOptions {
+  headers?: Record<string, string>;
+  fetch?: typeof fetch;
+}
+
+export class ResponseError extends Error {
+  constructor(readonly statusCode: number, readonly status: string, readonly body: any) {
+    super("status " + statusCode + ": " + (typeof body === "string" ? body : JSON.stringify(body)));
+  }
+}
+
+type SelectorNames = Partial<Record<keyof Selector, string>>;
+
+interface Envelope {
+  status: string;
+  message: string;
+}
+
+function formatValue(value: any): string {
+  if (value instanceof Date) {
+    return value.toISOString();
+  }
+  if (Array.isArray(value)) {
+    return value.map(formatValue).join(",");
+  }
+  if (typeof value === "object" && value !== null) {
+    return JSON.stringify(value);
+  }
+  return String(value);
+}
+
+function encodeSelector(query: URLSearchParams, selector: Selector | undefined, names: SelectorNames): void {
+  if (!selector) {
+    return;
+  }
+  for (const option of Object.keys(selector) as (keyof Selector)[]) {
+    const value = selector[option];
+    if (value === undefined || value === null || value === "" || value === 0 || (Array.isArray(value) && value.length === 0)) {
+      continue;
+    }
+    const name = names[option];
+    if (!name) {
+      throw new Error("view does not support " + option + " selector");
+    }
+    query.set(name, formatValue(value));
+  }
+}
+
+export class Client {
+  private readonly baseURL: string;
+
+  constructor(baseURL: string, private readonly options: ClientOptions = {}) {
+    this.baseURL = baseURL.replace(/\/+$/, "");
+  }
+
+  private async do(method: string, URI: string, query: URLSearchParams, headers: Record<string, string>, body?: any, envelope?: Envelope): Promise<any> {
+    let requestURL = this.baseURL + URI;
+    const queryString = query.toString();
+    if (queryString) {
+      requestURL += "?" + queryString;
+    }
+    const requestHeaders: Record<string, string> = { ...this.options.headers, ...headers };
+    const init: RequestInit = { method, headers: requestHeaders };
+    if (body !== undefined) {
+      init.body = JSON.stringify(body);
+      requestHeaders["Content-Type"] = "application/json";
+    }
+    const response = await (this.options.fetch ?? fetch)(requestURL, init);
+    const text = await response.text();
+    let data: any = undefined;
+    if (text) {
+      try {
+        data = JSON.parse(text);
+      } catch {
+        data = text;
+      }
+    }
+    if (envelope && data && data[envelope.status] === "error") {
+      throw new ResponseError(response.status, "error", data[envelope.message]);
+    }
+    if (!response.ok) {
+      throw new ResponseError(response.status, "", data);
+    }
+    return data;
+  }
+`
+
+var typeScriptIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+type (
+	typeScriptClient struct {
+		typeNames   map[string]bool
+		methodNames map[string]bool
+		types       map[goClientTypeKey]string
+		decls       *bytes.Buffer
+		methods     *bytes.Buffer
+	}
+
+	typeScriptParam struct {
+		field  string
+		tsType string
+		*clientParam
+	}
+)
+
+//GenerateTypeScriptClient generates TypeScript module with interfaces for route parameters, request and response bodies, and fetch based client method for each route
+func GenerateTypeScriptClient(routes ...*Route) ([]byte, error) {
+	client := &typeScriptClient{
+		typeNames:   map[string]bool{"Client": true, "ClientOptions": true, "ResponseError": true, "Selector": true, "SelectorNames": true, "Envelope": true},
+		methodNames: map[string]bool{"do": true, "constructor": true},
+		types:       map[goClientTypeKey]string{},
+		decls:       &bytes.Buffer{},
+		methods:     &bytes.Buffer{},
+	}
+
+	for _, route := range routes {
+		if err := client.addRoute(route); err != nil {
+			return nil, fmt.Errorf("failed to generate %v %v TypeScript client: %w", route.Method, route.URI, err)
+		}
+	}
+
+	source := &bytes.Buffer{}
+	source.WriteString(typeScriptClientSource)
+	source.Write(client.methods.Bytes())
+	source.WriteString("}\n")
+	source.Write(client.decls.Bytes())
+	return source.Bytes(), nil
+}
+
+func (c *typeScriptClient) addRoute(route *Route) error {
+	name := goClientName(route.View.Name)
+	if c.methodNames[lowerFirst(name)] {
+		name = goClientName(strings.ToLower(route.Method)) + name
+	}
+
+	method := uniqueName(c.methodNames, lowerFirst(name))
+	requestType := uniqueName(c.typeNames, name+"Request")
+
+	fields := map[string]bool{}
+	var params []*clientParam
+	clientParams(route.View, &params, map[string]bool{})
+	tsParams := make([]*typeScriptParam, 0, len(params))
+	optionalRequest := true
+	for _, param := range params {
+		field := uniqueName(fields, lowerFirst(goClientName(param.param.Name)))
+		tsType, err := c.paramType(route, param.rType, requestType+goClientName(param.param.Name))
+		if err != nil {
+			return err
+		}
+
+		optionalRequest = optionalRequest && !param.required
+		tsParams = append(tsParams, &typeScriptParam{field: field, tsType: tsType, clientParam: param})
+	}
+
+	var selectors []*clientSelector
+	clientSelectors(route.View, true, &selectors)
+	selectorFields := make([]string, len(selectors))
+	for i, selector := range selectors {
+		field := "selector"
+		if !selector.mainView {
+			field = lowerFirst(goClientName(selector.view.Name)) + "Selector"
+		}
+		selectorFields[i] = uniqueName(fields, field)
+	}
+
+	var bodyField, bodyType string
+	if route.Method != http.MethodGet && route._requestBodyType != nil {
+		var err error
+		if bodyType, err = c.typeExpr(route, route._requestBodyType, name+"Body", "", false); err != nil {
+			return err
+		}
+
+		bodyField = uniqueName(fields, "body")
+		optionalRequest = optionalRequest && !route._requestBodyParamRequired
+	}
+
+	decl := c.decls
+	decl.WriteString(fmt.Sprintf("\n/** %v represents %v %v request */\nexport interface %v {\n", requestType, route.Method, route.URI, requestType))
+	for _, param := range tsParams {
+		decl.WriteString(fmt.Sprintf("  %v%v: %v;\n", param.field, optionalMark(!param.required), param.tsType))
+	}
+
+	for _, field := range selectorFields {
+		decl.WriteString(fmt.Sprintf("  %v?: Selector;\n", field))
+	}
+
+	if bodyField != "" {
+		decl.WriteString(fmt.Sprintf("  %v%v: %v;\n", bodyField, optionalMark(!route._requestBodyParamRequired), bodyType))
+	}
+	decl.WriteString("}\n")
+
+	return c.addMethod(route, name, method, requestType, optionalRequest, tsParams, selectors, selectorFields, bodyField)
+}
+
+func (c *typeScriptClient) addMethod(route *Route, name, method, requestType string, optionalRequest bool, params []*typeScriptParam, selectors []*clientSelector, selectorFields []string, bodyField string) error {
+	hasResult := route.Service != ExecutorServiceType || route.ResponseBody != nil
+	resultType := "void"
+	var envelope, resultField string
+	var err error
+	switch {
+	case !hasResult:
+	case route._responseSetter != nil:
+		responseType := route.responseType()
+		var envelopeType string
+		if envelopeType, err = c.typeExpr(route, responseType, name+"Response", "", true); err != nil {
+			return err
+		}
+
+		field, ok := responseType.FieldByName(route.ResponseField)
+		if !ok {
+			return fmt.Errorf("failed to lookup response field %v", route.ResponseField)
+		}
+
+		if resultType, err = c.typeExpr(route, field.Type, envelopeType+goClientFieldName(field.Name), field.Name, true); err != nil {
+			return err
+		}
+
+		resultField = typeScriptAccessor(typeScriptFieldName(route, field))
+		statusField, _ := reflect.TypeOf(ResponseStatus{}).FieldByName("Status")
+		messageField, _ := reflect.TypeOf(ResponseStatus{}).FieldByName("Message")
+		envelope = fmt.Sprintf("{ status: %v, message: %v }", strconv.Quote(typeScriptFieldName(route, statusField)), strconv.Quote(typeScriptFieldName(route, messageField)))
+	case route.Service == ExecutorServiceType:
+		if resultType, err = c.typeExpr(route, route.ResponseBody._bodyType, name+"Response", "", true); err != nil {
+			return err
+		}
+	default:
+		rType := route.View.Schema.Type()
+		if route.Cardinality == view.Many {
+			rType = reflect.SliceOf(rType)
+		}
+
+		if resultType, err = c.typeExpr(route, rType, goClientName(route.View.Name), "", true); err != nil {
+			return err
+		}
+	}
+
+	requestArg := "request: " + requestType
+	if optionalRequest {
+		requestArg += " = {}"
+	}
+
+	sb := c.methods
+	sb.WriteString(fmt.Sprintf("\n  /** %v calls %v %v */\n  async %v(%v): Promise<%v> {\n", method, route.Method, route.URI, method, requestArg, resultType))
+	sb.WriteString(fmt.Sprintf("    let URI = %v;\n    const query = new URLSearchParams();\n    const headers: Record<string, string> = {};\n", strconv.Quote(route.URI)))
+	hasCookies := false
+	for _, param := range params {
+		hasCookies = hasCookies || param.param.In.Kind == view.CookieKind
+	}
+
+	if hasCookies {
+		sb.WriteString("    const cookies: string[] = [];\n")
+	}
+
+	for _, param := range params {
+		c.appendParamPlacement(sb, param)
+	}
+
+	if hasCookies {
+		sb.WriteString("    if (cookies.length > 0) {\n      headers[\"Cookie\"] = cookies.join(\"; \");\n    }\n")
+	}
+
+	for i, selector := range selectors {
+		names := make([]string, 0, len(selector.options))
+		for _, option := range selector.options {
+			names = append(names, option.option+": "+strconv.Quote(option.name))
+		}
+		sb.WriteString(fmt.Sprintf("    encodeSelector(query, request.%v, { %v });\n", selectorFields[i], strings.Join(names, ", ")))
+	}
+
+	body := "undefined"
+	if bodyField != "" {
+		body = "request." + bodyField
+	}
+
+	call := fmt.Sprintf("this.do(%v, URI, query, headers, %v", strconv.Quote(route.Method), body)
+	switch {
+	case !hasResult:
+		sb.WriteString("    await " + call + ");\n")
+	case envelope != "":
+		sb.WriteString(fmt.Sprintf("    const response = await %v, %v);\n    return response%v;\n", call, envelope, resultField))
+	default:
+		sb.WriteString("    return " + call + ");\n")
+	}
+
+	sb.WriteString("  }\n")
+	return nil
+}
+
+func (c *typeScriptClient) appendParamPlacement(sb *bytes.Buffer, param *typeScriptParam) {
+	value := "request." + param.field
+	var placement string
+	switch param.param.In.Kind {
+	case view.PathKind:
+		sb.WriteString(fmt.Sprintf("    URI = URI.replace(%v, encodeURIComponent(formatValue(%v)));\n", strconv.Quote("{"+param.param.In.Name+"}"), value))
+		return
+	case view.HeaderKind:
+		placement = fmt.Sprintf("headers[%v] = formatValue(%v);", strconv.Quote(param.param.In.Name), value)
+	case view.CookieKind:
+		placement = fmt.Sprintf("cookies.push(%v + encodeURIComponent(formatValue(%v)));", strconv.Quote(param.param.In.Name+"="), value)
+	default:
+		placement = fmt.Sprintf("query.set(%v, formatValue(%v));", strconv.Quote(param.param.In.Name), value)
+	}
+
+	if param.required {
+		sb.WriteString("    " + placement + "\n")
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("    if (%v !== undefined && %v !== null) {\n      %v\n    }\n", value, value, placement))
+}
+
+func (c *typeScriptClient) paramType(route *Route, rType reflect.Type, hint string) (string, error) {
+	for rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+
+	switch {
+	case rType == timeType:
+		return "Date | string", nil
+	case rType.Kind() == reflect.Slice && (rType.Elem() == timeType || rType.Elem() == reflect.PtrTo(timeType)):
+		return "(Date | string)[]", nil
+	}
+
+	return c.typeExpr(route, rType, hint, "", false)
+}
+
+func (c *typeScriptClient) typeExpr(route *Route, rType reflect.Type, hint string, path string, output bool) (string, error) {
+	switch rType.Kind() {
+	case reflect.Ptr:
+		return c.typeExpr(route, rType.Elem(), hint, path, output)
+	case reflect.Slice, reflect.Array:
+		if rType.Elem().Kind() == reflect.Uint8 {
+			return "string", nil
+		}
+		elemType, err := c.typeExpr(route, rType.Elem(), hint, path, output)
+		if strings.Contains(elemType, " ") {
+			elemType = "(" + elemType + ")"
+		}
+		return elemType + "[]", err
+	case reflect.Map:
+		elemType, err := c.typeExpr(route, rType.Elem(), hint, path, output)
+		return "Record<string, " + elemType + ">", err
+	case reflect.Interface:
+		return "any", nil
+	case reflect.Struct:
+		if rType == timeType {
+			return "string", nil
+		}
+		return c.interfaceType(route, rType, hint, path, output)
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "number", nil
+	}
+
+	return "", fmt.Errorf("unsupported TypeScript client type %v", rType.String())
+}
+
+func (c *typeScriptClient) interfaceType(route *Route, rType reflect.Type, hint string, path string, output bool) (string, error) {
+	key := goClientTypeKey{rType: rType, caser: *route._caser, output: output}
+	if output && len(route.Exclude) > 0 {
+		key.exclude = strings.Join(route.Exclude, ",")
+		key.path = path
+	}
+
+	if name, ok := c.types[key]; ok {
+		return name, nil
+	}
+
+	name := view.FirstNotEmpty(rType.Name(), hint)
+	if route._resource != nil {
+		if typeName, ok := route._resource.TypeName(rType); ok {
+			name = typeName
+		}
+	}
+
+	name = uniqueName(c.typeNames, goClientName(name))
+	c.types[key] = name
+
+	var extends []string
+	body := &bytes.Buffer{}
+	for i := 0; i < rType.NumField(); i++ {
+		field := rType.Field(i)
+		if field.Anonymous {
+			embedded, err := c.typeExpr(route, field.Type, name+field.Name, path, output)
+			if err != nil {
+				return "", err
+			}
+
+			extends = append(extends, embedded)
+			continue
+		}
+
+		tag := json.Parse(field.Tag.Get(json.TagName))
+		if tag.FieldName == "-" || (field.PkgPath != "" && tag.FieldName == "") {
+			continue
+		}
+
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		if output && route._excluded[fieldPath] {
+			continue
+		}
+
+		fieldType, err := c.typeExpr(route, field.Type, name+goClientFieldName(field.Name), fieldPath, output)
+		if err != nil {
+			return "", err
+		}
+
+		if field.Type.Kind() == reflect.Ptr {
+			fieldType += " | null"
+		}
+
+		body.WriteString(fmt.Sprintf("  %v%v: %v;\n", typeScriptProperty(typeScriptFieldName(route, field)), optionalMark(tag.OmitEmpty || route.OmitEmpty), fieldType))
+	}
+
+	decl := c.decls
+	decl.WriteString("\nexport interface " + name)
+	if len(extends) > 0 {
+		decl.WriteString(" extends " + strings.Join(extends, ", "))
+	}
+	decl.WriteString(" {\n")
+	decl.Write(body.Bytes())
+	decl.WriteString("}\n")
+	return name, nil
+}
+
+//typeScriptFieldName returns JSON field name used by route marshaller
+func typeScriptFieldName(route *Route, field reflect.StructField) string {
+	if tag := json.Parse(field.Tag.Get(json.TagName)); tag.FieldName != "" {
+		return tag.FieldName
+	}
+
+	if *route._caser == 0 {
+		return field.Name
+	}
+
+	return json.FormatName(field.Name, *route._caser)
+}
+
+func typeScriptAccessor(name string) string {
+	if typeScriptIdentifier.MatchString(name) {
+		return "." + name
+	}
+
+	return "[" + strconv.Quote(name) + "]"
+}
+
+func typeScriptProperty(name string) string {
+	if typeScriptIdentifier.MatchString(name) {
+		return name
+	}
+
+	return strconv.Quote(name)
+}
+
+func optionalMark(optional bool) string {
+	if optional {
+		return "?"
+	}
+
+	return ""
+}
+
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+
+	return strings.ToLower(name[:1]) + name[1:]
+}
@@ -0,0 +1,107 @@
+package router
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/view"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGenerateTypeScriptClient(t *testing.T) {
+	type Product struct {
+		ID       int
+		Name     *string
+		VendorID int
+		Created  time.Time
+	}
+
+	type Vendor struct {
+		ID       int
+		Name     string `json:",omitempty"`
+		Products []*Product
+	}
+
+	productsView := view.View{
+		Name:   "products",
+		Schema: view.NewSchema(reflect.TypeOf(&Product{})),
+		Selector: &view.Config{
+			CriteriaParam: &view.Parameter{Name: "_criteria", In: view.NewQueryLocation("pr_criteria")},
+		},
+	}
+
+	useCases := []struct {
+		description string
+		route       *Route
+		expect      []string
+	}{
+		{
+			description: "comprehensive style reader",
+			route: &Route{
+				Method: http.MethodGet,
+				URI:    "/v1/api/vendors/{vendorID}",
+				View: &view.View{
+					Name:   "vendor",
+					Schema: view.NewSchema(reflect.TypeOf(&Vendor{})),
+					Template: &view.Template{Parameters: []*view.Parameter{
+						{Name: "vendorID", In: &view.Location{Kind: view.PathKind, Name: "vendorID"}, Schema: view.NewSchema(reflect.TypeOf(0))},
+						{Name: "since", In: view.NewQueryLocation("since"), Schema: view.NewSchema(reflect.TypeOf(time.Time{}))},
+						{Name: "session", In: &view.Location{Kind: view.CookieKind, Name: "sid"}, Schema: view.NewSchema(reflect.TypeOf(""))},
+					}},
+					Selector: &view.Config{FieldsParam: &view.Parameter{Name: "_fields", In: view.NewQueryLocation("_fields")}},
+					With:     []*view.Relation{{Name: "products", Of: &view.ReferenceView{View: productsView}}},
+				},
+				Output: Output{Cardinality: view.One, CaseFormat: "lc", Style: ComprehensiveStyle, ResponseField: "Data", Exclude: []string{"Data.Products.VendorID"}},
+			},
+			expect: []string{
+				"export interface VendorRequest {\n  vendorID: number;\n  since?: Date | string;\n  session?: string;\n  selector?: Selector;\n  productsSelector?: Selector;\n}",
+				"async vendor(request: VendorRequest): Promise<Vendor> {",
+				"URI = URI.replace(\"{vendorID}\", encodeURIComponent(formatValue(request.vendorID)));",
+				"if (request.since !== undefined && request.since !== null) {\n      query.set(\"since\", formatValue(request.since));\n    }",
+				"cookies.push(\"sid=\" + encodeURIComponent(formatValue(request.session)));",
+				"encodeSelector(query, request.selector, { fields: \"_fields\" });",
+				"encodeSelector(query, request.productsSelector, { criteria: \"pr_criteria\" });",
+				"const response = await this.do(\"GET\", URI, query, headers, undefined, { status: \"status\", message: \"message\" });\n    return response.data;",
+				"export interface VendorResponse extends ResponseStatus {\n  data: Vendor;\n}",
+				"export interface Vendor {\n  id: number;\n  name?: string;\n  products: Product[];\n}",
+				"export interface Product {\n  id: number;\n  name: string | null;\n  created: string;\n}",
+			},
+		},
+		{
+			description: "executor with request body",
+			route: &Route{
+				Method:           http.MethodPost,
+				URI:              "/v1/api/products",
+				Service:          ExecutorServiceType,
+				View:             &productsView,
+				Output:           Output{OmitEmpty: true},
+				_requestBodyType: reflect.TypeOf(Product{}),
+			},
+			expect: []string{
+				"export interface ProductsRequest {\n  selector?: Selector;\n  body?: Product;\n}",
+				"async products(request: ProductsRequest = {}): Promise<void> {",
+				"await this.do(\"POST\", URI, query, headers, request.body);",
+				"export interface Product {\n  ID?: number;\n  Name?: string | null;\n  VendorID?: number;\n  Created?: string;\n}",
+			},
+		},
+	}
+
+	for _, useCase := range useCases {
+		route := useCase.route
+		route._resource = view.EmptyResource()
+		if !assert.Nil(t, route.initCaser(), useCase.description) || !assert.Nil(t, route.initStyle(), useCase.description) {
+			continue
+		}
+		route.indexExcluded()
+
+		source, err := GenerateTypeScriptClient(route)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		for _, expect := range useCase.expect {
+			assert.Contains(t, string(source), expect, useCase.description)
+		}
+	}
+}
@@ -4,14 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	as "github.com/aerospike/aerospike-client-go"
+	"github.com/robfig/cron/v3"
+	fs "github.com/viant/afs"
 	"github.com/viant/afs/option"
 	"github.com/viant/afs/url"
 	"github.com/viant/datly/converter"
 	"github.com/viant/datly/shared"
+	"github.com/viant/datly/view/cache/mem"
+	"github.com/viant/datly/view/cache/redis"
 	"github.com/viant/sqlx/io/read/cache"
 	"github.com/viant/sqlx/io/read/cache/aerospike"
 	"github.com/viant/sqlx/io/read/cache/afs"
 	rdata "github.com/viant/toolbox/data"
+	"math/rand"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,13 +33,24 @@ type (
 		Location     string
 		Provider     string
 		TimeToLiveMs int
-		PartSize     int `json:",omitempty"`
+		//StaleWhileRevalidateMs represents window after TimeToLiveMs when expired entries are served while refreshed in the background
+		StaleWhileRevalidateMs int        `json:",omitempty"`
+		PartSize               int        `json:",omitempty"`
+		Scope                  CacheScope `json:",omitempty"`
+		Tags                   []string   `json:",omitempty" yaml:",omitempty"`
+		MaxEntries             int        `json:",omitempty"` //mem:// provider and memory tier max entries
+		MaxBytes               int        `json:",omitempty"` //mem:// provider and memory tier max entries size
+		MemoryTier             bool       `json:",omitempty"` //puts memory tier in front of aerospike provider
 		AerospikeConfig
 		Warmup *Warmup `json:",omitempty" yaml:",omitempty"`
 
-		newCache    func() (cache.Cache, error)
-		initialized bool
-		mux         sync.Mutex
+		newCache     func() (cache.Cache, error)
+		memory       *mem.Cache
+		storage      fs.Service //afs cache storage service and expanded location checked by Ping
+		storageURL   string
+		revalidation *revalidation
+		initialized  bool
+		mux          sync.Mutex
 	}
 
 	AerospikeConfig struct {
@@ -50,6 +67,11 @@ type (
 		IndexMeta   bool       `json:",omitempty"`
 		Connector   *Connector `json:",omitempty"`
 		Cases       []*CacheParameters
+		Schedule    string `json:",omitempty"` //cron expression or @every interval, i.e. "*/15 * * * *", "@every 10m"
+		JitterMs    int    `json:",omitempty"` //max random delay added to each scheduled run
+		Watermark   string `json:",omitempty"` //column used by scheduled runs to rebuild only cases with changed data
+
+		_schedule cron.Schedule
 	}
 
 	CacheParameters struct {
@@ -77,6 +99,8 @@ const (
 	defaultType   = ""
 	afsType       = "afs"
 	aerospikeType = "aerospike"
+	memType       = mem.Scheme
+	redisType     = redis.Scheme
 )
 
 func (c *Cache) init(ctx context.Context, resource *Resource, aView *View) error {
@@ -86,6 +110,7 @@ func (c *Cache) init(ctx context.Context, resource *Resource, aView *View) error
 
 	c.initialized = true
 	c.owner = aView
+	c.revalidation = newRevalidation()
 	var viewName string
 	if aView != nil {
 		viewName = aView.Name
@@ -95,7 +120,7 @@ func (c *Cache) init(ctx context.Context, resource *Resource, aView *View) error
 		return err
 	}
 
-	if c.Location == "" {
+	if c.Location == "" && url.Scheme(c.Provider, "") != memType {
 		return fmt.Errorf("view %v cache Location can't be empty", viewName)
 	}
 
@@ -103,6 +128,12 @@ func (c *Cache) init(ctx context.Context, resource *Resource, aView *View) error
 		return fmt.Errorf("view %v cache TimeToLiveMs can't be empty", viewName)
 	}
 
+	if aView != nil {
+		if err := c.initScope(aView); err != nil {
+			return err
+		}
+	}
+
 	if err := c.ensureCacheClient(aView, viewName); err != nil {
 		return err
 	}
@@ -137,6 +168,13 @@ func (c *Cache) cacheService(name string, aView *View) (func() (cache.Cache, err
 	switch scheme {
 	case aerospikeType:
 		return c.aerospikeCache(aView)
+	case redisType:
+		return c.redisCache(aView)
+	case memType:
+		c.memory = mem.New(c.storageTTL(), c.MaxEntries, c.MaxBytes)
+		return func() (cache.Cache, error) {
+			return c.memory, nil
+		}, nil
 	default:
 		if aView.Name == "" {
 			return nil, nil
@@ -146,17 +184,35 @@ func (c *Cache) cacheService(name string, aView *View) (func() (cache.Cache, err
 			return nil, err
 		}
 
-		afsCache, err := afs.NewCache(expandedLoc, time.Duration(c.TimeToLiveMs)*time.Millisecond, aView.Name, option.NewStream(c.PartSize, 0))
+		afsCache, err := afs.NewCache(expandedLoc, c.storageTTL(), aView.Name, option.NewStream(c.PartSize, 0))
 		if err != nil {
 			return nil, err
 		}
 
+		c.storage, c.storageURL = fs.New(), expandedLoc
 		return func() (cache.Cache, error) {
 			return afsCache, nil
 		}, nil
 	}
 }
 
+func (c *Cache) redisCache(aView *View) (func() (cache.Cache, error), error) {
+	client, err := redis.Client(c.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, err := c.expandLocation(aView)
+	if err != nil {
+		return nil, err
+	}
+
+	redisCache := redis.New(client, prefix, c.storageTTL(), c.timeoutConfig(), c.failureHandler())
+	return func() (cache.Cache, error) {
+		return redisCache, nil
+	}, nil
+}
+
 func (c *Cache) aerospikeCache(aView *View) (func() (cache.Cache, error), error) {
 	if c.Location == "" {
 		return nil, fmt.Errorf("aerospike cache SetName cannot be empty")
@@ -174,28 +230,43 @@ func (c *Cache) aerospikeCache(aView *View) (func() (cache.Cache, error), error)
 		return nil, err
 	}
 
-	timeoutConfig := &aerospike.TimeoutConfig{
+	timeoutConfig := c.timeoutConfig()
+	failureHandler := c.failureHandler()
+	if c.MemoryTier {
+		c.memory = mem.New(c.storageTTL(), c.MaxEntries, c.MaxBytes)
+	}
+
+	return func() (cache.Cache, error) {
+		client, err := clientProvider()
+		if err != nil {
+			return nil, err
+		}
+
+		aerospikeCache, err := aerospike.New(namespace, expanded, client, uint32(c.storageTTL()/time.Second), timeoutConfig, failureHandler)
+		if err != nil || c.memory == nil {
+			return aerospikeCache, err
+		}
+
+		return mem.NewTiered(c.memory, aerospikeCache), nil
+	}, nil
+}
+
+func (c *Cache) timeoutConfig() *aerospike.TimeoutConfig {
+	return &aerospike.TimeoutConfig{
 		MaxRetries:            c.AerospikeConfig.MaxRetries,
 		TotalTimeoutMs:        c.AerospikeConfig.TotalTimeoutInMs,
 		SleepBetweenRetriesMs: c.SleepBetweenRetriesInMs,
 	}
+}
 
+func (c *Cache) failureHandler() *aerospike.FailureHandler {
 	var resetTimout *time.Duration
 	if c.AerospikeConfig.ResetFailuresInMs != 0 {
-		resetDuration := time.Duration(c.AerospikeConfig.ResetFailuresInMs)
+		resetDuration := time.Duration(c.AerospikeConfig.ResetFailuresInMs) * time.Millisecond
 		resetTimout = &resetDuration
 	}
 
-	failureHandler := aerospike.NewFailureHandler(int64(c.AerospikeConfig.FailedRequestLimit), resetTimout)
-
-	return func() (cache.Cache, error) {
-		client, err := clientProvider()
-		if err != nil {
-			return nil, err
-		}
-
-		return aerospike.New(namespace, expanded, client, uint32(c.TimeToLiveMs/1000), timeoutConfig, failureHandler)
-	}, nil
+	return aerospike.NewFailureHandler(int64(c.AerospikeConfig.FailedRequestLimit), resetTimout)
 }
 
 func (c *Cache) expandLocation(aView *View) (string, error) {
@@ -221,6 +292,64 @@ func (c *Cache) Service() (cache.Cache, error) {
 	return c.newCache()
 }
 
+//Ping checks if cache storage is reachable
+func (c *Cache) Ping(ctx context.Context) error {
+	switch url.Scheme(c.Provider, "") {
+	case memType:
+		return nil
+	case redisType:
+		client, err := redis.Client(c.Provider)
+		if err != nil {
+			return err
+		}
+
+		return client.Ping(ctx).Err()
+	case aerospikeType:
+		host, port, _, err := c.split(c.Provider)
+		if err != nil {
+			return err
+		}
+
+		client, err := aClientPool.Client(host, port)()
+		if err != nil {
+			return err
+		}
+
+		if !client.IsConnected() {
+			return fmt.Errorf("aerospike %v:%v is not connected", host, port)
+		}
+
+		return nil
+	default:
+		if c.storage == nil {
+			return fmt.Errorf("cache %v was not initialized", c.Location)
+		}
+
+		_, err := c.storage.Exists(ctx, c.storageURL)
+		return err
+	}
+}
+
+//ExpandedLocation returns cache Location expanded with the view owning the cache
+func (c *Cache) ExpandedLocation() (string, error) {
+	if c.owner == nil {
+		return c.Location, nil
+	}
+
+	return c.expandLocation(c.owner)
+}
+
+//AerospikeClient returns pooled client and namespace of the aerospike provider
+func (c *Cache) AerospikeClient() (*as.Client, string, error) {
+	host, port, namespace, err := c.split(c.Provider)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client, err := aClientPool.Client(host, port)()
+	return client, namespace, err
+}
+
 func (c *Cache) split(location string) (host string, port int, namespace string, err error) {
 	actualScheme := url.Scheme(location, "")
 
@@ -289,6 +418,18 @@ func (c *Cache) inherit(source *Cache) error {
 		c.PartSize = source.PartSize
 	}
 
+	if c.MaxEntries == 0 {
+		c.MaxEntries = source.MaxEntries
+	}
+
+	if c.MaxBytes == 0 {
+		c.MaxBytes = source.MaxBytes
+	}
+
+	if !c.MemoryTier {
+		c.MemoryTier = source.MemoryTier
+	}
+
 	if c.Location == "" {
 		c.Location = source.Location
 	}
@@ -297,6 +438,18 @@ func (c *Cache) inherit(source *Cache) error {
 		c.TimeToLiveMs = source.TimeToLiveMs
 	}
 
+	if c.StaleWhileRevalidateMs == 0 {
+		c.StaleWhileRevalidateMs = source.StaleWhileRevalidateMs
+	}
+
+	if c.Scope == "" {
+		c.Scope = source.Scope
+	}
+
+	if len(c.Tags) == 0 {
+		c.Tags = source.Tags
+	}
+
 	return nil
 }
 
@@ -383,6 +536,9 @@ func (c *Cache) initWarmup(ctx context.Context, resource *Resource) error {
 	}
 
 	c.addNonRequiredWarmupIfNeeded()
+	if err := c.Warmup.initSchedule(); err != nil {
+		return fmt.Errorf("invalid view %v warmup schedule: %w", c.owner.Name, err)
+	}
 
 	_, ok := c.owner.ColumnByName(c.Warmup.IndexColumn)
 	if !ok && c.Warmup.IndexColumn != "" {
@@ -407,6 +563,40 @@ func (c *Cache) initWarmup(ctx context.Context, resource *Resource) error {
 	return nil
 }
 
+func (w *Warmup) initSchedule() error {
+	if w.Schedule == "" || w._schedule != nil {
+		return nil
+	}
+
+	var err error
+	w._schedule, err = cron.ParseStandard(w.Schedule)
+	return err
+}
+
+//Scheduled returns true if warmup runs on schedule
+func (w *Warmup) Scheduled() bool {
+	return w._schedule != nil
+}
+
+//NextRun returns next scheduled warmup run with random jitter
+func (w *Warmup) NextRun(after time.Time) time.Time {
+	return w.ScheduledRun(after).Add(w.Jitter())
+}
+
+//ScheduledRun returns next scheduled warmup run without jitter, scheduled runs are the same for all runners
+func (w *Warmup) ScheduledRun(after time.Time) time.Time {
+	return w._schedule.Next(after)
+}
+
+//Jitter returns random delay of the scheduled run
+func (w *Warmup) Jitter() time.Duration {
+	if w.JitterMs <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Intn(w.JitterMs)) * time.Millisecond
+}
+
 func (c *Cache) ensureParam(paramValue *ParamValue) error {
 	if paramValue._param != nil {
 		return nil
@@ -0,0 +1,523 @@
+package mem
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/viant/datly/view/cache/shared"
+	"github.com/viant/sqlx/io"
+	"github.com/viant/sqlx/io/read/cache"
+	"github.com/viant/sqlx/io/read/cache/aerospike"
+	"github.com/viant/sqlx/io/read/cache/hash"
+	goIo "io"
+	"sync"
+	"time"
+)
+
+const (
+	//Scheme represents in process cache provider scheme
+	Scheme = "mem"
+	//DefaultMaxEntries represents default max number of entries kept by the cache
+	DefaultMaxEntries = 10000
+	//DefaultMaxBytes represents default max size of entries kept by the cache
+	DefaultMaxBytes = 64 * 1024 * 1024
+)
+
+type (
+	//Cache represents in process cache bounded by max entries and bytes, least recently used entries are evicted first
+	Cache struct {
+		ttl        time.Duration
+		maxEntries int
+		maxBytes   int
+
+		mux     sync.Mutex
+		entries map[string]*list.Element
+		lru     *list.List
+		size    int
+		writing map[string]*buffer
+	}
+
+	record struct {
+		key      string
+		SQL      string
+		args     string
+		fields   []*cache.Field
+		types    []string
+		data     []byte
+		expireAt time.Time
+		//indexed represents keys of the records indexed with the marker, value without a record had no rows
+		indexed map[string]bool
+	}
+
+	//buffer represents in flight entry data
+	buffer struct {
+		data []byte
+	}
+
+	//reader represents entry data reader
+	reader struct {
+		data   []byte
+		offset int
+	}
+)
+
+//New creates in memory cache, non positive bounds are replaced with defaults
+func New(ttl time.Duration, maxEntries, maxBytes int) *Cache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+
+	return &Cache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+		writing:    map[string]*buffer{},
+	}
+}
+
+//Len returns number of cached entries
+func (c *Cache) Len() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.lru.Len()
+}
+
+//Size returns size of cached entries
+func (c *Cache) Size() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.size
+}
+
+func (c *Cache) AsSource(ctx context.Context, entry *cache.Entry) (cache.Source, error) {
+	return shared.NewSource(c, entry), nil
+}
+
+func (c *Cache) AddValues(ctx context.Context, entry *cache.Entry, values []interface{}) error {
+	marshal, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	return entry.Write(marshal)
+}
+
+func (c *Cache) Get(ctx context.Context, SQL string, args []interface{}, options ...interface{}) (*cache.Entry, error) {
+	matcher, stats := shared.QueryOptions(options)
+	entry, err := c.lookup(SQL, args, matcher, stats)
+	if err != nil || entry != nil {
+		return entry, err
+	}
+
+	return c.newWriter(SQL, args, stats)
+}
+
+func (c *Cache) lookup(SQL string, args []interface{}, matcher *cache.ParmetrizedQuery, stats *cache.Stats) (*cache.Entry, error) {
+	lookup, err := newRecord(SQL, args)
+	if err != nil {
+		return nil, err
+	}
+
+	key := lookup.key
+	entry := &cache.Entry{Id: key, Meta: cache.Meta{SQL: SQL, Args: []byte(lookup.args)}}
+	if aRecord, ok := c.get(key); ok && aRecord.matches(SQL, entry.Meta.Args) {
+		if err = assignRecord(entry, aRecord); err != nil {
+			return nil, err
+		}
+
+		stats.Type = cache.TypeReadSingle
+		stats.FoundLazy = true
+		stats.RecordsCounter = 1
+		stats.Key = key
+		return entry, nil
+	}
+
+	if matcher == nil {
+		return nil, nil
+	}
+
+	return c.lookupIndexed(entry, matcher, stats)
+}
+
+func (c *Cache) lookupIndexed(entry *cache.Entry, matcher *cache.ParmetrizedQuery, stats *cache.Stats) (*cache.Entry, error) {
+	argsMarshal, err := matcher.MarshalArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	URL, err := hash.GenerateWithMarshal(matcher.SQL, "", "", argsMarshal)
+	if err != nil {
+		return nil, err
+	}
+
+	marker, ok := c.get(shared.ColumnURL(URL, matcher.By))
+	if !ok || !marker.matches(matcher.SQL, argsMarshal) {
+		return nil, nil
+	}
+
+	var data []byte
+	for _, value := range matcher.In {
+		valueMarshal, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		key := shared.ColumnValueURL(matcher.By, valueMarshal, URL)
+		aRecord, ok := c.get(key)
+		if !ok {
+			if marker.indexed[key] {
+				c.delete(marker.key)
+				return nil, nil
+			}
+			continue
+		}
+
+		lines := shared.SelectLines(aRecord.data, matcher.Offset, matcher.Limit)
+		if len(lines) == 0 {
+			continue
+		}
+
+		if len(data) > 0 {
+			data = append(data, '\n')
+		}
+		data = append(data, lines...)
+	}
+
+	entry.Id = marker.key
+	if err = assignRecord(entry, &record{fields: marker.fields, types: marker.types, data: data}); err != nil {
+		return nil, err
+	}
+
+	stats.Type = cache.TypeReadMulti
+	stats.FoundWarmup = true
+	stats.RecordsCounter = len(matcher.In)
+	stats.Key = marker.key
+	return entry, nil
+}
+
+func (c *Cache) newWriter(SQL string, args []interface{}, stats *cache.Stats) (*cache.Entry, error) {
+	aRecord, err := newRecord(SQL, args)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if _, ok := c.writing[aRecord.key]; ok {
+		return nil, nil
+	}
+
+	aBuffer := &buffer{}
+	c.writing[aRecord.key] = aBuffer
+	entry := &cache.Entry{Id: aRecord.key, Meta: cache.Meta{SQL: SQL, Args: []byte(aRecord.args)}}
+	entry.SetWriter(cache.NewLineWriter(aBuffer), aBuffer)
+	stats.Type = cache.TypeWrite
+	return entry, nil
+}
+
+func (c *Cache) AssignRows(entry *cache.Entry, rows *sql.Rows) error {
+	return entry.AssignRows(rows)
+}
+
+func (c *Cache) UpdateType(ctx context.Context, entry *cache.Entry, args []interface{}) (bool, error) {
+	typeHolder := &cache.ScanTypeHolder{}
+	typeHolder.InitType(args)
+	if !typeHolder.Match(entry) {
+		return false, c.Delete(ctx, entry)
+	}
+
+	return true, nil
+}
+
+func (c *Cache) Close(ctx context.Context, entry *cache.Entry) error {
+	if entry.Has() {
+		return entry.Close()
+	}
+
+	if err := entry.Close(); err != nil {
+		_ = c.Delete(ctx, entry)
+		return err
+	}
+
+	c.mux.Lock()
+	aBuffer, ok := c.writing[entry.Id]
+	delete(c.writing, entry.Id)
+	c.mux.Unlock()
+	if !ok {
+		return nil
+	}
+
+	c.put(&record{
+		key:    entry.Id,
+		SQL:    entry.Meta.SQL,
+		args:   string(entry.Meta.Args),
+		fields: entry.Meta.Fields,
+		types:  entry.Meta.Type,
+		data:   aBuffer.data,
+	})
+
+	return nil
+}
+
+func (c *Cache) Delete(ctx context.Context, entry *cache.Entry) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	delete(c.writing, entry.Id)
+	if element, ok := c.entries[entry.Id]; ok {
+		c.remove(element)
+	}
+
+	return nil
+}
+
+func (c *Cache) Rollback(ctx context.Context, entry *cache.Entry) error {
+	return c.Delete(ctx, entry)
+}
+
+//IndexBy caches SQL result indexed by column values, indexed entries are matched with the cache.ParmetrizedQuery
+func (c *Cache) IndexBy(ctx context.Context, db *sql.DB, column, SQL string, args []interface{}) (int, error) {
+	if args == nil {
+		args = []interface{}{}
+	}
+
+	querySQL, ordered := shared.OrderedSQL(SQL, column)
+	rows, err := db.QueryContext(ctx, querySQL, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+
+	fields, err := cache.ColumnsToFields(io.TypesToColumns(columnTypes))
+	if err != nil {
+		return 0, err
+	}
+
+	values := make(chan *cache.Indexed, 512)
+	indexSource, err := aerospike.NewIndexSource(column, ordered, fields, values)
+	if err != nil {
+		return 0, err
+	}
+
+	argsMarshal, err := json.Marshal(args)
+	if err != nil {
+		return 0, err
+	}
+
+	URL, err := hash.GenerateWithMarshal(SQL, "", "", argsMarshal)
+	if err != nil {
+		return 0, err
+	}
+
+	var fetchErr error
+	go func() {
+		defer close(values)
+		placeholders := aerospike.NewPlaceholders(indexSource.ColumnIndex(), fields)
+		for rows.Next() {
+			if fetchErr = rows.Scan(placeholders.ScanPlaceholders()...); fetchErr != nil {
+				return
+			}
+
+			columnValue, ok := placeholders.ColumnValue()
+			if !ok {
+				continue
+			}
+
+			indexed := indexSource.Index(columnValue)
+			indexed.Column = column
+			if fetchErr = indexed.StringifyData(placeholders.Values()); fetchErr != nil {
+				return
+			}
+		}
+
+		fetchErr = indexSource.Close()
+	}()
+
+	inserted := 0
+	keys := map[string]bool{}
+	var indexErr error
+	for indexed := range values {
+		if (indexed.ColumnValue == nil && column != "") || indexErr != nil {
+			continue
+		}
+
+		key := URL
+		if column != "" {
+			valueMarshal, err := json.Marshal(indexed.ColumnValue)
+			if err != nil {
+				indexErr = err
+				continue
+			}
+			key = shared.ColumnValueURL(column, valueMarshal, URL)
+		}
+
+		c.put(&record{key: key, SQL: SQL, args: string(argsMarshal), fields: fields, data: indexed.Data.Bytes()})
+		keys[key] = true
+		inserted++
+	}
+
+	if fetchErr != nil {
+		return inserted, fetchErr
+	}
+
+	if indexErr != nil {
+		return inserted, indexErr
+	}
+
+	if column != "" {
+		c.put(&record{key: shared.ColumnURL(URL, column), SQL: SQL, args: string(argsMarshal), fields: fields, indexed: keys})
+		inserted++
+	}
+
+	return inserted, rows.Err()
+}
+
+func (c *Cache) get(key string) (*record, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	aRecord := element.Value.(*record)
+	if time.Now().After(aRecord.expireAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	return aRecord, true
+}
+
+func (c *Cache) put(aRecord *record) {
+	aRecord.expireAt = time.Now().Add(c.ttl)
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if element, ok := c.entries[aRecord.key]; ok {
+		c.remove(element)
+	}
+
+	if aRecord.size() > c.maxBytes {
+		return
+	}
+
+	c.entries[aRecord.key] = c.lru.PushFront(aRecord)
+	c.size += aRecord.size()
+	for c.lru.Len() > c.maxEntries || c.size > c.maxBytes {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) delete(key string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+}
+
+func (c *Cache) remove(element *list.Element) {
+	aRecord := element.Value.(*record)
+	c.lru.Remove(element)
+	delete(c.entries, aRecord.key)
+	c.size -= aRecord.size()
+}
+
+func newRecord(SQL string, args []interface{}) (*record, error) {
+	argsMarshal, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hash.GenerateWithMarshal(SQL, "", "", argsMarshal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record{key: key, SQL: SQL, args: string(argsMarshal)}, nil
+}
+
+func (r *record) size() int {
+	result := len(r.key) + len(r.SQL) + len(r.args) + len(r.data)
+	for key := range r.indexed {
+		result += len(key)
+	}
+
+	return result
+}
+
+func (r *record) matches(SQL string, args []byte) bool {
+	return r.SQL == SQL && r.args == string(args)
+}
+
+func assignRecord(entry *cache.Entry, aRecord *record) error {
+	entry.Meta.Type = aRecord.types
+	entry.Meta.Fields = aRecord.fields
+	for _, field := range entry.Meta.Fields {
+		if err := field.Init(); err != nil {
+			return err
+		}
+	}
+
+	aReader := &reader{data: aRecord.data}
+	entry.SetReader(aReader, aReader)
+	return nil
+}
+
+func (b *buffer) Write(data []byte) (int, error) {
+	b.data = append(b.data, data...)
+	return len(data), nil
+}
+
+func (b *buffer) Flush() error {
+	return nil
+}
+
+func (b *buffer) Close() error {
+	return nil
+}
+
+func (r *reader) Read(dest []byte) (int, error) {
+	if r.offset >= len(r.data) {
+		return 0, goIo.EOF
+	}
+
+	n := copy(dest, r.data[r.offset:])
+	r.offset += n
+	return n, nil
+}
+
+func (r *reader) ReadLine() ([]byte, bool, error) {
+	if r.offset >= len(r.data) {
+		return nil, false, goIo.EOF
+	}
+
+	data := r.data[r.offset:]
+	end := len(data)
+	next := end
+	for i, b := range data {
+		if b == '\n' {
+			end, next = i, i+1
+			break
+		}
+	}
+
+	r.offset += next
+	return data[:end], false, nil
+}
+
+func (r *reader) Close() error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package mem
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/sqlx/io/read"
+	"github.com/viant/sqlx/io/read/cache"
+	_ "github.com/viant/sqlx/metadata/product/sqlite"
+	"github.com/viant/sqlx/option"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCache_Get(t *testing.T) {
+	type Event struct {
+		ID     int    `sqlx:"ID"`
+		TypeID int    `sqlx:"TYPE_ID"`
+		Name   string `sqlx:"NAME"`
+	}
+
+	dsn := "/tmp/datly_mem_cache_test.db"
+	_ = os.Remove(dsn)
+	db, err := sql.Open("sqlite3", dsn)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	for _, SQL := range []string{
+		"CREATE TABLE EVENTS (ID INTEGER PRIMARY KEY, TYPE_ID INTEGER, NAME TEXT)",
+		"INSERT INTO EVENTS VALUES (1, 10, 'a'), (2, 10, 'b'), (3, 20, 'c')",
+	} {
+		if _, err = db.Exec(SQL); !assert.Nil(t, err) {
+			return
+		}
+	}
+
+	query := func(aCache cache.Cache, SQL string, matcher *cache.ParmetrizedQuery, args ...interface{}) ([]*Event, *cache.Stats) {
+		var events []*Event
+		stats := &cache.Stats{}
+		options := []option.Option{aCache, stats}
+		if matcher != nil {
+			options = append(options, matcher)
+		}
+
+		reader, err := read.New(context.Background(), db, SQL, func() interface{} { return &Event{} }, options...)
+		if !assert.Nil(t, err, SQL) {
+			return nil, stats
+		}
+
+		assert.Nil(t, reader.QueryAll(context.Background(), func(row interface{}) error {
+			events = append(events, row.(*Event))
+			return nil
+		}, args...), SQL)
+		return events, stats
+	}
+
+	useCases := []struct {
+		description string
+		maxEntries  int
+		run         func(aCache *Cache)
+	}{
+		{
+			description: "lazy entry is written then read",
+			run: func(aCache *Cache) {
+				SQL := "SELECT ID, TYPE_ID, NAME FROM EVENTS WHERE TYPE_ID = ?"
+				expect := []*Event{{ID: 1, TypeID: 10, Name: "a"}, {ID: 2, TypeID: 10, Name: "b"}}
+				events, stats := query(aCache, SQL, nil, 10)
+				assert.Equal(t, expect, events)
+				assert.EqualValues(t, cache.TypeWrite, stats.Type)
+
+				events, stats = query(aCache, SQL, nil, 10)
+				assert.Equal(t, expect, events)
+				assert.True(t, stats.FoundLazy)
+				assert.Equal(t, 1, aCache.Len())
+			},
+		},
+		{
+			description: "least recently used entry is evicted",
+			maxEntries:  1,
+			run: func(aCache *Cache) {
+				SQL := "SELECT ID, TYPE_ID, NAME FROM EVENTS WHERE TYPE_ID = ?"
+				query(aCache, SQL, nil, 10)
+				query(aCache, SQL, nil, 20)
+				_, stats := query(aCache, SQL, nil, 10)
+				assert.EqualValues(t, cache.TypeWrite, stats.Type)
+				assert.Equal(t, 1, aCache.Len())
+			},
+		},
+		{
+			description: "warmup indexed entries are matched",
+			run: func(aCache *Cache) {
+				SQL := "SELECT ID, TYPE_ID, NAME FROM EVENTS"
+				indexed, err := aCache.IndexBy(context.Background(), db, "TYPE_ID", SQL, nil)
+				assert.Nil(t, err)
+				assert.Equal(t, 3, indexed)
+
+				events, stats := query(aCache, SQL+" WHERE TYPE_ID IN (?, ?)", &cache.ParmetrizedQuery{By: "TYPE_ID", SQL: SQL, In: []interface{}{20, 10}}, 20, 10)
+				assert.True(t, stats.FoundWarmup)
+				assert.Equal(t, []*Event{{ID: 3, TypeID: 20, Name: "c"}, {ID: 1, TypeID: 10, Name: "a"}, {ID: 2, TypeID: 10, Name: "b"}}, events)
+
+				events, stats = query(aCache, SQL+" WHERE TYPE_ID IN (?, ?)", &cache.ParmetrizedQuery{By: "TYPE_ID", SQL: SQL, In: []interface{}{30, 20}}, 30, 20)
+				assert.True(t, stats.FoundWarmup, "value without rows")
+				assert.Equal(t, []*Event{{ID: 3, TypeID: 20, Name: "c"}}, events)
+			},
+		},
+		{
+			description: "warmup entries with evicted value are not matched",
+			maxEntries:  3,
+			run: func(aCache *Cache) {
+				SQL := "SELECT ID, TYPE_ID, NAME FROM EVENTS"
+				indexed, err := aCache.IndexBy(context.Background(), db, "TYPE_ID", SQL, nil)
+				assert.Nil(t, err)
+				assert.Equal(t, 3, indexed)
+
+				query(aCache, SQL+" WHERE ID = ?", nil, 1)
+				events, stats := query(aCache, SQL+" WHERE TYPE_ID IN (?, ?)", &cache.ParmetrizedQuery{By: "TYPE_ID", SQL: SQL, In: []interface{}{20, 10}}, 20, 10)
+				assert.False(t, stats.FoundWarmup)
+				assert.Equal(t, 3, len(events))
+
+				_, stats = query(aCache, SQL+" WHERE TYPE_ID IN (?)", &cache.ParmetrizedQuery{By: "TYPE_ID", SQL: SQL, In: []interface{}{20}}, 20)
+				assert.False(t, stats.FoundWarmup, "marker was dropped")
+			},
+		},
+	}
+
+	for _, useCase := range useCases {
+		useCase.run(New(time.Minute, useCase.maxEntries, 0))
+	}
+}
@@ -0,0 +1,181 @@
+package mem
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/viant/datly/view/cache/shared"
+	"github.com/viant/sqlx/io/read/cache"
+	"io"
+	"sync"
+)
+
+type (
+	//Tiered represents memory cache in front of the next tier cache, i.e. aerospike.
+	//Next tier hits are copied to the memory tier, writes are stored in both tiers
+	Tiered struct {
+		memory *Cache
+		next   cache.Cache
+
+		mux     sync.Mutex
+		pending map[*cache.Entry]*record
+	}
+)
+
+//NewTiered creates cache with memory tier in front of the next tier
+func NewTiered(memory *Cache, next cache.Cache) *Tiered {
+	return &Tiered{memory: memory, next: next, pending: map[*cache.Entry]*record{}}
+}
+
+func (t *Tiered) AsSource(ctx context.Context, entry *cache.Entry) (cache.Source, error) {
+	if t.isPending(entry) {
+		return t.next.AsSource(ctx, entry)
+	}
+
+	return t.memory.AsSource(ctx, entry)
+}
+
+func (t *Tiered) AddValues(ctx context.Context, entry *cache.Entry, values []interface{}) error {
+	aRecord, ok := t.pendingRecord(entry)
+	if !ok {
+		return t.memory.AddValues(ctx, entry, values)
+	}
+
+	if err := t.next.AddValues(ctx, entry, values); err != nil {
+		return err
+	}
+
+	marshal, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	if len(aRecord.data) > 0 {
+		aRecord.data = append(aRecord.data, '\n')
+	}
+	aRecord.data = append(aRecord.data, marshal...)
+	return nil
+}
+
+func (t *Tiered) Get(ctx context.Context, SQL string, args []interface{}, options ...interface{}) (*cache.Entry, error) {
+	matcher, stats := shared.QueryOptions(options)
+	entry, err := t.memory.lookup(SQL, args, matcher, stats)
+	if err != nil || entry != nil {
+		return entry, err
+	}
+
+	aRecord, err := newRecord(SQL, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry, err = t.next.Get(ctx, SQL, args, options...); err != nil || entry == nil {
+		return entry, err
+	}
+
+	if !entry.Has() {
+		t.mux.Lock()
+		t.pending[entry] = aRecord
+		t.mux.Unlock()
+		return entry, nil
+	}
+
+	return t.promote(ctx, aRecord, entry)
+}
+
+//promote copies next tier entry to the memory tier, partially read entry is not promoted
+func (t *Tiered) promote(ctx context.Context, aRecord *record, entry *cache.Entry) (*cache.Entry, error) {
+	for {
+		line, err := cache.ReadLine(entry.ReadCloser)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			_ = t.next.Close(ctx, entry)
+			return nil, err
+		}
+
+		if len(aRecord.data) > 0 {
+			aRecord.data = append(aRecord.data, '\n')
+		}
+		aRecord.data = append(aRecord.data, line...)
+	}
+
+	if err := t.next.Close(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	aRecord.fields, aRecord.types = entry.Meta.Fields, entry.Meta.Type
+	t.memory.put(aRecord)
+	promoted := &cache.Entry{Id: aRecord.key, Meta: entry.Meta}
+	return promoted, assignRecord(promoted, aRecord)
+}
+
+func (t *Tiered) AssignRows(entry *cache.Entry, rows *sql.Rows) error {
+	return entry.AssignRows(rows)
+}
+
+func (t *Tiered) UpdateType(ctx context.Context, entry *cache.Entry, args []interface{}) (bool, error) {
+	if t.isPending(entry) {
+		return t.next.UpdateType(ctx, entry, args)
+	}
+
+	return t.memory.UpdateType(ctx, entry, args)
+}
+
+func (t *Tiered) Close(ctx context.Context, entry *cache.Entry) error {
+	aRecord, ok := t.release(entry)
+	if !ok {
+		return t.memory.Close(ctx, entry)
+	}
+
+	if err := t.next.Close(ctx, entry); err != nil {
+		return err
+	}
+
+	aRecord.fields, aRecord.types = entry.Meta.Fields, entry.Meta.Type
+	t.memory.put(aRecord)
+	return nil
+}
+
+func (t *Tiered) Delete(ctx context.Context, entry *cache.Entry) error {
+	if _, ok := t.release(entry); ok {
+		return t.next.Delete(ctx, entry)
+	}
+
+	return t.memory.Delete(ctx, entry)
+}
+
+func (t *Tiered) Rollback(ctx context.Context, entry *cache.Entry) error {
+	if _, ok := t.release(entry); ok {
+		return t.next.Rollback(ctx, entry)
+	}
+
+	return t.memory.Rollback(ctx, entry)
+}
+
+//IndexBy indexes next tier only, indexed entries are copied to the memory tier once matched
+func (t *Tiered) IndexBy(ctx context.Context, db *sql.DB, column, SQL string, args []interface{}) (int, error) {
+	return t.next.IndexBy(ctx, db, column, SQL, args)
+}
+
+func (t *Tiered) isPending(entry *cache.Entry) bool {
+	_, ok := t.pendingRecord(entry)
+	return ok
+}
+
+func (t *Tiered) pendingRecord(entry *cache.Entry) (*record, bool) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	aRecord, ok := t.pending[entry]
+	return aRecord, ok
+}
+
+func (t *Tiered) release(entry *cache.Entry) (*record, bool) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	aRecord, ok := t.pending[entry]
+	delete(t.pending, entry)
+	return aRecord, ok
+}
@@ -0,0 +1,134 @@
+package mem
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/sqlx/io/read"
+	"github.com/viant/sqlx/io/read/cache"
+	_ "github.com/viant/sqlx/metadata/product/sqlite"
+	"github.com/viant/sqlx/option"
+	"os"
+	"testing"
+	"time"
+)
+
+type (
+	//failingTier represents next tier failing to read entries after the first line
+	failingTier struct {
+		*Cache
+	}
+
+	failingReader struct {
+		cache.Reader
+		lines int
+	}
+)
+
+func (f *failingTier) Get(ctx context.Context, SQL string, args []interface{}, options ...interface{}) (*cache.Entry, error) {
+	entry, err := f.Cache.Get(ctx, SQL, args, options...)
+	if err != nil || entry == nil || !entry.Has() {
+		return entry, err
+	}
+
+	entry.ReadCloser = cache.NewReadCloser(&failingReader{Reader: entry.ReadCloser, lines: 1}, entry.ReadCloser)
+	return entry, nil
+}
+
+func (r *failingReader) ReadLine() ([]byte, bool, error) {
+	if r.lines == 0 {
+		return nil, false, fmt.Errorf("connection reset")
+	}
+
+	r.lines--
+	return r.Reader.ReadLine()
+}
+
+func TestTiered_Get(t *testing.T) {
+	type Event struct {
+		ID     int    `sqlx:"ID"`
+		TypeID int    `sqlx:"TYPE_ID"`
+		Name   string `sqlx:"NAME"`
+	}
+
+	dsn := "/tmp/datly_tiered_cache_test.db"
+	_ = os.Remove(dsn)
+	db, err := sql.Open("sqlite3", dsn)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	for _, SQL := range []string{
+		"CREATE TABLE EVENTS (ID INTEGER PRIMARY KEY, TYPE_ID INTEGER, NAME TEXT)",
+		"INSERT INTO EVENTS VALUES (1, 10, 'a'), (2, 10, 'b'), (3, 20, 'c')",
+	} {
+		if _, err = db.Exec(SQL); !assert.Nil(t, err) {
+			return
+		}
+	}
+
+	query := func(aCache cache.Cache, SQL string, args ...interface{}) ([]*Event, *cache.Stats) {
+		var events []*Event
+		stats := &cache.Stats{}
+		reader, err := read.New(context.Background(), db, SQL, func() interface{} { return &Event{} }, []option.Option{aCache, stats}...)
+		if !assert.Nil(t, err, SQL) {
+			return nil, stats
+		}
+
+		assert.Nil(t, reader.QueryAll(context.Background(), func(row interface{}) error {
+			events = append(events, row.(*Event))
+			return nil
+		}, args...), SQL)
+		return events, stats
+	}
+
+	SQL := "SELECT ID, TYPE_ID, NAME FROM EVENTS WHERE TYPE_ID = ?"
+	expect := []*Event{{ID: 1, TypeID: 10, Name: "a"}, {ID: 2, TypeID: 10, Name: "b"}}
+	useCases := []struct {
+		description string
+		run         func(memory *Cache, next *Cache)
+	}{
+		{
+			description: "miss is written to both tiers",
+			run: func(memory *Cache, next *Cache) {
+				events, stats := query(NewTiered(memory, next), SQL, 10)
+				assert.Equal(t, expect, events)
+				assert.EqualValues(t, cache.TypeWrite, stats.Type)
+				assert.Equal(t, 1, memory.Len())
+				assert.Equal(t, 1, next.Len())
+			},
+		},
+		{
+			description: "next tier hit is promoted to memory tier",
+			run: func(memory *Cache, next *Cache) {
+				query(next, SQL, 10)
+				aCache := NewTiered(memory, next)
+				events, stats := query(aCache, SQL, 10)
+				assert.Equal(t, expect, events)
+				assert.True(t, stats.FoundLazy)
+				assert.Equal(t, 1, memory.Len())
+
+				events, _ = query(aCache, SQL, 10)
+				assert.Equal(t, expect, events, "read from memory tier")
+			},
+		},
+		{
+			description: "next tier read error is not promoted",
+			run: func(memory *Cache, next *Cache) {
+				query(next, SQL, 10)
+				aCache := NewTiered(memory, &failingTier{Cache: next})
+				entry, err := aCache.Get(context.Background(), SQL, []interface{}{10})
+				assert.EqualError(t, err, "connection reset")
+				assert.Nil(t, entry)
+				assert.Equal(t, 0, memory.Len())
+			},
+		},
+	}
+
+	for _, useCase := range useCases {
+		useCase.run(New(time.Minute, 0, 0), New(time.Minute, 0, 0))
+	}
+}
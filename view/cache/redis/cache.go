@@ -0,0 +1,575 @@
+package redis
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/viant/datly/view/cache/shared"
+	"github.com/viant/sqlx/io"
+	"github.com/viant/sqlx/io/read/cache"
+	"github.com/viant/sqlx/io/read/cache/aerospike"
+	"github.com/viant/sqlx/io/read/cache/hash"
+	"sync"
+	"time"
+)
+
+const (
+	metaBin    = "meta"
+	dataBin    = "data"
+	indexedBin = "indexed"
+
+	//ErrorTypeServerGeneric represents redis command error
+	ErrorTypeServerGeneric = "redis error occurred"
+	//ErrorTypeCurrentlyNotAvailable represents redis skipped due to exceeded failed requests limit
+	ErrorTypeCurrentlyNotAvailable = "redis currently not available"
+
+	indexBatchSize = 256
+)
+
+type (
+	//Cache represents redis cache, entries are stored as hashes with meta and data fields.
+	//Lazy entries are keyed by the SQL and args hash, warmup entries use aerospike cache index key layout.
+	//Redis failures are reported with cache stats and handled as cache miss.
+	Cache struct {
+		client         *goredis.Client
+		prefix         string
+		ttl            time.Duration
+		timeoutConfig  *aerospike.TimeoutConfig
+		failureHandler *aerospike.FailureHandler
+
+		mux     sync.Mutex
+		writing map[string]*buffer
+	}
+
+	hashValue struct {
+		meta    *cache.Meta
+		data    []byte
+		indexed []string //keys of the records indexed with the marker, value without a record had no rows
+	}
+
+	buffer struct {
+		bytes.Buffer
+	}
+
+	nopCloser struct{}
+)
+
+//New creates redis cache, supported options: *aerospike.TimeoutConfig, *aerospike.FailureHandler
+func New(client *goredis.Client, prefix string, ttl time.Duration, options ...interface{}) *Cache {
+	result := &Cache{client: client, prefix: prefix, ttl: ttl, writing: map[string]*buffer{}}
+	for _, option := range options {
+		switch actual := option.(type) {
+		case *aerospike.TimeoutConfig:
+			result.timeoutConfig = actual
+		case *aerospike.FailureHandler:
+			result.failureHandler = actual
+		}
+	}
+
+	return result
+}
+
+func (c *Cache) AsSource(ctx context.Context, entry *cache.Entry) (cache.Source, error) {
+	return shared.NewSource(c, entry), nil
+}
+
+func (c *Cache) AddValues(ctx context.Context, entry *cache.Entry, values []interface{}) error {
+	marshal, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	return entry.Write(marshal)
+}
+
+func (c *Cache) Get(ctx context.Context, SQL string, args []interface{}, options ...interface{}) (*cache.Entry, error) {
+	matcher, stats := shared.QueryOptions(options)
+	if c.failureHandler != nil && c.failureHandler.IsProbing() {
+		stats.ErrorType = ErrorTypeCurrentlyNotAvailable
+		return nil, nil
+	}
+
+	entry, err := c.lookup(ctx, SQL, args, matcher, stats)
+	if err != nil {
+		stats.ErrorType = ErrorTypeServerGeneric
+		return nil, nil
+	}
+
+	if entry != nil {
+		return entry, nil
+	}
+
+	return c.newWriter(SQL, args, stats)
+}
+
+func (c *Cache) lookup(ctx context.Context, SQL string, args []interface{}, matcher *cache.ParmetrizedQuery, stats *cache.Stats) (*cache.Entry, error) {
+	argsMarshal, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hash.GenerateWithMarshal(SQL, "", "", argsMarshal)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := c.hGetAll(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &cache.Entry{Id: key, Meta: cache.Meta{SQL: SQL, Args: argsMarshal}}
+	if meta, ok := c.matchingMeta(values, SQL, argsMarshal); ok {
+		if err = assignEntry(entry, meta, []byte(values[dataBin])); err != nil {
+			return nil, err
+		}
+
+		stats.Type = cache.TypeReadSingle
+		stats.FoundLazy = true
+		stats.RecordsCounter = 1
+		stats.Key = key
+		return entry, nil
+	}
+
+	if matcher == nil {
+		return nil, nil
+	}
+
+	return c.lookupIndexed(ctx, entry, matcher, stats)
+}
+
+func (c *Cache) lookupIndexed(ctx context.Context, entry *cache.Entry, matcher *cache.ParmetrizedQuery, stats *cache.Stats) (*cache.Entry, error) {
+	argsMarshal, err := matcher.MarshalArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	URL, err := hash.GenerateWithMarshal(matcher.SQL, "", "", argsMarshal)
+	if err != nil {
+		return nil, err
+	}
+
+	markerKey := shared.ColumnURL(URL, matcher.By)
+	values, err := c.hGetAll(ctx, markerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, ok := c.matchingMeta(values, matcher.SQL, argsMarshal)
+	if !ok {
+		return nil, nil
+	}
+
+	indexed := map[string]bool{}
+	if indexedValue, ok := values[indexedBin]; ok {
+		var indexedKeys []string
+		if err = json.Unmarshal([]byte(indexedValue), &indexedKeys); err != nil {
+			return nil, nil
+		}
+
+		for _, key := range indexedKeys {
+			indexed[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(matcher.In))
+	for _, value := range matcher.In {
+		valueMarshal, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, shared.ColumnValueURL(matcher.By, valueMarshal, URL))
+	}
+
+	var commands []*goredis.StringCmd
+	err = c.do(ctx, func(ctx context.Context) error {
+		pipeline := c.client.Pipeline()
+		commands = commands[:0]
+		for _, key := range keys {
+			commands = append(commands, pipeline.HGet(ctx, c.key(key), dataBin))
+		}
+
+		_, err := pipeline.Exec(ctx)
+		return err
+	})
+
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, err
+	}
+
+	var data []byte
+	for i, command := range commands {
+		value, err := command.Bytes()
+		if err != nil {
+			if errors.Is(err, goredis.Nil) && indexed[keys[i]] {
+				//indexed value expired or was evicted, warmup entries can't be matched anymore
+				_ = c.Delete(ctx, &cache.Entry{Id: markerKey})
+				return nil, nil
+			}
+			continue
+		}
+
+		lines := shared.SelectLines(value, matcher.Offset, matcher.Limit)
+		if len(lines) == 0 {
+			continue
+		}
+
+		if len(data) > 0 {
+			data = append(data, '\n')
+		}
+		data = append(data, lines...)
+	}
+
+	entry.Id = markerKey
+	if err = assignEntry(entry, meta, data); err != nil {
+		return nil, err
+	}
+
+	stats.Type = cache.TypeReadMulti
+	stats.FoundWarmup = true
+	stats.RecordsCounter = len(matcher.In)
+	stats.Key = markerKey
+	return entry, nil
+}
+
+func (c *Cache) matchingMeta(values map[string]string, SQL string, args []byte) (*cache.Meta, bool) {
+	metaValue, ok := values[metaBin]
+	if !ok {
+		return nil, false
+	}
+
+	meta := &cache.Meta{}
+	if err := json.Unmarshal([]byte(metaValue), meta); err != nil {
+		return nil, false
+	}
+
+	return meta, meta.SQL == SQL && string(meta.Args) == string(args)
+}
+
+func (c *Cache) newWriter(SQL string, args []interface{}, stats *cache.Stats) (*cache.Entry, error) {
+	argsMarshal, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hash.GenerateWithMarshal(SQL, "", "", argsMarshal)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if _, ok := c.writing[key]; ok {
+		return nil, nil
+	}
+
+	aBuffer := &buffer{}
+	c.writing[key] = aBuffer
+	entry := &cache.Entry{Id: key, Meta: cache.Meta{SQL: SQL, Args: argsMarshal}}
+	entry.SetWriter(cache.NewLineWriter(aBuffer), aBuffer)
+	stats.Type = cache.TypeWrite
+	return entry, nil
+}
+
+func (c *Cache) AssignRows(entry *cache.Entry, rows *sql.Rows) error {
+	return entry.AssignRows(rows)
+}
+
+func (c *Cache) UpdateType(ctx context.Context, entry *cache.Entry, args []interface{}) (bool, error) {
+	typeHolder := &cache.ScanTypeHolder{}
+	typeHolder.InitType(args)
+	if !typeHolder.Match(entry) {
+		return false, c.Delete(ctx, entry)
+	}
+
+	return true, nil
+}
+
+func (c *Cache) Close(ctx context.Context, entry *cache.Entry) error {
+	if entry.Has() {
+		return entry.Close()
+	}
+
+	if err := entry.Close(); err != nil {
+		_ = c.Delete(ctx, entry)
+		return err
+	}
+
+	c.mux.Lock()
+	aBuffer, ok := c.writing[entry.Id]
+	delete(c.writing, entry.Id)
+	c.mux.Unlock()
+	if !ok {
+		return nil
+	}
+
+	meta := entry.Meta
+	//failed write does not fail the read, entry is fetched from the database again and the failure is counted by the failure handler
+	_ = c.put(ctx, map[string]*hashValue{entry.Id: {meta: &meta, data: aBuffer.Bytes()}})
+	return nil
+}
+
+func (c *Cache) Delete(ctx context.Context, entry *cache.Entry) error {
+	c.mux.Lock()
+	delete(c.writing, entry.Id)
+	c.mux.Unlock()
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.Del(ctx, c.key(entry.Id)).Err()
+	})
+}
+
+func (c *Cache) Rollback(ctx context.Context, entry *cache.Entry) error {
+	return c.Delete(ctx, entry)
+}
+
+//IndexBy caches SQL result indexed by column values, indexed entries are matched with the cache.ParmetrizedQuery
+func (c *Cache) IndexBy(ctx context.Context, db *sql.DB, column, SQL string, args []interface{}) (int, error) {
+	if args == nil {
+		args = []interface{}{}
+	}
+
+	querySQL, ordered := shared.OrderedSQL(SQL, column)
+	rows, err := db.QueryContext(ctx, querySQL, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+
+	fields, err := cache.ColumnsToFields(io.TypesToColumns(columnTypes))
+	if err != nil {
+		return 0, err
+	}
+
+	values := make(chan *cache.Indexed, 512)
+	indexSource, err := aerospike.NewIndexSource(column, ordered, fields, values)
+	if err != nil {
+		return 0, err
+	}
+
+	argsMarshal, err := json.Marshal(args)
+	if err != nil {
+		return 0, err
+	}
+
+	URL, err := hash.GenerateWithMarshal(SQL, "", "", argsMarshal)
+	if err != nil {
+		return 0, err
+	}
+
+	var fetchErr error
+	go func() {
+		defer close(values)
+		placeholders := aerospike.NewPlaceholders(indexSource.ColumnIndex(), fields)
+		for rows.Next() {
+			if fetchErr = rows.Scan(placeholders.ScanPlaceholders()...); fetchErr != nil {
+				return
+			}
+
+			columnValue, ok := placeholders.ColumnValue()
+			if !ok {
+				continue
+			}
+
+			indexed := indexSource.Index(columnValue)
+			indexed.Column = column
+			if fetchErr = indexed.StringifyData(placeholders.Values()); fetchErr != nil {
+				return
+			}
+		}
+
+		fetchErr = indexSource.Close()
+	}()
+
+	meta := &cache.Meta{SQL: SQL, Args: argsMarshal, Fields: fields}
+	var indexedKeys []string
+	inserted := 0
+	batch := map[string]*hashValue{}
+	var indexErr error
+	for indexed := range values {
+		if (indexed.ColumnValue == nil && column != "") || indexErr != nil {
+			continue
+		}
+
+		if column == "" {
+			batch[URL] = &hashValue{meta: meta, data: indexed.Data.Bytes()}
+		} else {
+			valueMarshal, err := json.Marshal(indexed.ColumnValue)
+			if err != nil {
+				indexErr = err
+				continue
+			}
+			key := shared.ColumnValueURL(column, valueMarshal, URL)
+			batch[key] = &hashValue{data: indexed.Data.Bytes()}
+			indexedKeys = append(indexedKeys, key)
+		}
+
+		if len(batch) < indexBatchSize {
+			continue
+		}
+
+		if indexErr = c.put(ctx, batch); indexErr == nil {
+			inserted += len(batch)
+		}
+		batch = map[string]*hashValue{}
+	}
+
+	if fetchErr != nil {
+		return inserted, fetchErr
+	}
+
+	if indexErr != nil {
+		return inserted, indexErr
+	}
+
+	if column != "" {
+		batch[shared.ColumnURL(URL, column)] = &hashValue{meta: meta, indexed: indexedKeys}
+	}
+
+	if err = c.put(ctx, batch); err != nil {
+		return inserted, err
+	}
+
+	return inserted + len(batch), rows.Err()
+}
+
+func (c *Cache) put(ctx context.Context, values map[string]*hashValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	fields := make(map[string][]interface{}, len(values))
+	for key, value := range values {
+		var keyFields []interface{}
+		if value.meta != nil {
+			metaMarshal, err := json.Marshal(value.meta)
+			if err != nil {
+				return err
+			}
+			keyFields = append(keyFields, metaBin, metaMarshal)
+		}
+
+		if value.indexed != nil {
+			indexedMarshal, err := json.Marshal(value.indexed)
+			if err != nil {
+				return err
+			}
+			keyFields = append(keyFields, indexedBin, indexedMarshal)
+		}
+
+		fields[c.key(key)] = append(keyFields, dataBin, value.data)
+	}
+
+	return c.do(ctx, func(ctx context.Context) error {
+		_, err := c.client.TxPipelined(ctx, func(pipeline goredis.Pipeliner) error {
+			for key, keyFields := range fields {
+				pipeline.Del(ctx, key)
+				pipeline.HSet(ctx, key, keyFields...)
+				pipeline.PExpire(ctx, key, c.ttl)
+			}
+			return nil
+		})
+		return err
+	})
+}
+
+func (c *Cache) hGetAll(ctx context.Context, key string) (map[string]string, error) {
+	var values map[string]string
+	err := c.do(ctx, func(ctx context.Context) error {
+		var err error
+		values, err = c.client.HGetAll(ctx, c.key(key)).Result()
+		return err
+	})
+
+	return values, err
+}
+
+//do runs redis command, retrying failed command up to MaxRetries times
+func (c *Cache) do(ctx context.Context, command func(ctx context.Context) error) error {
+	var maxRetries int
+	var sleep, timeout time.Duration
+	if c.timeoutConfig != nil {
+		maxRetries = c.timeoutConfig.MaxRetries
+		sleep = time.Duration(c.timeoutConfig.SleepBetweenRetriesMs) * time.Millisecond
+		timeout = time.Duration(c.timeoutConfig.TotalTimeoutMs) * time.Millisecond
+	}
+
+	var err error
+	for i := 0; i <= maxRetries; i++ {
+		if i > 0 && sleep > 0 {
+			time.Sleep(sleep)
+		}
+
+		err = c.withTimeout(ctx, timeout, command)
+		if err == nil || errors.Is(err, goredis.Nil) {
+			c.handleSuccess()
+			return err
+		}
+	}
+
+	c.handleFailure()
+	return err
+}
+
+func (c *Cache) withTimeout(ctx context.Context, timeout time.Duration, command func(ctx context.Context) error) error {
+	if timeout == 0 {
+		return command(ctx)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return command(timeoutCtx)
+}
+
+func (c *Cache) handleSuccess() {
+	if c.failureHandler != nil {
+		c.failureHandler.HandleSuccess()
+	}
+}
+
+func (c *Cache) handleFailure() {
+	if c.failureHandler != nil {
+		c.failureHandler.HandleFailure()
+	}
+}
+
+func (c *Cache) key(key string) string {
+	if c.prefix == "" {
+		return key
+	}
+
+	return c.prefix + ":" + key
+}
+
+func assignEntry(entry *cache.Entry, meta *cache.Meta, data []byte) error {
+	entry.Meta.Type = meta.Type
+	entry.Meta.Fields = meta.Fields
+	for _, field := range entry.Meta.Fields {
+		if err := field.Init(); err != nil {
+			return err
+		}
+	}
+
+	entry.SetReader(bufio.NewReader(bytes.NewReader(data)), nopCloser{})
+	return nil
+}
+
+func (b *buffer) Flush() error {
+	return nil
+}
+
+func (b *buffer) Close() error {
+	return nil
+}
+
+func (n nopCloser) Close() error {
+	return nil
+}
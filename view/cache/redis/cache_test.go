@@ -0,0 +1,146 @@
+package redis
+
+import (
+	"context"
+	"database/sql"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/view/cache/shared"
+	"github.com/viant/sqlx/io/read"
+	"github.com/viant/sqlx/io/read/cache"
+	"github.com/viant/sqlx/io/read/cache/hash"
+	_ "github.com/viant/sqlx/metadata/product/sqlite"
+	"github.com/viant/sqlx/option"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCache_Get(t *testing.T) {
+	type Event struct {
+		ID     int    `sqlx:"ID"`
+		TypeID int    `sqlx:"TYPE_ID"`
+		Name   string `sqlx:"NAME"`
+	}
+
+	server, err := miniredis.Run()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer server.Close()
+
+	dsn := "/tmp/datly_redis_cache_test.db"
+	_ = os.Remove(dsn)
+	db, err := sql.Open("sqlite3", dsn)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	for _, SQL := range []string{
+		"CREATE TABLE EVENTS (ID INTEGER PRIMARY KEY, TYPE_ID INTEGER, NAME TEXT)",
+		"INSERT INTO EVENTS VALUES (1, 10, 'a'), (2, 10, 'b'), (3, 20, 'c')",
+	} {
+		if _, err = db.Exec(SQL); !assert.Nil(t, err) {
+			return
+		}
+	}
+
+	query := func(aCache cache.Cache, SQL string, matcher *cache.ParmetrizedQuery, args ...interface{}) ([]*Event, *cache.Stats) {
+		var events []*Event
+		stats := &cache.Stats{}
+		options := []option.Option{aCache, stats}
+		if matcher != nil {
+			options = append(options, matcher)
+		}
+
+		reader, err := read.New(context.Background(), db, SQL, func() interface{} { return &Event{} }, options...)
+		if !assert.Nil(t, err, SQL) {
+			return nil, stats
+		}
+
+		assert.Nil(t, reader.QueryAll(context.Background(), func(row interface{}) error {
+			events = append(events, row.(*Event))
+			return nil
+		}, args...), SQL)
+		return events, stats
+	}
+
+	useCases := []struct {
+		description string
+		run         func(aCache *Cache)
+	}{
+		{
+			description: "lazy entry is written then read",
+			run: func(aCache *Cache) {
+				SQL := "SELECT ID, TYPE_ID, NAME FROM EVENTS WHERE TYPE_ID = ?"
+				expect := []*Event{{ID: 1, TypeID: 10, Name: "a"}, {ID: 2, TypeID: 10, Name: "b"}}
+				events, stats := query(aCache, SQL, nil, 10)
+				assert.Equal(t, expect, events)
+				assert.EqualValues(t, cache.TypeWrite, stats.Type)
+
+				events, stats = query(aCache, SQL, nil, 10)
+				assert.Equal(t, expect, events)
+				assert.True(t, stats.FoundLazy)
+				assert.Equal(t, time.Minute, server.TTL("events:"+stats.Key))
+			},
+		},
+		{
+			description: "warmup indexed entries are matched",
+			run: func(aCache *Cache) {
+				SQL := "SELECT ID, TYPE_ID, NAME FROM EVENTS"
+				indexed, err := aCache.IndexBy(context.Background(), db, "TYPE_ID", SQL, nil)
+				assert.Nil(t, err)
+				assert.Equal(t, 3, indexed)
+
+				events, stats := query(aCache, SQL+" WHERE TYPE_ID IN (?, ?)", &cache.ParmetrizedQuery{By: "TYPE_ID", SQL: SQL, In: []interface{}{20, 10}}, 20, 10)
+				assert.True(t, stats.FoundWarmup)
+				assert.Equal(t, []*Event{{ID: 3, TypeID: 20, Name: "c"}, {ID: 1, TypeID: 10, Name: "a"}, {ID: 2, TypeID: 10, Name: "b"}}, events)
+			},
+		},
+		{
+			description: "warmup entries with expired value are not matched",
+			run: func(aCache *Cache) {
+				SQL := "SELECT ID, TYPE_ID, NAME FROM EVENTS"
+				indexed, err := aCache.IndexBy(context.Background(), db, "TYPE_ID", SQL, nil)
+				assert.Nil(t, err)
+				assert.Equal(t, 3, indexed)
+
+				URL, err := hash.GenerateWithMarshal(SQL, "", "", []byte("[]"))
+				assert.Nil(t, err)
+				server.Del("events:" + shared.ColumnValueURL("TYPE_ID", []byte("10"), URL))
+
+				events, stats := query(aCache, SQL+" WHERE TYPE_ID IN (?, ?)", &cache.ParmetrizedQuery{By: "TYPE_ID", SQL: SQL, In: []interface{}{30, 20}}, 30, 20)
+				assert.True(t, stats.FoundWarmup, "value without rows")
+				assert.Equal(t, []*Event{{ID: 3, TypeID: 20, Name: "c"}}, events)
+
+				events, stats = query(aCache, SQL+" WHERE TYPE_ID IN (?, ?)", &cache.ParmetrizedQuery{By: "TYPE_ID", SQL: SQL, In: []interface{}{20, 10}}, 20, 10)
+				assert.False(t, stats.FoundWarmup)
+				assert.Equal(t, 3, len(events))
+
+				_, stats = query(aCache, SQL+" WHERE TYPE_ID IN (?)", &cache.ParmetrizedQuery{By: "TYPE_ID", SQL: SQL, In: []interface{}{20}}, 20)
+				assert.False(t, stats.FoundWarmup, "marker was dropped")
+			},
+		},
+		{
+			description: "unavailable redis is handled as cache miss",
+			run: func(aCache *Cache) {
+				server.SetError("LOADING")
+				defer server.SetError("")
+				SQL := "SELECT ID, TYPE_ID, NAME FROM EVENTS WHERE TYPE_ID = ?"
+				events, stats := query(aCache, SQL, nil, 20)
+				assert.Equal(t, []*Event{{ID: 3, TypeID: 20, Name: "c"}}, events)
+				assert.Equal(t, ErrorTypeServerGeneric, stats.ErrorType)
+			},
+		},
+	}
+
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer client.Close()
+	for _, useCase := range useCases {
+		server.FlushAll()
+		useCase.run(New(client, "events", time.Minute))
+	}
+}
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"fmt"
+	goredis "github.com/go-redis/redis/v8"
+	"strings"
+	"sync"
+)
+
+//Scheme represents redis location scheme
+const Scheme = "redis"
+
+type clientRegistry struct {
+	mux   sync.Mutex
+	index map[string]*goredis.Client
+}
+
+var clients = &clientRegistry{index: map[string]*goredis.Client{}}
+
+//Client returns pooled client for the redis://[user:password@]host:port/db location
+func Client(URL string) (*goredis.Client, error) {
+	clientURL, _, err := Split(URL)
+	if err != nil {
+		return nil, err
+	}
+
+	clients.mux.Lock()
+	defer clients.mux.Unlock()
+	if client, ok := clients.index[clientURL]; ok {
+		return client, nil
+	}
+
+	options, err := goredis.ParseURL(clientURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client := goredis.NewClient(options)
+	clients.index[clientURL] = client
+	return client, nil
+}
+
+//Split splits redis://host:port/db/prefix location into client URL and keys prefix
+func Split(URL string) (string, string, error) {
+	if !strings.HasPrefix(URL, Scheme+"://") {
+		return "", "", fmt.Errorf("unsupported redis location %v, supported location format: redis://[user:password@]host:port[/db[/prefix]]", URL)
+	}
+
+	remaining := URL[len(Scheme+"://"):]
+	index := strings.Index(remaining, "/")
+	if index == -1 {
+		return URL, "", nil
+	}
+
+	path := remaining[index+1:]
+	db, prefix := path, ""
+	if slash := strings.Index(path, "/"); slash != -1 {
+		db, prefix = path[:slash], strings.Trim(path[slash+1:], "/")
+	}
+
+	return Scheme + "://" + remaining[:index+1] + db, prefix, nil
+}
+
+//ResetClients closes pooled clients
+func ResetClients() {
+	clients.mux.Lock()
+	defer clients.mux.Unlock()
+	for _, client := range clients.index {
+		_ = client.Close()
+	}
+
+	clients.index = map[string]*goredis.Client{}
+}
@@ -0,0 +1,75 @@
+package shared
+
+import (
+	"github.com/viant/sqlx/io/read/cache"
+	"strconv"
+	"strings"
+)
+
+//QueryOptions returns matcher and stats passed as the cache Get options, stats are created if not passed
+func QueryOptions(options []interface{}) (*cache.ParmetrizedQuery, *cache.Stats) {
+	var matcher *cache.ParmetrizedQuery
+	var stats *cache.Stats
+	for _, option := range options {
+		switch actual := option.(type) {
+		case *cache.ParmetrizedQuery:
+			matcher = actual
+		case *cache.Stats:
+			stats = actual
+		}
+	}
+
+	if stats == nil {
+		stats = &cache.Stats{}
+	}
+
+	stats.Init()
+	if matcher != nil {
+		matcher.Init()
+	}
+
+	return matcher, stats
+}
+
+//OrderedSQL returns SQL ordered by the indexed column, false if SQL is ordered by other column
+func OrderedSQL(SQL string, column string) (string, bool) {
+	if column == "" {
+		return SQL, false
+	}
+
+	lcSQL := strings.ToLower(SQL)
+	if index := strings.LastIndex(lcSQL, " order by "); index != -1 {
+		return SQL, strings.HasPrefix(strings.TrimSpace(lcSQL[index+len(" order by "):]), strings.ToLower(column))
+	}
+
+	return SQL + " ORDER BY " + column, true
+}
+
+//ColumnURL returns key of the index marker
+func ColumnURL(URL string, column string) string {
+	return strings.ToLower(column) + "#" + URL
+}
+
+//ColumnValueURL returns key of the indexed column value entry
+func ColumnValueURL(column string, valueMarshal []byte, URL string) string {
+	return strings.ToLower(column) + "#" + strconv.Quote(string(valueMarshal)) + "#" + URL
+}
+
+//SelectLines returns data lines, skipping offset lines and limiting result if limit was specified
+func SelectLines(data []byte, offset, limit int) []byte {
+	if offset == 0 && limit == 0 {
+		return data
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if offset >= len(lines) {
+		return nil
+	}
+
+	lines = lines[offset:]
+	if limit > 0 && limit < len(lines) {
+		lines = lines[:limit]
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,73 @@
+package shared
+
+import (
+	"context"
+	"github.com/viant/sqlx/io"
+	"github.com/viant/sqlx/io/read/cache"
+	"github.com/viant/xunsafe"
+)
+
+//Source represents cache entry source
+type Source struct {
+	cache         cache.Cache
+	entry         *cache.Entry
+	typeHolder    *cache.ScanTypeHolder
+	scanner       cache.ScannerFn
+	columnsHolder *cache.ColumnsHolder
+	xtypesHolder  *cache.XTypesHolder
+}
+
+//NewSource creates source of the cache entry
+func NewSource(service cache.Cache, entry *cache.Entry) *Source {
+	return &Source{cache: service, entry: entry}
+}
+
+func (s *Source) ConvertColumns() ([]io.Column, error) {
+	if s.columnsHolder == nil {
+		s.columnsHolder = cache.NewColumnsHolder(s.entry)
+	}
+
+	return s.columnsHolder.ConvertColumns()
+}
+
+func (s *Source) Scanner(ctx context.Context) cache.ScannerFn {
+	if s.scanner == nil {
+		s.scanner = cache.NewScanner(s.typeHolder, nil).New(s.entry)
+	}
+
+	return s.scanner
+}
+
+func (s *Source) XTypes() []*xunsafe.Type {
+	if s.xtypesHolder == nil {
+		s.xtypesHolder = cache.NewXTypeHolder(s.entry)
+	}
+
+	return s.xtypesHolder.XTypes()
+}
+
+//CheckType checks if cached entry types match scanned values, entry types are tracked per source as entries of different queries share the cache
+func (s *Source) CheckType(ctx context.Context, values []interface{}) (bool, error) {
+	if s.typeHolder == nil {
+		s.typeHolder = &cache.ScanTypeHolder{}
+		s.typeHolder.InitType(values)
+	}
+
+	if !s.typeHolder.Match(s.entry) {
+		return false, s.cache.Delete(ctx, s.entry)
+	}
+
+	return true, nil
+}
+
+func (s *Source) Close(ctx context.Context) error {
+	return s.cache.Close(ctx, s.entry)
+}
+
+func (s *Source) Next() bool {
+	return s.entry.Next()
+}
+
+func (s *Source) Rollback(ctx context.Context) error {
+	return s.cache.Delete(ctx, s.entry)
+}
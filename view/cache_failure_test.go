@@ -0,0 +1,30 @@
+package view
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCache_FailureHandler(t *testing.T) {
+	useCases := []struct {
+		description   string
+		resetMs       int
+		wait          time.Duration
+		expectProbing bool
+	}{
+		{description: "probing within reset window", resetMs: 200, wait: 20 * time.Millisecond, expectProbing: true},
+		{description: "probing reset after window", resetMs: 20, wait: 200 * time.Millisecond},
+		{description: "probing without reset", wait: 20 * time.Millisecond},
+	}
+
+	for _, useCase := range useCases {
+		aCache := &Cache{AerospikeConfig: AerospikeConfig{FailedRequestLimit: 1, ResetFailuresInMs: useCase.resetMs}}
+		handler := aCache.failureHandler()
+		handler.HandleFailure()
+		handler.HandleFailure()
+		time.Sleep(useCase.wait)
+		assert.Equal(t, useCase.expectProbing, handler.IsProbing(), useCase.description)
+		_ = handler.Close()
+	}
+}
@@ -0,0 +1,59 @@
+package view
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/viant/sqlx/io/read/cache"
+)
+
+//ReadCacheRecord reads single row record stored with WriteCacheRecord into dest, returns false if record was not found
+func ReadCacheRecord(ctx context.Context, service cache.Cache, SQL string, args []interface{}, dest interface{}) (bool, error) {
+	entry, err := service.Get(ctx, SQL, args)
+	if err != nil || entry == nil {
+		return false, err
+	}
+
+	if !entry.Has() {
+		return false, service.Rollback(ctx, entry)
+	}
+
+	found := entry.Next()
+	if found {
+		err = json.Unmarshal(entry.Data, dest)
+	}
+
+	if closeErr := service.Close(ctx, entry); err == nil {
+		err = closeErr
+	}
+
+	return found && err == nil, err
+}
+
+//WriteCacheRecord replaces single row record stored in the cache service with the values, record expires with the cache service entries
+func WriteCacheRecord(ctx context.Context, service cache.Cache, SQL string, args []interface{}, values []interface{}) error {
+	entry, err := service.Get(ctx, SQL, args)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	if entry.Has() {
+		if err = service.Close(ctx, entry); err != nil {
+			return err
+		}
+
+		if err = service.Delete(ctx, entry); err != nil {
+			return err
+		}
+
+		if entry, err = service.Get(ctx, SQL, args); err != nil || entry == nil {
+			return err
+		}
+	}
+
+	if err = service.AddValues(ctx, entry, values); err != nil {
+		_ = service.Rollback(ctx, entry)
+		return err
+	}
+
+	return service.Close(ctx, entry)
+}
@@ -0,0 +1,219 @@
+package view
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//CacheScope represents who cached data can be shared with
+type CacheScope string
+
+const (
+	//CacheScopePublic cached data is shared with all callers
+	CacheScopePublic CacheScope = "public"
+	//CacheScopeTenant cached data is shared within caller tenant
+	CacheScopeTenant CacheScope = "tenant"
+	//CacheScopeUser cached data is shared with the same caller only
+	CacheScopeUser CacheScope = "user"
+)
+
+//PrincipalCodecs represents codecs deriving parameter value from the request principal
+var PrincipalCodecs = map[string]bool{
+	"JwtClaim":        true,
+	"CognitoJwtClaim": true,
+	"CertClaim":       true,
+}
+
+//PrincipalHeaders represents headers identifying the request principal
+var PrincipalHeaders = map[string]bool{
+	"authorization":     true,
+	"datly-client-cert": true,
+}
+
+var userClaims = "UserID|User_ID|Email|Username|FirstName|First_Name|LastName|Last_Name|Subject|Sub|CommonName|Fingerprint"
+
+//Validate checks if CacheScope is valid
+func (s CacheScope) Validate() error {
+	switch s {
+	case CacheScopePublic, CacheScopeTenant, CacheScopeUser:
+		return nil
+	}
+
+	return fmt.Errorf("unsupported cache scope %v", s)
+}
+
+//IsPrincipal returns true if parameter value is derived from the request principal
+func (p *Parameter) IsPrincipal() bool {
+	if p.Output != nil && PrincipalCodecs[p.Output.Name] {
+		return true
+	}
+
+	if p.In == nil {
+		return false
+	}
+
+	switch p.In.Kind {
+	case KindCookie:
+		return true
+	case KindHeader:
+		return PrincipalHeaders[strings.ToLower(p.In.Name)]
+	}
+
+	return false
+}
+
+//PrincipalParameters returns parameters derived from the request principal used by the view, its relations and data view parameters
+func (v *View) PrincipalParameters() []*Parameter {
+	var result []*Parameter
+	v.appendPrincipalParameters(&result, map[*View]bool{}, true)
+	return result
+}
+
+func (v *View) appendPrincipalParameters(result *[]*Parameter, visited map[*View]bool, withRelations bool) {
+	if v == nil || visited[v] {
+		return
+	}
+	visited[v] = true
+
+	if v.Template != nil {
+		for _, parameter := range v.Template.Parameters {
+			if parameter.IsPrincipal() {
+				*result = append(*result, parameter)
+			}
+
+			if parameter.In != nil && parameter.In.Kind == KindDataView {
+				parameter.view.appendPrincipalParameters(result, visited, true)
+			}
+		}
+	}
+
+	if !withRelations {
+		return
+	}
+
+	for _, relation := range v.With {
+		relation.Of.View.appendPrincipalParameters(result, visited, true)
+	}
+}
+
+//DefaultCacheScope returns user scope if view uses principal parameters, public otherwise
+func (v *View) DefaultCacheScope() CacheScope {
+	if len(v.PrincipalParameters()) > 0 {
+		return CacheScopeUser
+	}
+
+	return CacheScopePublic
+}
+
+//ValidateCacheScope refuses cache scopes that would share principal specific data of the view or its relations across principals
+func (v *View) ValidateCacheScope(scope CacheScope) error {
+	return v.validateCacheScope(scope, v.PrincipalParameters(), true)
+}
+
+func (v *View) validateCacheScope(scope CacheScope, principals []*Parameter, withRelations bool) error {
+	if err := scope.Validate(); err != nil {
+		return err
+	}
+
+	switch scope {
+	case CacheScopePublic:
+		if len(principals) > 0 {
+			return fmt.Errorf("view %v cache scope %v can't be used with principal parameter %v, use %v or %v scope", v.Name, scope, principals[0].Name, CacheScopeTenant, CacheScopeUser)
+		}
+	case CacheScopeTenant:
+		for _, parameter := range principals {
+			if parameter.Output == nil || !PrincipalCodecs[parameter.Output.Name] {
+				return fmt.Errorf("view %v cache scope %v can't be used with raw principal parameter %v, use %v scope", v.Name, scope, parameter.Name, CacheScopeUser)
+			}
+
+			if aView, ok := v.usesUserClaim(parameter, map[*View]bool{}, withRelations); ok {
+				return fmt.Errorf("view %v cache scope %v can't be used as view %v uses user specific %v claim, use %v scope", v.Name, scope, aView.Name, parameter.Name, CacheScopeUser)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *View) usesUserClaim(parameter *Parameter, visited map[*View]bool, withRelations bool) (*View, bool) {
+	if v == nil || visited[v] {
+		return nil, false
+	}
+	visited[v] = true
+
+	expr := regexp.MustCompile(`(?i)\$\{?` + regexp.QuoteMeta(parameter.Name) + `\.(` + userClaims + `)\b`)
+	if v.Template != nil && expr.MatchString(v.Template.Source) {
+		return v, true
+	}
+
+	pathExpr := regexp.MustCompile(`(?i)^` + regexp.QuoteMeta(parameter.Name) + `\.(` + userClaims + `)$`)
+	for _, filter := range v.RowFilters {
+		if pathExpr.MatchString(filter.Param) {
+			return v, true
+		}
+	}
+
+	if v.Template != nil {
+		for _, candidate := range v.Template.Parameters {
+			if candidate.In != nil && candidate.In.Kind == KindDataView {
+				if aView, ok := candidate.view.usesUserClaim(parameter, visited, true); ok {
+					return aView, ok
+				}
+			}
+		}
+	}
+
+	if !withRelations {
+		return nil, false
+	}
+
+	for _, relation := range v.With {
+		if aView, ok := relation.Of.View.usesUserClaim(parameter, visited, true); ok {
+			return aView, ok
+		}
+	}
+
+	return nil, false
+}
+
+//CacheKeyValues returns values of all view template parameters, including unexported and principal parameters
+func (v *View) CacheKeyValues(selector *Selector) (map[string]interface{}, error) {
+	if v.Template == nil || selector == nil || selector.Parameters.Values == nil {
+		return nil, nil
+	}
+
+	result := make(map[string]interface{}, len(v.Template.Parameters))
+	for _, parameter := range v.Template.Parameters {
+		value, err := parameter.Value(selector.Parameters.Values)
+		if err != nil {
+			return nil, err
+		}
+
+		result[parameter.Name] = value
+	}
+
+	return result, nil
+}
+
+//initScope validates view cache scope, view cache entries are keyed by SQL and bound arguments, thus non public scope requires principal parameters
+func (c *Cache) initScope(aView *View) error {
+	var principals []*Parameter
+	aView.appendPrincipalParameters(&principals, map[*View]bool{}, false)
+	if c.Scope == "" {
+		c.Scope = CacheScopePublic
+		if len(principals) > 0 {
+			c.Scope = CacheScopeUser
+		}
+	}
+
+	if err := aView.validateCacheScope(c.Scope, principals, false); err != nil {
+		return err
+	}
+
+	if c.Scope != CacheScopePublic && len(principals) == 0 {
+		return fmt.Errorf("view %v cache scope %v requires view template to use principal parameter", aView.Name, c.Scope)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package view
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestView_ValidateCacheScope(t *testing.T) {
+	jwt := func() *Parameter {
+		return &Parameter{Name: "Jwt", In: &Location{Kind: KindHeader, Name: "Authorization"}, Output: &Codec{Name: "JwtClaim"}}
+	}
+	lang := &Parameter{Name: "Lang", In: &Location{Kind: KindHeader, Name: "Accept-Language"}}
+
+	useCases := []struct {
+		description  string
+		view         *View
+		scope        CacheScope
+		expectErr    bool
+		expectScope  CacheScope
+		expectParams int
+	}{
+		{
+			description: "public view",
+			view:        &View{Name: "events", Template: &Template{Source: "SELECT * FROM EVENTS", Parameters: []*Parameter{lang}}},
+			scope:       CacheScopePublic,
+			expectScope: CacheScopePublic,
+		},
+		{
+			description:  "public scope with jwt parameter",
+			view:         &View{Name: "events", Template: &Template{Source: "SELECT * FROM EVENTS WHERE ACCOUNT_ID = $Jwt.AccountId", Parameters: []*Parameter{jwt()}}},
+			scope:        CacheScopePublic,
+			expectErr:    true,
+			expectScope:  CacheScopeUser,
+			expectParams: 1,
+		},
+		{
+			description:  "tenant scope with tenant claim",
+			view:         &View{Name: "events", Template: &Template{Source: "SELECT * FROM EVENTS WHERE ACCOUNT_ID = $Jwt.AccountId", Parameters: []*Parameter{jwt()}}},
+			scope:        CacheScopeTenant,
+			expectScope:  CacheScopeUser,
+			expectParams: 1,
+		},
+		{
+			description:  "tenant scope with user claim",
+			view:         &View{Name: "events", Template: &Template{Source: "SELECT * FROM EVENTS WHERE USER_ID = ${Jwt.UserID}", Parameters: []*Parameter{jwt()}}},
+			scope:        CacheScopeTenant,
+			expectErr:    true,
+			expectScope:  CacheScopeUser,
+			expectParams: 1,
+		},
+		{
+			description: "tenant scope with relation row filter on user claim",
+			view: &View{Name: "accounts", Template: &Template{Source: "SELECT * FROM ACCOUNTS"}, With: []*Relation{{Of: &ReferenceView{View: View{
+				Name:       "events",
+				Template:   &Template{Source: "SELECT * FROM EVENTS", Parameters: []*Parameter{jwt()}},
+				RowFilters: RowFilters{{Column: "USER_ID", Param: "Jwt.UserID"}},
+			}}}}},
+			scope:        CacheScopeTenant,
+			expectErr:    true,
+			expectScope:  CacheScopeUser,
+			expectParams: 1,
+		},
+		{
+			description:  "user scope with raw authorization header",
+			view:         &View{Name: "events", Template: &Template{Source: "SELECT * FROM EVENTS", Parameters: []*Parameter{{Name: "Token", In: &Location{Kind: KindHeader, Name: "Authorization"}}}}},
+			scope:        CacheScopeUser,
+			expectScope:  CacheScopeUser,
+			expectParams: 1,
+		},
+	}
+
+	for _, useCase := range useCases {
+		err := useCase.view.ValidateCacheScope(useCase.scope)
+		assert.Equal(t, useCase.expectErr, err != nil, useCase.description)
+		assert.Equal(t, useCase.expectScope, useCase.view.DefaultCacheScope(), useCase.description)
+		assert.Equal(t, useCase.expectParams, len(useCase.view.PrincipalParameters()), useCase.description)
+	}
+}
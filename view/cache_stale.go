@@ -0,0 +1,257 @@
+package view
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/viant/sqlx/io/read/cache"
+	"reflect"
+	"sync"
+	"time"
+)
+
+type (
+	//revalidation tracks cache entries being refreshed, entries are stored for TimeToLiveMs + StaleWhileRevalidateMs,
+	//once TimeToLiveMs elapsed entry is served as stale while a single background refresh runs.
+	//Entry write time is stored in the cache service next to the entry, thus it is shared and expires with the entry
+	revalidation struct {
+		mux        sync.Mutex
+		refreshing map[string]bool
+	}
+
+	//Revalidation represents stale while revalidate state of a single view read
+	Revalidation struct {
+		Stale bool
+		key   string
+		cache *Cache
+	}
+
+	//staleCache detects stale entries, in refresh mode refreshed rows are buffered and swapped with the stale entry once read,
+	//thus the stale entry is served until the refresh completes
+	staleCache struct {
+		cache.Cache
+		owner        *Cache
+		revalidation *Revalidation
+		refresh      bool
+		key          string
+		SQL          string
+		args         []interface{}
+		stale        *cache.Entry
+		rows         [][]interface{}
+	}
+)
+
+//writtenSQLPrefix prefixes SQL of the cache entries storing write time of the actual entries
+const writtenSQLPrefix = "/* written */ "
+
+func newRevalidation() *revalidation {
+	return &revalidation{refreshing: map[string]bool{}}
+}
+
+//StaleWhileRevalidate returns stale window, entries older than TimeToLiveMs are served within the window while being refreshed
+func (c *Cache) StaleWhileRevalidate() time.Duration {
+	return time.Duration(c.StaleWhileRevalidateMs) * time.Millisecond
+}
+
+func (c *Cache) storageTTL() time.Duration {
+	return time.Duration(c.TimeToLiveMs)*time.Millisecond + c.StaleWhileRevalidate()
+}
+
+//RevalidatingService returns cache service detecting stale entries, service is returned unchanged if stale window is not configured
+func (c *Cache) RevalidatingService(service cache.Cache, revalidation *Revalidation) cache.Cache {
+	if c.StaleWhileRevalidateMs == 0 || service == nil {
+		return service
+	}
+
+	revalidation.cache = c
+	return &staleCache{Cache: service, owner: c, revalidation: revalidation}
+}
+
+//BeginRefresh returns cache service refreshing stale entry, false if entry is not stale or is already being refreshed
+func (r *Revalidation) BeginRefresh(service cache.Cache) (cache.Cache, bool) {
+	if !r.Stale || r.cache == nil {
+		return nil, false
+	}
+
+	state := r.cache.revalidation
+	state.mux.Lock()
+	defer state.mux.Unlock()
+	if state.refreshing[r.key] {
+		return nil, false
+	}
+
+	state.refreshing[r.key] = true
+	return &staleCache{Cache: service, owner: r.cache, refresh: true, key: r.key}, true
+}
+
+//EndRefresh releases stale entry refresh
+func (r *Revalidation) EndRefresh() {
+	state := r.cache.revalidation
+	state.mux.Lock()
+	defer state.mux.Unlock()
+	delete(state.refreshing, r.key)
+}
+
+func (c *staleCache) Get(ctx context.Context, SQL string, args []interface{}, options ...interface{}) (*cache.Entry, error) {
+	if c.key == "" {
+		argsJSON, err := json.Marshal(args)
+		if err != nil {
+			return nil, err
+		}
+
+		c.key = SQL + string(argsJSON)
+	}
+	c.SQL, c.args = SQL, args
+
+	entry, err := c.Cache.Get(ctx, SQL, args, options...)
+	if err != nil || entry == nil || !entry.Has() {
+		return entry, err
+	}
+
+	if !c.refresh {
+		stale, err := c.isStale(ctx, time.Now())
+		if err != nil {
+			_ = c.Cache.Close(ctx, entry)
+			return nil, err
+		}
+
+		if stale {
+			c.revalidation.Stale = true
+			c.revalidation.key = c.key
+		}
+
+		return entry, nil
+	}
+
+	if err = c.Cache.Close(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	c.stale = entry
+	return &cache.Entry{Id: entry.Id, Meta: cache.Meta{SQL: entry.Meta.SQL, Args: entry.Meta.Args}}, nil
+}
+
+func (c *staleCache) AssignRows(entry *cache.Entry, rows *sql.Rows) error {
+	if c.stale == nil {
+		return c.Cache.AssignRows(entry, rows)
+	}
+
+	return entry.AssignRows(rows)
+}
+
+func (c *staleCache) UpdateType(ctx context.Context, entry *cache.Entry, args []interface{}) (bool, error) {
+	if c.stale == nil {
+		return c.Cache.UpdateType(ctx, entry, args)
+	}
+
+	typeHolder := &cache.ScanTypeHolder{}
+	typeHolder.InitType(args)
+	return typeHolder.Match(entry), nil
+}
+
+func (c *staleCache) AddValues(ctx context.Context, entry *cache.Entry, values []interface{}) error {
+	if c.stale == nil {
+		return c.Cache.AddValues(ctx, entry, values)
+	}
+
+	c.rows = append(c.rows, copyValues(values))
+	return nil
+}
+
+func (c *staleCache) Rollback(ctx context.Context, entry *cache.Entry) error {
+	if c.stale == nil {
+		return c.Cache.Rollback(ctx, entry)
+	}
+
+	c.rows = nil
+	return nil
+}
+
+func (c *staleCache) Close(ctx context.Context, entry *cache.Entry) error {
+	if c.stale != nil {
+		return c.swap(ctx, entry)
+	}
+
+	written := !entry.Has()
+	if err := c.Cache.Close(ctx, entry); err != nil || !written {
+		return err
+	}
+
+	return c.markWritten(ctx, time.Now())
+}
+
+//swap replaces stale entry with the refreshed rows, cache services create entry writer only if entry is missing,
+//thus stale entry is deleted right before the buffered rows are written
+func (c *staleCache) swap(ctx context.Context, refreshed *cache.Entry) error {
+	rows := c.rows
+	c.rows = nil
+	if err := c.Cache.Delete(ctx, c.stale); err != nil {
+		return err
+	}
+
+	entry, err := c.Cache.Get(ctx, c.SQL, c.args)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	if entry.Has() {
+		return c.Cache.Close(ctx, entry)
+	}
+
+	entry.Meta.Fields = refreshed.Meta.Fields
+	for _, values := range rows {
+		ok, err := c.Cache.UpdateType(ctx, entry, values)
+		if err != nil || !ok {
+			return err
+		}
+
+		if err = c.Cache.AddValues(ctx, entry, values); err != nil {
+			_ = c.Cache.Rollback(ctx, entry)
+			return err
+		}
+	}
+
+	if err = c.Cache.Close(ctx, entry); err != nil {
+		return err
+	}
+
+	return c.markWritten(ctx, time.Now())
+}
+
+//isStale returns true if entry was written more than TimeToLiveMs ago, entries without write time are fresh
+func (c *staleCache) isStale(ctx context.Context, now time.Time) (bool, error) {
+	var written []int64
+	found, err := ReadCacheRecord(ctx, c.Cache, writtenSQLPrefix+c.SQL, c.args, &written)
+	if err != nil || !found || len(written) == 0 {
+		return false, err
+	}
+
+	return now.After(time.Unix(0, written[0]).Add(time.Duration(c.owner.TimeToLiveMs) * time.Millisecond)), nil
+}
+
+//markWritten stores entry write time in the cache service
+func (c *staleCache) markWritten(ctx context.Context, now time.Time) error {
+	return WriteCacheRecord(ctx, c.Cache, writtenSQLPrefix+c.SQL, c.args, []interface{}{now.UnixNano()})
+}
+
+//copyValues copies scanned values, scan placeholders are reused between rows
+func copyValues(values []interface{}) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		rValue := reflect.ValueOf(value)
+		if rValue.Kind() != reflect.Ptr || rValue.IsNil() {
+			result[i] = value
+			continue
+		}
+
+		copied := reflect.New(rValue.Type().Elem())
+		copied.Elem().Set(rValue.Elem())
+		if elem := copied.Elem(); elem.Kind() == reflect.Slice && elem.Type().Elem().Kind() == reflect.Uint8 {
+			elem.SetBytes(append([]byte{}, elem.Bytes()...))
+		}
+
+		result[i] = copied.Interface()
+	}
+
+	return result
+}
@@ -0,0 +1,122 @@
+package view
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/view/cache/mem"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache_RevalidatingService(t *testing.T) {
+	ctx := context.Background()
+	writer := &Cache{TimeToLiveMs: 50, StaleWhileRevalidateMs: 1000, revalidation: newRevalidation()}
+	reader := &Cache{TimeToLiveMs: 50, StaleWhileRevalidateMs: 1000, revalidation: newRevalidation()}
+	service := mem.New(writer.storageTTL(), 0, 0)
+
+	aService := writer.RevalidatingService(service, &Revalidation{})
+	entry, err := aService.Get(ctx, "SELECT * FROM EVENTS", []interface{}{1})
+	if !assert.Nil(t, err) || !assert.False(t, entry.Has()) {
+		return
+	}
+
+	assert.Nil(t, aService.AddValues(ctx, entry, []interface{}{1, "abc"}))
+	assert.Nil(t, aService.Close(ctx, entry))
+
+	useCases := []struct {
+		description string
+		owner       *Cache
+		wait        time.Duration
+		expectStale bool
+	}{
+		{description: "fresh entry", owner: writer},
+		{description: "fresh entry read by other instance", owner: reader},
+		{description: "stale entry", owner: writer, wait: 60 * time.Millisecond, expectStale: true},
+		{description: "stale entry read by other instance", owner: reader, expectStale: true},
+	}
+
+	for _, useCase := range useCases {
+		time.Sleep(useCase.wait)
+		revalidation := &Revalidation{}
+		aService := useCase.owner.RevalidatingService(service, revalidation)
+		entry, err := aService.Get(ctx, "SELECT * FROM EVENTS", []interface{}{1})
+		if !assert.Nil(t, err, useCase.description) || !assert.True(t, entry.Has(), useCase.description) {
+			continue
+		}
+
+		assert.Nil(t, aService.Close(ctx, entry), useCase.description)
+		assert.Equal(t, useCase.expectStale, revalidation.Stale, useCase.description)
+	}
+}
+
+func TestRevalidation_BeginRefresh(t *testing.T) {
+	ctx := context.Background()
+	SQL, args := "SELECT * FROM EVENTS", []interface{}{1}
+	owner := &Cache{TimeToLiveMs: 50, StaleWhileRevalidateMs: 1000, revalidation: newRevalidation()}
+	service := mem.New(owner.storageTTL(), 0, 0)
+
+	read := func() string {
+		entry, err := service.Get(ctx, SQL, args)
+		if !assert.Nil(t, err) || !entry.Has() {
+			return ""
+		}
+		defer service.Close(ctx, entry)
+
+		var lines []string
+		for entry.Next() {
+			lines = append(lines, string(entry.Data))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	aService := owner.RevalidatingService(service, &Revalidation{})
+	entry, err := aService.Get(ctx, SQL, args)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, aService.AddValues(ctx, entry, []interface{}{1, "abc"}))
+	assert.Nil(t, aService.Close(ctx, entry))
+
+	time.Sleep(60 * time.Millisecond)
+	revalidation := &Revalidation{}
+	aService = owner.RevalidatingService(service, revalidation)
+	entry, err = aService.Get(ctx, SQL, args)
+	if !assert.Nil(t, err) || !assert.True(t, revalidation.Stale) {
+		return
+	}
+	assert.Nil(t, aService.Close(ctx, entry))
+
+	refreshService, ok := revalidation.BeginRefresh(service)
+	if !assert.True(t, ok) {
+		return
+	}
+	defer revalidation.EndRefresh()
+
+	_, ok = revalidation.BeginRefresh(service)
+	assert.False(t, ok, "single refresh of the stale entry")
+
+	refreshed, err := refreshService.Get(ctx, SQL, args)
+	if !assert.Nil(t, err) || !assert.False(t, refreshed.Has()) {
+		return
+	}
+
+	ID, name := 2, "xyz"
+	values := []interface{}{&ID, &name}
+	typeMatches, err := refreshService.UpdateType(ctx, refreshed, values)
+	assert.Nil(t, err)
+	assert.True(t, typeMatches)
+	assert.Nil(t, refreshService.AddValues(ctx, refreshed, values))
+	ID, name = 3, "reused"
+	assert.Equal(t, `[1,"abc"]`, read(), "stale entry is served while refreshing")
+
+	assert.Nil(t, refreshService.Close(ctx, refreshed))
+	assert.Equal(t, `[2,"xyz"]`, read(), "stale entry is replaced with refreshed rows")
+
+	revalidation = &Revalidation{}
+	entry, err = owner.RevalidatingService(service, revalidation).Get(ctx, SQL, args)
+	if assert.Nil(t, err) && assert.True(t, entry.Has()) {
+		assert.Nil(t, service.Close(ctx, entry))
+	}
+	assert.False(t, revalidation.Stale, "refreshed entry is fresh")
+}
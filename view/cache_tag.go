@@ -0,0 +1,297 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"github.com/viant/sqlx/io/read/cache"
+	"github.com/viant/toolbox"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	//TagView represents view cache tag kind
+	TagView = "view"
+	//TagTable represents table cache tag kind
+	TagTable = "table"
+	//TagRoute represents route cache tag kind
+	TagRoute = "route"
+)
+
+var (
+	tableExpr    = regexp.MustCompile(`(?i)\b(?:FROM|JOIN|INTO|UPDATE)\s+([a-zA-Z_][\w.]*)`)
+	tagParamExpr = regexp.MustCompile(`\$\{([\w.]+)}`)
+)
+
+type (
+	//TagGenerations represents cache tag generations, purging a tag makes entries cached with the tag unreachable, they expire with their TimeToLiveMs.
+	//Generations are kept in process memory unless TagStore is used, then they are shared and refreshed every refresh interval
+	TagGenerations struct {
+		mux         sync.RWMutex
+		generations map[string]uint64
+		store       TagStore
+		interval    time.Duration
+		refreshed   int64
+		refreshing  int32
+	}
+
+	//TagStore represents storage sharing cache tag generations between datly instances
+	TagStore interface {
+		Load(ctx context.Context) (map[string]uint64, error)
+		Increment(ctx context.Context, tags []string) error
+	}
+
+	//taggedCache appends tags generation to the cache entry arguments
+	taggedCache struct {
+		cache.Cache
+		generation string
+	}
+)
+
+//CacheTags represents process wide cache tag generations
+var CacheTags = NewTagGenerations()
+
+//NewTagGenerations creates tag generations
+func NewTagGenerations() *TagGenerations {
+	return &TagGenerations{generations: map[string]uint64{}}
+}
+
+//CacheTag returns cache tag of the given kind
+func CacheTag(kind, value string) string {
+	return kind + ":" + value
+}
+
+//UseStore shares generations using the store, generations purged by other instances are visible after the refresh interval
+func (t *TagGenerations) UseStore(ctx context.Context, store TagStore, interval time.Duration) error {
+	t.mux.Lock()
+	t.store = store
+	t.interval = interval
+	t.mux.Unlock()
+	return t.refresh(ctx)
+}
+
+//Purge invalidates entries cached with any of the tags
+func (t *TagGenerations) Purge(ctx context.Context, tags ...string) error {
+	t.mux.Lock()
+	for _, tag := range tags {
+		t.generations[tag]++
+	}
+	store := t.store
+	t.mux.Unlock()
+
+	if store == nil {
+		return nil
+	}
+
+	if err := store.Increment(ctx, tags); err != nil {
+		return fmt.Errorf("failed to purge cache tags %v: %w", tags, err)
+	}
+
+	return t.refresh(ctx)
+}
+
+func (t *TagGenerations) refresh(ctx context.Context) error {
+	t.mux.RLock()
+	store := t.store
+	t.mux.RUnlock()
+	if store == nil {
+		return nil
+	}
+
+	generations, err := store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load cache tags: %w", err)
+	}
+
+	t.mux.Lock()
+	t.generations = generations
+	t.mux.Unlock()
+	atomic.StoreInt64(&t.refreshed, time.Now().UnixNano())
+	return nil
+}
+
+//refreshIfNeeded loads generations purged by other instances in the background once per refresh interval
+func (t *TagGenerations) refreshIfNeeded() {
+	t.mux.RLock()
+	store, interval := t.store, t.interval
+	t.mux.RUnlock()
+	if store == nil || time.Since(time.Unix(0, atomic.LoadInt64(&t.refreshed))) < interval {
+		return
+	}
+
+	if !atomic.CompareAndSwapInt32(&t.refreshing, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&t.refreshing, 0)
+		if err := t.refresh(context.Background()); err != nil {
+			fmt.Printf("[WARN] %v\n", err.Error())
+		}
+		atomic.StoreInt64(&t.refreshed, time.Now().UnixNano())
+	}()
+}
+
+//Generation returns tags generation, empty if none of the tags was purged
+func (t *TagGenerations) Generation(tags []string) string {
+	t.refreshIfNeeded()
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	if len(t.generations) == 0 {
+		return ""
+	}
+
+	var result []string
+	for _, tag := range tags {
+		if generation, ok := t.generations[tag]; ok {
+			result = append(result, tag+"="+strconv.FormatUint(generation, 10))
+		}
+	}
+
+	sort.Strings(result)
+	return strings.Join(result, ",")
+}
+
+//Snapshot returns purged tags generations
+func (t *TagGenerations) Snapshot() map[string]uint64 {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	result := make(map[string]uint64, len(t.generations))
+	for tag, generation := range t.generations {
+		result[tag] = generation
+	}
+
+	return result
+}
+
+func (c *taggedCache) Get(ctx context.Context, SQL string, args []interface{}, options ...interface{}) (*cache.Entry, error) {
+	return c.Cache.Get(ctx, SQL, append(args[:len(args):len(args)], c.generation), options...)
+}
+
+//TaggedService returns cache service honoring purged view cache tags. Entries indexed by the warmup are keyed without generation,
+//thus entries matched by the warmup index are refreshed with the next warmup
+func (c *Cache) TaggedService(selector *Selector) (cache.Cache, error) {
+	service, err := c.Service()
+	if err != nil || service == nil || c.owner == nil {
+		return service, err
+	}
+
+	tags, err := c.owner.CacheTags(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	generation := CacheTags.Generation(tags)
+	if generation == "" {
+		return service, nil
+	}
+
+	return &taggedCache{Cache: service, generation: generation}, nil
+}
+
+//CacheTags returns view cache tags: view name, table names used by the view SQL and view cache custom tags
+func (v *View) CacheTags(selector *Selector) ([]string, error) {
+	tags := []string{CacheTag(TagView, v.Name)}
+	for _, table := range v.TableNames() {
+		tags = append(tags, CacheTag(TagTable, table))
+	}
+
+	if v.Cache == nil || len(v.Cache.Tags) == 0 {
+		return tags, nil
+	}
+
+	custom, err := v.ExpandCacheTags(v.Cache.Tags, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(tags, custom...), nil
+}
+
+//TableNames returns upper case table names used by the view
+func (v *View) TableNames() []string {
+	var result []string
+	index := map[string]bool{}
+	add := func(table string) {
+		table = strings.ToUpper(table)
+		if table == "" || index[table] {
+			return
+		}
+		index[table] = true
+		result = append(result, table)
+	}
+
+	add(v.Table)
+	for _, SQL := range []string{v.From, v.templateSource()} {
+		for _, match := range tableExpr.FindAllStringSubmatch(SQL, -1) {
+			add(match[1])
+		}
+	}
+
+	return result
+}
+
+func (v *View) templateSource() string {
+	if v.Template == nil {
+		return ""
+	}
+
+	return v.Template.Source
+}
+
+//ExpandCacheTags expands ${Param.Path} cache tag placeholders with the selector parameter values
+func (v *View) ExpandCacheTags(tags []string, selector *Selector) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		var expandErr error
+		expanded := tagParamExpr.ReplaceAllStringFunc(tag, func(placeholder string) string {
+			value, err := v.paramPathValue(tagParamExpr.FindStringSubmatch(placeholder)[1], selector)
+			if err != nil {
+				expandErr = err
+				return ""
+			}
+
+			return toolbox.AsString(value)
+		})
+
+		if expandErr != nil {
+			return nil, fmt.Errorf("failed to expand view %v cache tag %v: %w", v.Name, tag, expandErr)
+		}
+
+		result = append(result, expanded)
+	}
+
+	return result, nil
+}
+
+func (v *View) paramPathValue(aPath string, selector *Selector) (interface{}, error) {
+	if v.Template == nil {
+		return nil, fmt.Errorf("view %v template was empty", v.Name)
+	}
+
+	segments := strings.Split(aPath, ".")
+	param, err := v.Template._parametersIndex.Lookup(segments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if selector != nil && selector.Parameters.Values != nil {
+		if value, err = param.Value(selector.Parameters.Values); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, segment := range segments[1:] {
+		if value, err = fieldValue(value, segment); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
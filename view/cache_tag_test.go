@@ -0,0 +1,48 @@
+package view
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestView_CacheTags(t *testing.T) {
+	useCases := []struct {
+		description string
+		view        *View
+		purge       []string
+		expectTags  []string
+		expectGen   string
+	}{
+		{
+			description: "view and table tags",
+			view:        &View{Name: "events", Table: "events", Template: &Template{Source: "SELECT * FROM EVENTS e JOIN EVENT_TYPES t ON e.TYPE_ID = t.ID"}},
+			expectTags:  []string{"view:events", "table:EVENTS", "table:EVENT_TYPES"},
+		},
+		{
+			description: "purged table tag",
+			view:        &View{Name: "events", Template: &Template{Source: "SELECT * FROM EVENTS"}},
+			purge:       []string{"table:EVENTS", "table:EVENTS", "view:accounts"},
+			expectTags:  []string{"view:events", "table:EVENTS"},
+			expectGen:   "table:EVENTS=2",
+		},
+		{
+			description: "custom tags without parameters",
+			view:        &View{Name: "events", Template: &Template{Source: "SELECT * FROM EVENTS"}, Cache: &Cache{Tags: []string{"events-feed"}}},
+			purge:       []string{"events-feed"},
+			expectTags:  []string{"view:events", "table:EVENTS", "events-feed"},
+			expectGen:   "events-feed=1",
+		},
+	}
+
+	for _, useCase := range useCases {
+		CacheTags = NewTagGenerations()
+		assert.Nil(t, CacheTags.Purge(context.Background(), useCase.purge...), useCase.description)
+		tags, err := useCase.view.CacheTags(nil)
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.expectTags, tags, useCase.description)
+		assert.Equal(t, useCase.expectGen, CacheTags.Generation(tags), useCase.description)
+	}
+
+	CacheTags = NewTagGenerations()
+}
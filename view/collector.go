@@ -94,6 +94,11 @@ func (r *Collector) Resolve(column io.Column) func(ptr unsafe.Pointer) interface
 	}
 }
 
+//HasResolved returns true if collector resolved unmapped columns
+func (r *Collector) HasResolved() bool {
+	return len(r.types) > 0
+}
+
 //parentValuesPositions returns positions in the parent main slice by given column name
 //After first use, it is not possible to index new resolved column indexes by Resolve method
 func (r *Collector) parentValuesPositions(columnName string) map[interface{}][]int {
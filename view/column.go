@@ -28,6 +28,7 @@ type Column struct {
 	Codec          *Codec `json:",omitempty"`
 	DatabaseColumn string `json:",omitempty"`
 	IndexedBy      string `json:",omitempty"`
+	Mask           *Mask  `json:",omitempty"`
 
 	rType         reflect.Type
 	tag           *io.Tag
@@ -124,6 +125,12 @@ func (c *Column) Init(resource *Resource, caser format.Case, allowNulls bool, co
 		}
 	}
 
+	if c.Mask != nil {
+		if err := c.Mask.Init(c); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -188,6 +195,10 @@ func (c *Column) inherit(config *ColumnConfig) {
 	if config.Format != nil {
 		c.Format = *config.Format
 	}
+
+	if config.Mask != nil {
+		c.Mask = config.Mask
+	}
 }
 
 //Columns wrap slice of Column
@@ -313,4 +324,5 @@ type ColumnConfig struct {
 	Codec      *Codec  `json:",omitempty"`
 	DataType   *string `json:",omitempty"`
 	Format     *string `json:",omitempty"`
+	Mask       *Mask   `json:",omitempty"`
 }
@@ -22,6 +22,7 @@ type (
 		_dsn string
 		//TODO add secure password storage
 		db          func() (*sql.DB, error)
+		_dbKey      string
 		initialized bool
 		*DBConfig
 		mux sync.Mutex
@@ -99,13 +100,29 @@ func (c *Connector) DB() (*sql.DB, error) {
 	}
 
 	c.mux.Lock()
-	c.db = aDbPool.DB(c.Driver, dsn, c.DBConfig)
+	c._dbKey, c.db = aDbPool.keyedDB(c.Driver, dsn, c.DBConfig)
 	aDB, err := c.db()
 	c.mux.Unlock()
 
 	return aDB, err
 }
 
+func (c *Connector) dbKey() string {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c._dbKey
+}
+
+//Ping verifies connection to the database
+func (c *Connector) Ping(ctx context.Context) error {
+	db, err := c.DB()
+	if err != nil {
+		return err
+	}
+
+	return db.PingContext(ctx)
+}
+
 //Validate check if connector was configured properly.
 //Name, Driver and DSN are required.
 func (c *Connector) Validate() error {
@@ -136,6 +153,7 @@ func (c *Connector) inherit(connector *Connector) {
 
 	if c.db == nil {
 		c.db = connector.db
+		c._dbKey = connector._dbKey
 	}
 
 	if c.Name == "" {
@@ -20,6 +20,8 @@ const (
 	AndCriteria           = "$AND_CRITERIA"
 	OrCriteria            = "$OR_CRITERIA"
 
+	RowFilter = "$ROW_FILTER"
+
 	WherePrefix = "WHERE_"
 	AndPrefix   = "AND_"
 	OrPrefix    = "OR_"
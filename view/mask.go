@@ -0,0 +1,162 @@
+package view
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/viant/scy"
+	"reflect"
+	"strings"
+)
+
+const (
+	//MaskDrop removes column from the response
+	MaskDrop = "drop"
+	//MaskNull replaces column value with null (zero value for not nullable types)
+	MaskNull = "null"
+	//MaskHash replaces column value with its HMAC-SHA256 keyed with the mask Secret
+	MaskHash = "hash"
+	//MaskPartial replaces all but last Visible characters with Char
+	MaskPartial = "partial"
+
+	defaultMaskVisible = 4
+	defaultMaskChar    = "*"
+)
+
+//Mask represents column masking policy, callers fulfilling Roles, Scopes and Claims see actual column value
+type Mask struct {
+	Strategy string        `json:",omitempty"`
+	Visible  int           `json:",omitempty"`
+	Char     string        `json:",omitempty"`
+	Roles    []string      `json:",omitempty"`
+	Scopes   []string      `json:",omitempty"`
+	Claims   []string      `json:",omitempty"`
+	Secret   *scy.Resource `json:",omitempty"` //hash strategy key, unkeyed hashes of low entropy values can be reversed
+
+	_key []byte
+}
+
+//Init initializes Mask
+func (m *Mask) Init(column *Column) error {
+	if m.Strategy == "" {
+		m.Strategy = MaskNull
+	}
+
+	m.Strategy = strings.ToLower(m.Strategy)
+	switch m.Strategy {
+	case MaskDrop, MaskNull:
+	case MaskHash:
+		if err := m.initKey(column); err != nil {
+			return err
+		}
+	case MaskPartial:
+		if m.Visible == 0 {
+			m.Visible = defaultMaskVisible
+		}
+
+		if m.Char == "" {
+			m.Char = defaultMaskChar
+		}
+	default:
+		return fmt.Errorf("unsupported column %v mask strategy %v", column.Name, m.Strategy)
+	}
+
+	if m.Visible < 0 {
+		return fmt.Errorf("column %v mask visible characters can't be negative", column.Name)
+	}
+
+	return nil
+}
+
+func (m *Mask) initKey(column *Column) error {
+	if m._key != nil {
+		return nil
+	}
+
+	if m.Secret == nil {
+		return fmt.Errorf("column %v hash mask secret was empty", column.Name)
+	}
+
+	secret, err := scy.New().Load(context.Background(), m.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to load column %v hash mask secret: %w", column.Name, err)
+	}
+
+	m._key = []byte(strings.TrimSpace(secret.String()))
+	if len(m._key) == 0 {
+		return fmt.Errorf("column %v hash mask secret was empty", column.Name)
+	}
+
+	return nil
+}
+
+//Description returns mask description used by the API documentation
+func (m *Mask) Description() string {
+	description := "masked (" + m.Strategy + ")"
+	var requirements []string
+	if len(m.Roles) > 0 {
+		requirements = append(requirements, "roles: any of "+strings.Join(m.Roles, ", "))
+	}
+
+	if len(m.Scopes) > 0 {
+		requirements = append(requirements, "scopes: all of "+strings.Join(m.Scopes, ", "))
+	}
+
+	if len(m.Claims) > 0 {
+		requirements = append(requirements, "claims: "+strings.Join(m.Claims, " && "))
+	}
+
+	if len(requirements) == 0 {
+		return description
+	}
+
+	return description + " unless caller has " + strings.Join(requirements, "; ")
+}
+
+//Apply masks struct field value
+func (m *Mask) Apply(value reflect.Value) {
+	if !value.CanSet() {
+		return
+	}
+
+	switch m.Strategy {
+	case MaskHash, MaskPartial:
+		actual := value
+		if actual.Kind() == reflect.Ptr {
+			if actual.IsNil() {
+				return
+			}
+			actual = actual.Elem()
+		}
+
+		if actual.Kind() == reflect.String {
+			actual.SetString(m.maskString(actual.String()))
+			return
+		}
+	}
+
+	value.Set(reflect.Zero(value.Type()))
+}
+
+func (m *Mask) maskString(value string) string {
+	if m.Strategy == MaskHash {
+		hash := hmac.New(sha256.New, m._key)
+		hash.Write([]byte(value))
+		return hex.EncodeToString(hash.Sum(nil))
+	}
+
+	runes := []rune(value)
+	visible := m.Visible
+	if visible >= len(runes) {
+		visible = 0
+	}
+
+	return strings.Repeat(m.Char, len(runes)-visible) + string(runes[len(runes)-visible:])
+}
+
+//Field returns column struct field
+func (c *Column) Field() *reflect.StructField {
+	return c.field
+}
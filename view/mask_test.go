@@ -0,0 +1,82 @@
+package view
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestMask_Apply(t *testing.T) {
+	type record struct {
+		Email *string
+		Phone string
+		SSN   string
+		ID    int
+	}
+
+	email := "abc@example.com"
+	useCases := []struct {
+		description string
+		mask        *Mask
+		field       string
+		expect      interface{}
+		expectErr   bool
+	}{
+		{
+			description: "partial mask",
+			mask:        &Mask{Strategy: MaskPartial},
+			field:       "Phone",
+			expect:      "******1234",
+		},
+		{
+			description: "partial mask hides short values",
+			mask:        &Mask{Strategy: MaskPartial, Visible: 4},
+			field:       "SSN",
+			expect:      "***",
+		},
+		{
+			description: "null mask",
+			mask:        &Mask{Strategy: MaskNull},
+			field:       "Email",
+			expect:      (*string)(nil),
+		},
+		{
+			description: "hash mask",
+			mask:        &Mask{Strategy: MaskHash, _key: []byte("secret")},
+			field:       "Phone",
+			expect:      "5c2447b09e51ce6cfce49c9c6357c4abc2ed5866b2adfb68ae9a59de141c8dd2",
+		},
+		{
+			description: "hash mask without secret",
+			mask:        &Mask{Strategy: MaskHash},
+			field:       "Phone",
+			expectErr:   true,
+		},
+		{
+			description: "hash mask of non string value",
+			mask:        &Mask{Strategy: MaskHash, _key: []byte("secret")},
+			field:       "ID",
+			expect:      0,
+		},
+		{
+			description: "drop mask",
+			mask:        &Mask{Strategy: MaskDrop},
+			field:       "Phone",
+			expect:      "",
+		},
+	}
+
+	for _, useCase := range useCases {
+		err := useCase.mask.Init(&Column{Name: useCase.field})
+		if useCase.expectErr {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+
+		assert.Nil(t, err, useCase.description)
+		aRecord := &record{Email: &email, Phone: "5551231234", SSN: "123", ID: 10}
+		value := reflect.ValueOf(aRecord).Elem().FieldByName(useCase.field)
+		useCase.mask.Apply(value)
+		assert.Equal(t, useCase.expect, value.Interface(), useCase.description)
+	}
+}
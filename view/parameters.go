@@ -578,6 +578,16 @@ func (p ParametersSlice) Filter(kind Kind) ParametersIndex {
 	return result
 }
 
+func (p ParametersSlice) byName(name string) *Parameter {
+	for _, parameter := range p {
+		if FirstNotEmpty(parameter.Name, parameter.Ref) == name {
+			return parameter
+		}
+	}
+
+	return nil
+}
+
 func (p ParametersIndex) merge(with ParametersIndex) {
 	for s := range with {
 		p[s] = with[s]
@@ -18,8 +18,9 @@ var (
 
 type (
 	dbRegistry struct {
-		index map[string]*db
-		mutex sync.Mutex
+		index  map[string]*db
+		owners map[string]map[interface{}]bool
+		mutex  sync.Mutex
 	}
 
 	db struct {
@@ -28,6 +29,10 @@ type (
 		ctx         context.Context
 		cancelFunc  context.CancelFunc
 		initialized bool
+		released    bool
+		driver      string
+		dsn         string
+		config      *DBConfig
 	}
 
 	aerospikeClientRegistry struct {
@@ -161,6 +166,13 @@ func (d *db) initDatabase(driver string, dsn string, config *DBConfig) error {
 
 func (d *db) connect() (*sql.DB, error) {
 	d.mutex.Lock()
+	if d.released {
+		d.released = false
+		if err := d.initDatabase(d.driver, d.dsn, d.config); err != nil {
+			fmt.Printf("error occured while initializing db %v\n", err.Error())
+		}
+		d.keepConnectionAlive(d.driver, d.dsn, d.config)
+	}
 	aDb := d.actual
 	d.mutex.Unlock()
 
@@ -239,6 +251,27 @@ func (d *db) keepConnectionAlive(driver string, dsn string, config *DBConfig) {
 	}(driver, dsn, config)
 }
 
+func (d *db) release() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.released || !d.initialized {
+		return
+	}
+
+	if d.cancelFunc != nil {
+		d.cancelFunc()
+		d.cancelFunc = nil
+	}
+
+	if d.actual != nil {
+		_ = d.actual.Close()
+		d.actual = nil
+	}
+
+	d.initialized = false
+	d.released = true
+}
+
 func (d *db) ctxWithTimeout(duration time.Duration) (context.Context, context.CancelFunc) {
 	background := context.Background()
 	ctxWithTimeout, cancelFn := context.WithTimeout(background, duration)
@@ -246,6 +279,11 @@ func (d *db) ctxWithTimeout(duration time.Duration) (context.Context, context.Ca
 }
 
 func (p *dbRegistry) DB(driver, dsn string, config *DBConfig) func() (*sql.DB, error) {
+	_, connect := p.keyedDB(driver, dsn, config)
+	return connect
+}
+
+func (p *dbRegistry) keyedDB(driver, dsn string, config *DBConfig) (string, func() (*sql.DB, error)) {
 	builder := &strings.Builder{}
 
 	if config == nil {
@@ -267,14 +305,70 @@ func (p *dbRegistry) DB(driver, dsn string, config *DBConfig) func() (*sql.DB, e
 	actualKey := builder.String()
 	dbConn := p.getItem(actualKey, driver, dsn, config)
 
-	return dbConn.connect
+	return actualKey, dbConn.connect
+}
+
+func (p *dbRegistry) retain(owner interface{}, keys []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, key := range keys {
+		if p.owners[key] == nil {
+			p.owners[key] = map[interface{}]bool{}
+		}
+
+		p.owners[key][owner] = true
+	}
+}
+
+func (p *dbRegistry) release(owner interface{}) {
+	p.mutex.Lock()
+	var unused []*db
+	for key, owners := range p.owners {
+		if !owners[owner] {
+			continue
+		}
+
+		delete(owners, owner)
+		if len(owners) > 0 {
+			continue
+		}
+
+		delete(p.owners, key)
+		if item, ok := p.index[key]; ok {
+			unused = append(unused, item)
+		}
+	}
+	p.mutex.Unlock()
+
+	for _, item := range unused {
+		item.release()
+	}
+}
+
+//RetainDBs marks pooled database connections of the given connectors as used by the owner.
+//Only connections that were already opened by the connectors are retained.
+func RetainDBs(owner interface{}, connectors []*Connector) {
+	var keys []string
+	for _, connector := range connectors {
+		if key := connector.dbKey(); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	aDbPool.retain(owner, keys)
+}
+
+//ReleaseDBs removes owner from pooled database connections it retained and closes the ones no longer retained by any owner.
+//Connections that were never retained are not closed, released connections are reopened if requested again.
+func ReleaseDBs(owner interface{}) {
+	aDbPool.release(owner)
 }
 
 func (p *dbRegistry) getItem(key string, driver string, dsn string, config *DBConfig) *db {
 	p.mutex.Lock()
 	item, ok := p.index[key]
 	if !ok {
-		item = &db{}
+		item = &db{driver: driver, dsn: dsn, config: config}
 		err := item.initWithLock(driver, dsn, config)
 		if err != nil {
 			fmt.Printf("error occured while initializing db %v\n", err.Error())
@@ -307,7 +401,7 @@ func ResetAerospikePool() {
 }
 
 func newPool() *dbRegistry {
-	return &dbRegistry{index: map[string]*db{}}
+	return &dbRegistry{index: map[string]*db{}, owners: map[string]map[interface{}]bool{}}
 }
 
 func (a *aerospikeClientRegistry) Client(host string, port int) func() (*as.Client, error) {
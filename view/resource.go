@@ -19,8 +19,9 @@ import (
 //Resource represents grouped view needed to build the View
 //can be loaded from i.e. yaml file
 type Resource struct {
-	Metrics   *Metrics
-	SourceURL string `json:",omitempty"`
+	Metrics     *Metrics
+	SlowQueries *logger.SlowQueryLog `json:"-" yaml:"-"`
+	SourceURL   string               `json:",omitempty"`
 
 	CacheProviders []*Cache
 	_cacheIndex    map[string]int
@@ -0,0 +1,286 @@
+package view
+
+import (
+	"fmt"
+	"github.com/viant/datly/view/keywords"
+	"reflect"
+	"strings"
+)
+
+type (
+	//RowFilter represents mandatory row-level security predicate, applied to every view read
+	RowFilter struct {
+		Column string `json:",omitempty"`
+		Param  string `json:",omitempty"` //parameter name with optional value path i.e. Jwt.AccountId
+
+		_column *Column
+		_param  *Parameter
+		_path   []string
+	}
+
+	//RowFilters represents view row filters
+	RowFilters []*RowFilter
+)
+
+func (v *View) initRowFilters() error {
+	for _, filter := range v.RowFilters {
+		if err := filter.init(v); err != nil {
+			return fmt.Errorf("invalid view %v row filter: %w", v.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (f *RowFilter) init(aView *View) error {
+	if f.Column == "" {
+		return fmt.Errorf("row filter column was empty")
+	}
+
+	if f.Param == "" {
+		return fmt.Errorf("row filter %v param was empty", f.Column)
+	}
+
+	column, ok := aView.ColumnByName(f.Column)
+	if !ok {
+		return fmt.Errorf("not found row filter column %v", f.Column)
+	}
+	f._column = column
+
+	segments := strings.Split(f.Param, ".")
+	param, err := aView.Template._parametersIndex.Lookup(segments[0])
+	if err != nil {
+		return err
+	}
+
+	f._param = param
+	f._path = segments[1:]
+	return nil
+}
+
+//inheritRowFilters adds parent row filters to the relation view, relation views can't expose rows the parent filters out.
+//Parameters used by inherited filters are inherited as well unless relation view template defines its own schema.
+func (v *View) inheritRowFilters(parent *View) {
+	if len(parent.RowFilters) == 0 {
+		return
+	}
+
+	filters := append(RowFilters{}, v.RowFilters...)
+	for _, filter := range parent.RowFilters {
+		if filters.has(filter.Column) {
+			continue
+		}
+
+		filters = append(filters, filter.copy())
+		v.inheritRowFilterParam(parent, filter)
+	}
+
+	v.RowFilters = filters
+}
+
+func (v *View) inheritRowFilterParam(parent *View, filter *RowFilter) {
+	if parent.Template == nil {
+		return
+	}
+
+	name := strings.Split(filter.Param, ".")[0]
+	param := ParametersSlice(parent.Template.Parameters).byName(name)
+	if param == nil {
+		return
+	}
+
+	if v.Template == nil {
+		v.Template = &Template{}
+	}
+
+	if schema := v.Template.Schema; schema != nil && (schema.Name != "" || schema.Type() != nil) {
+		return
+	}
+
+	if ParametersSlice(v.Template.Parameters).byName(name) != nil {
+		return
+	}
+
+	inherited := &Parameter{
+		Reference:         param.Reference,
+		Name:              param.Name,
+		PresenceName:      param.PresenceName,
+		In:                param.In,
+		Required:          param.Required,
+		Description:       param.Description,
+		DataType:          param.DataType,
+		Style:             param.Style,
+		MaxAllowedRecords: param.MaxAllowedRecords,
+		Codec:             param.Codec,
+		Output:            param.Output,
+		Const:             param.Const,
+		DateFormat:        param.DateFormat,
+		ErrorStatusCode:   param.ErrorStatusCode,
+	}
+
+	if param.Schema != nil {
+		inherited.Schema = param.Schema.copy()
+	}
+
+	v.Template.Parameters = append(append([]*Parameter{}, v.Template.Parameters...), inherited)
+}
+
+//copy returns uninitialized filters copy, initialized filters are bound to the view columns and parameters
+func (r RowFilters) copy() RowFilters {
+	if r == nil {
+		return nil
+	}
+
+	result := make(RowFilters, 0, len(r))
+	for _, filter := range r {
+		result = append(result, filter.copy())
+	}
+
+	return result
+}
+
+func (f *RowFilter) copy() *RowFilter {
+	return &RowFilter{Column: f.Column, Param: f.Param}
+}
+
+func (r RowFilters) has(column string) bool {
+	for _, filter := range r {
+		if strings.EqualFold(filter.Column, column) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//active returns true if view has initialized row filters
+func (r RowFilters) active() bool {
+	for _, filter := range r {
+		if filter._column == nil {
+			return false
+		}
+	}
+
+	return len(r) > 0
+}
+
+//rowFiltered wraps view source with row filters, so they can't be bypassed by criteria, columns projection or template overrides
+func (v *View) rowFiltered(source string) string {
+	if v == nil || !v.RowFilters.active() {
+		return source
+	}
+
+	alias := v.RowFilterAlias()
+	return "(SELECT * FROM " + source + " AS " + alias + " WHERE " + keywords.RowFilter + ")"
+}
+
+//RowFilterAlias returns alias used by the row filters query wrapper
+func (v *View) RowFilterAlias() string {
+	if v.Alias != "" {
+		return v.Alias
+	}
+
+	return "t"
+}
+
+//Value returns row filter value bound to the selector parameters, row filter without value denies access
+func (f *RowFilter) Value(selector *Selector) (interface{}, error) {
+	var value interface{}
+	var err error
+	if selector != nil && selector.Parameters.Values != nil {
+		if value, err = f._param.Value(selector.Parameters.Values); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, segment := range f._path {
+		if value, err = fieldValue(value, segment); err != nil {
+			return nil, fmt.Errorf("row filter %v: %w", f.Param, err)
+		}
+	}
+
+	if isEmptyValue(value) {
+		return nil, fmt.Errorf("row filter %v value was empty", f.Param)
+	}
+
+	return value, nil
+}
+
+//Criteria returns row filters SQL criteria with placeholders values
+func (r RowFilters) Criteria(alias string, selector *Selector) (string, []interface{}, error) {
+	sb := strings.Builder{}
+	placeholders := make([]interface{}, 0, len(r))
+	for i, filter := range r {
+		value, err := filter.Value(selector)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if i != 0 {
+			sb.WriteString(" AND ")
+		}
+
+		if alias != "" {
+			sb.WriteString(alias)
+			sb.WriteString(".")
+		}
+
+		sb.WriteString(filter._column.Name)
+		sb.WriteString(" = ?")
+		placeholders = append(placeholders, value)
+	}
+
+	return sb.String(), placeholders, nil
+}
+
+func fieldValue(value interface{}, name string) (interface{}, error) {
+	rValue := reflect.ValueOf(value)
+	for rValue.Kind() == reflect.Ptr || rValue.Kind() == reflect.Interface {
+		if rValue.IsNil() {
+			return nil, nil
+		}
+		rValue = rValue.Elem()
+	}
+
+	switch rValue.Kind() {
+	case reflect.Struct:
+		field := rValue.FieldByNameFunc(func(fieldName string) bool {
+			return strings.EqualFold(fieldName, name)
+		})
+
+		if !field.IsValid() {
+			return nil, fmt.Errorf("not found field %v at %v", name, rValue.Type().String())
+		}
+
+		return field.Interface(), nil
+	case reflect.Map:
+		if rValue.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unable to read %v from %v", name, rValue.Type().String())
+		}
+
+		field := rValue.MapIndex(reflect.ValueOf(name))
+		if !field.IsValid() {
+			return nil, nil
+		}
+
+		return field.Interface(), nil
+	case reflect.Invalid:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unable to read %v from %v", name, rValue.Type().String())
+}
+
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	rValue := reflect.ValueOf(value)
+	switch rValue.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return rValue.IsNil()
+	}
+
+	return rValue.IsZero()
+}
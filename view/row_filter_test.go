@@ -0,0 +1,72 @@
+package view
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/shared"
+	"os"
+	"testing"
+)
+
+func TestView_RowFiltersRef(t *testing.T) {
+	dbLocation := "/tmp/datly_row_filter_test.db"
+	_ = os.Remove(dbLocation)
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE EVENTS (ID INTEGER PRIMARY KEY, USER_ID INTEGER, QUANTITY REAL)")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	userID := func() *Parameter {
+		return &Parameter{Name: "UserID", In: &Location{Kind: KindHeader, Name: "User-Id"}, Schema: &Schema{DataType: "int"}}
+	}
+	lang := &Parameter{Name: "Lang", In: &Location{Kind: KindHeader, Name: "Accept-Language"}, Schema: &Schema{DataType: "string"}}
+
+	connector := &Connector{Name: "db", Driver: "sqlite3", DSN: dbLocation}
+	resource := EmptyResource()
+	resource.Connectors = []*Connector{connector}
+	resource.AddViews(
+		&View{Name: "events", Connector: connector, Table: "EVENTS", RowFilters: RowFilters{{Column: "USER_ID", Param: "UserID"}},
+			Template: &Template{Source: "SELECT * FROM EVENTS", Parameters: []*Parameter{userID()}}},
+		&View{Name: "user_events", Reference: shared.Reference{Ref: "events"}},
+		&View{Name: "lang_events", Reference: shared.Reference{Ref: "events"}, Template: &Template{Source: "SELECT * FROM EVENTS", Parameters: []*Parameter{lang, userID()}}},
+	)
+
+	if !assert.Nil(t, resource.Init(context.Background())) {
+		return
+	}
+
+	useCases := []struct {
+		description string
+		view        string
+	}{
+		{description: "referenced view", view: "events"},
+		{description: "view inheriting template", view: "user_events"},
+		{description: "view with own template", view: "lang_events"},
+	}
+
+	filters := map[*RowFilter]bool{}
+	for _, useCase := range useCases {
+		aView, err := resource.View(useCase.view)
+		if !assert.Nil(t, err, useCase.description) || !assert.Len(t, aView.RowFilters, 1, useCase.description) {
+			continue
+		}
+
+		filter := aView.RowFilters[0]
+		assert.False(t, filters[filter], useCase.description)
+		filters[filter] = true
+
+		param, err := aView.Template._parametersIndex.Lookup("UserID")
+		assert.Nil(t, err, useCase.description)
+		assert.True(t, filter._param == param, useCase.description)
+		column, _ := aView.ColumnByName("USER_ID")
+		assert.True(t, filter._column == column, useCase.description)
+	}
+}
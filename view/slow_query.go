@@ -0,0 +1,152 @@
+package view
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/viant/datly/logger"
+	"strings"
+	"time"
+)
+
+const explainTimeout = 5 * time.Second
+
+type (
+	//SlowQuery configures slow query capturing for the View
+	SlowQuery struct {
+		ThresholdMs int
+		Explain     bool `json:",omitempty"`
+		RevealArgs  bool `json:",omitempty"`
+	}
+
+	//Queryer represents database the slow query was run with
+	Queryer interface {
+		QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	}
+)
+
+//Threshold returns slow query threshold
+func (s *SlowQuery) Threshold() time.Duration {
+	return time.Duration(s.ThresholdMs) * time.Millisecond
+}
+
+//LogSlowQueryIfNeeded records query in the Resource slow query log and emits it through the View Logger if elapsed time exceeded View SlowQuery threshold,
+//EXPLAIN runs in the background with the queryer database, query is recorded once the plan is captured, nil queryer (i.e. transaction statements) skips EXPLAIN
+func (v *View) LogSlowQueryIfNeeded(ctx context.Context, queryer Queryer, mode Mode, SQL string, args []interface{}, rows int, elapsed time.Duration, err error) {
+	config := v.SlowQuery
+	if config == nil || elapsed < config.Threshold() {
+		return
+	}
+
+	query := &logger.SlowQuery{
+		View:      v.Name,
+		Mode:      string(mode),
+		SQL:       SQL,
+		Args:      maskArgs(args, config.RevealArgs),
+		Rows:      rows,
+		Elapsed:   elapsed.String(),
+		ElapsedMs: int(elapsed.Milliseconds()),
+		Time:      time.Now(),
+	}
+
+	if err != nil {
+		query.Error = err.Error()
+	}
+
+	if explainSQL, ok := explainStatement(v.Connector, SQL); ok && config.Explain && queryer != nil {
+		go func() {
+			var planErr error
+			if query.Plan, planErr = explain(context.Background(), queryer, explainSQL, args); planErr != nil {
+				query.PlanError = planErr.Error()
+			}
+			v.logSlowQuery(query)
+		}()
+		return
+	}
+
+	v.logSlowQuery(query)
+}
+
+func (v *View) logSlowQuery(query *logger.SlowQuery) {
+	if v._slowQueries != nil {
+		v._slowQueries.Add(query)
+	}
+
+	if v.Logger != nil {
+		v.Logger.SlowQuery(query)
+	}
+}
+
+func explain(ctx context.Context, queryer Queryer, SQL string, args []interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, explainTimeout)
+	defer cancel()
+
+	rows, err := queryer.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		record := map[string]interface{}{}
+		for i, column := range columns {
+			if asBytes, ok := values[i].([]byte); ok {
+				values[i] = string(asBytes)
+			}
+
+			record[column] = values[i]
+		}
+
+		result = append(result, record)
+	}
+
+	return result, rows.Err()
+}
+
+func explainStatement(connector *Connector, SQL string) (string, bool) {
+	if connector == nil {
+		return "", false
+	}
+
+	switch strings.ToLower(connector.Driver) {
+	case "mysql", "postgres", "pgx":
+		return "EXPLAIN " + SQL, true
+	case "sqlite3", "sqlite":
+		return "EXPLAIN QUERY PLAN " + SQL, true
+	}
+
+	return "", false
+}
+
+func maskArgs(args []interface{}, reveal bool) []interface{} {
+	if reveal || len(args) == 0 {
+		return args
+	}
+
+	result := make([]interface{}, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			continue
+		}
+
+		result[i] = fmt.Sprintf("%T(***)", arg)
+	}
+
+	return result
+}
@@ -0,0 +1,84 @@
+package view
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/logger"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestView_LogSlowQueryIfNeeded(t *testing.T) {
+	dbLocation := "/tmp/datly_slow_query_test.db"
+	_ = os.Remove(dbLocation)
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE events (id INTEGER PRIMARY KEY)")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	useCases := []struct {
+		description string
+		config      *SlowQuery
+		queryer     Queryer
+		elapsed     time.Duration
+		expectLog   bool
+		expectPlan  bool
+	}{
+		{
+			description: "below threshold",
+			config:      &SlowQuery{ThresholdMs: 100, Explain: true},
+			queryer:     db,
+			elapsed:     time.Millisecond,
+		},
+		{
+			description: "slow query without explain",
+			config:      &SlowQuery{ThresholdMs: 100},
+			queryer:     db,
+			elapsed:     time.Second,
+			expectLog:   true,
+		},
+		{
+			description: "explain runs in background",
+			config:      &SlowQuery{ThresholdMs: 100, Explain: true},
+			queryer:     db,
+			elapsed:     time.Second,
+			expectLog:   true,
+			expectPlan:  true,
+		},
+		{
+			description: "explain skipped without queryer",
+			config:      &SlowQuery{ThresholdMs: 100, Explain: true},
+			elapsed:     time.Second,
+			expectLog:   true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		slowQueries := logger.NewSlowQueryLog(10)
+		aView := &View{Name: "events", SlowQuery: useCase.config, Connector: &Connector{Driver: "sqlite3"}, _slowQueries: slowQueries}
+		aView.LogSlowQueryIfNeeded(context.Background(), useCase.queryer, SQLQueryMode, "SELECT * FROM events WHERE id = ?", []interface{}{1}, 0, useCase.elapsed, nil)
+
+		if !useCase.expectLog {
+			assert.Empty(t, slowQueries.Entries(), useCase.description)
+			continue
+		}
+
+		if !assert.Eventually(t, func() bool { return len(slowQueries.Entries()) == 1 }, time.Second, time.Millisecond, useCase.description) {
+			continue
+		}
+
+		entries := slowQueries.Entries()
+		assert.Equal(t, []interface{}{"int(***)"}, entries[0].Args, useCase.description)
+		assert.Equal(t, "", entries[0].PlanError, useCase.description)
+		assert.Equal(t, useCase.expectPlan, len(entries[0].Plan) > 0, useCase.description)
+	}
+}
@@ -235,7 +235,7 @@ func FilterConstParameters(parameters []*Parameter) []expand.ConstUpdater {
 
 func (t *Template) EvaluateSource(externalParams, presenceMap interface{}, parentParam *expand.MetaParam, batchData *BatchData, options ...interface{}) (string, *expand.SQLCriteria, *logger.Printer, error) {
 	if t.wasEmpty {
-		return t.Source, &expand.SQLCriteria{}, &logger.Printer{}, nil
+		return t._view.rowFiltered(t.Source), &expand.SQLCriteria{}, &logger.Printer{}, nil
 	}
 
 	state, criteria, printer, err := t.EvaluateState(externalParams, presenceMap, parentParam, batchData, options...)
@@ -243,7 +243,7 @@ func (t *Template) EvaluateSource(externalParams, presenceMap interface{}, paren
 		return "", criteria, printer, err
 	}
 
-	return state.Buffer.String(), criteria, printer, err
+	return t._view.rowFiltered(state.Buffer.String()), criteria, printer, err
 }
 
 func (t *Template) EvaluateState(externalParams interface{}, presenceMap interface{}, parentParam *expand.MetaParam, batchData *BatchData, options ...interface{}) (*est.State, *expand.SQLCriteria, *logger.Printer, error) {
@@ -454,6 +454,22 @@ func (t *Template) replacementEntry(key string, params CriteriaParam, selector *
 	case keywords.SelectorCriteria[1:]:
 		*placeholders = append(*placeholders, selector.Placeholders...)
 		return key, selector.Criteria, nil
+	case keywords.RowFilter[1:]:
+		if len(t._view.RowFilters) == 0 {
+			return "", "", fmt.Errorf("view %v uses %v but has no row filters", t._view.Name, keywords.RowFilter)
+		}
+
+		if !t._view.RowFilters.active() { //columns detection runs before row filters are initialized
+			return key, "1 = 1", nil
+		}
+
+		criteria, values, err := t._view.RowFilters.Criteria(t._view.RowFilterAlias(), selector)
+		if err != nil {
+			return "", "", err
+		}
+
+		*placeholders = append(*placeholders, values...)
+		return key, criteria, nil
 	default:
 		if strings.HasPrefix(key, keywords.WherePrefix) {
 			_, aValue, err := t.replacementEntry(key[len(keywords.WherePrefix):], params, selector, batchData, placeholders, sanitized)
@@ -58,12 +58,15 @@ type (
 		MatchStrategy MatchStrategy `json:",omitempty"`
 		Batch         *Batch        `json:",omitempty"`
 
-		Logger  *logger.Adapter `json:",omitempty"`
-		Counter logger.Counter  `json:"-"`
-		Caser   format.Case     `json:",omitempty"`
+		Logger     *logger.Adapter `json:",omitempty"`
+		Counter    logger.Counter  `json:"-"`
+		Caser      format.Case     `json:",omitempty"`
+		SlowQuery  *SlowQuery      `json:",omitempty"`
+		RowFilters RowFilters      `json:",omitempty"`
 
-		_columns  ColumnIndex
-		_excluded map[string]bool
+		_columns     ColumnIndex
+		_excluded    map[string]bool
+		_slowQueries *logger.SlowQueryLog
 
 		DiscoverCriteria *bool  `json:",omitempty"`
 		AllowNulls       *bool  `json:",omitempty"`
@@ -246,6 +249,8 @@ func (v *View) initViews(ctx context.Context, resource *Resource, relations []*R
 			return err
 		}
 
+		refView.inheritRowFilters(v)
+
 		if err := rel.BeforeViewInit(ctx); err != nil {
 			return err
 		}
@@ -289,6 +294,7 @@ func (v *View) initView(ctx context.Context, resource *Resource, transforms mars
 	}
 
 	v.ensureCounter(resource)
+	v._slowQueries = resource.SlowQueries
 
 	v.Alias = FirstNotEmpty(v.Alias, "t")
 	if v.From == "" {
@@ -364,6 +370,10 @@ func (v *View) initView(ctx context.Context, resource *Resource, transforms mars
 		return err
 	}
 
+	if err = v.initRowFilters(); err != nil {
+		return err
+	}
+
 	if v.Cache != nil {
 		if err = v.Cache.init(ctx, resource, v); err != nil {
 			return err
@@ -660,6 +670,14 @@ func (v *View) inherit(view *View) error {
 		v.Batch = view.Batch
 	}
 
+	if v.SlowQuery == nil {
+		v.SlowQuery = view.SlowQuery
+	}
+
+	if len(v.RowFilters) == 0 {
+		v.RowFilters = view.RowFilters.copy()
+	}
+
 	if v.AllowNulls == nil {
 		v.AllowNulls = view.AllowNulls
 	}
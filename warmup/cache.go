@@ -21,9 +21,10 @@ type (
 	}
 
 	warmupEntry struct {
-		matcher *cache.ParmetrizedQuery
-		view    *view.View
-		column  string
+		matcher   *cache.ParmetrizedQuery
+		view      *view.View
+		column    string
+		watermark *cache.ParmetrizedQuery //cache case data query
 	}
 
 	warmupEntryFn func() (*warmupEntry, error)
@@ -61,16 +62,17 @@ func (c *matchersCollector) populateCacheCases(ctx context.Context, collector ch
 }
 
 func (c *matchersCollector) populateChan(aView *view.View, aChan chan warmupEntryFn, cacheInput *view.CacheInput) {
-	c.createIndexWarmupEntry(aView, aChan, cacheInput)
+	build, err := c.builder.CacheSQL(c.view, cacheInput.Selector)
+	c.createIndexWarmupEntry(aView, aChan, cacheInput, build, err)
 
 	if !cacheInput.IndexMeta {
 		return
 	}
 
-	c.createMetaWarmupEntry(aView, aChan, cacheInput)
+	c.createMetaWarmupEntry(aView, aChan, cacheInput, build)
 }
 
-func (c *matchersCollector) createMetaWarmupEntry(aView *view.View, aChan chan warmupEntryFn, input *view.CacheInput) {
+func (c *matchersCollector) createMetaWarmupEntry(aView *view.View, aChan chan warmupEntryFn, input *view.CacheInput, watermark *cache.ParmetrizedQuery) {
 	cacheIndex, err := c.builder.CacheMetaSQL(aView, input.Selector, nil, nil, nil)
 	if err != nil {
 		aChan <- func() (*warmupEntry, error) {
@@ -81,24 +83,25 @@ func (c *matchersCollector) createMetaWarmupEntry(aView *view.View, aChan chan w
 
 	aChan <- func() (*warmupEntry, error) {
 		return &warmupEntry{
-			matcher: cacheIndex,
-			view:    aView,
-			column:  input.MetaColumn,
+			matcher:   cacheIndex,
+			view:      aView,
+			column:    input.MetaColumn,
+			watermark: watermark,
 		}, nil
 	}
 }
 
-func (c *matchersCollector) createIndexWarmupEntry(aView *view.View, aChan chan warmupEntryFn, cacheInput *view.CacheInput) {
-	build, err := c.builder.CacheSQL(c.view, cacheInput.Selector)
+func (c *matchersCollector) createIndexWarmupEntry(aView *view.View, aChan chan warmupEntryFn, cacheInput *view.CacheInput, build *cache.ParmetrizedQuery, err error) {
 	aChan <- func() (*warmupEntry, error) {
 		if err != nil {
 			return nil, err
 		}
 
 		return &warmupEntry{
-			matcher: build,
-			view:    aView,
-			column:  cacheInput.Column,
+			matcher:   build,
+			view:      aView,
+			column:    cacheInput.Column,
+			watermark: build,
 		}, err
 	}
 }
@@ -155,10 +158,21 @@ func DB(entry *warmupEntry) (*sql.DB, error) {
 }
 
 func PopulateCache(views []*view.View) (int, error) {
+	refresh, err := populateCache(views, nil)
+	return refresh.Indexed, err
+}
+
+//RefreshCache populates views cache, cases with unchanged Warmup.Watermark column value are skipped
+func RefreshCache(views []*view.View, watermarks *Watermarks) (*Refresh, error) {
+	return populateCache(views, watermarks)
+}
+
+func populateCache(views []*view.View, watermarks *Watermarks) (*Refresh, error) {
+	refresh := &Refresh{}
 	viewsWithCache := FilterCacheViews(views)
 
 	if len(viewsWithCache) == 0 {
-		return 0, nil
+		return refresh, nil
 	}
 
 	collector := make(chan warmupEntryFn)
@@ -187,7 +201,7 @@ func PopulateCache(views []*view.View) (int, error) {
 	}
 
 	if collectorSize == 0 {
-		return 0, nil
+		return refresh, nil
 	}
 
 	var errors []error
@@ -209,7 +223,15 @@ func PopulateCache(views []*view.View) (int, error) {
 
 	close(collector)
 	if err := errUtils.CombineErrors("errors while populating cache: ", errors); err != nil {
-		return 0, err
+		return refresh, err
+	}
+
+	var changed map[string]*watermark
+	if watermarks != nil {
+		var err error
+		if warmupEntries, changed, err = watermarks.changed(ctx, warmupEntries, refresh); err != nil {
+			return refresh, err
+		}
 	}
 
 	notifierErr := make(chan func() (int, error))
@@ -230,7 +252,15 @@ func PopulateCache(views []*view.View) (int, error) {
 	}
 
 	close(notifier)
-	return indexed, errUtils.CombineErrors("errors while populating cache: ", errors)
+	refresh.Indexed = indexed
+	refresh.Rebuilt = len(warmupEntries)
+	if len(errors) == 0 && watermarks != nil {
+		if err := watermarks.commit(ctx, changed); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	return refresh, errUtils.CombineErrors("errors while populating cache: ", errors)
 }
 
 func FilterCacheViews(views []*view.View) []*view.View {
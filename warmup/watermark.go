@@ -0,0 +1,148 @@
+package warmup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/datly/view"
+	"strconv"
+	"time"
+)
+
+//watermarkSQLPrefix prefixes SQL of the cache entries storing warmup case watermark
+const watermarkSQLPrefix = "/* watermark */ "
+
+type (
+	//Refresh represents cache refresh outcome
+	Refresh struct {
+		Indexed int //number of indexed entries
+		Rebuilt int //number of rebuilt warmup entries
+		Skipped int //number of warmup entries skipped due to unchanged watermark
+	}
+
+	//Watermarks tracks warmup cases watermark column values between cache refreshes, values are stored with the view cache entries,
+	//thus runners sharing cache provider share watermarks
+	Watermarks struct{}
+
+	watermark struct {
+		entry *warmupEntry
+		value string
+	}
+)
+
+//NewWatermarks creates watermarks
+func NewWatermarks() *Watermarks {
+	return &Watermarks{}
+}
+
+//changed returns entries of the cases with changed watermark or cases built more than half of cache TimeToLiveMs ago,
+//as skipped entries expire with cache TimeToLiveMs
+func (w *Watermarks) changed(ctx context.Context, entries []*warmupEntry, refresh *Refresh) ([]*warmupEntry, map[string]*watermark, error) {
+	values := map[string]*watermark{}
+	skip := map[string]bool{}
+	result := make([]*warmupEntry, 0, len(entries))
+	for _, entry := range entries {
+		column := entry.view.Cache.Warmup.Watermark
+		if column == "" || entry.watermark == nil {
+			result = append(result, entry)
+			continue
+		}
+
+		key, err := watermarkKey(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if _, ok := values[key]; !ok {
+			value, err := watermarkValue(ctx, entry, column)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			values[key] = &watermark{entry: entry, value: value}
+			if skip[key], err = w.unchanged(ctx, entry, value, time.Duration(entry.view.Cache.TimeToLiveMs)*time.Millisecond/2); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if skip[key] {
+			refresh.Skipped++
+			continue
+		}
+
+		result = append(result, entry)
+	}
+
+	for key := range skip {
+		if skip[key] {
+			delete(values, key)
+		}
+	}
+
+	return result, values, nil
+}
+
+func (w *Watermarks) unchanged(ctx context.Context, entry *warmupEntry, value string, maxAge time.Duration) (bool, error) {
+	service, err := entry.view.Cache.Service()
+	if err != nil {
+		return false, err
+	}
+
+	var previous []string
+	found, err := view.ReadCacheRecord(ctx, service, watermarkSQLPrefix+entry.watermark.SQL, entry.watermark.Args, &previous)
+	if err != nil || !found || len(previous) != 2 {
+		return false, err
+	}
+
+	builtAt, err := strconv.ParseInt(previous[0], 10, 64)
+	if err != nil {
+		return false, nil
+	}
+
+	return previous[1] == value && time.Since(time.Unix(0, builtAt)) < maxAge, nil
+}
+
+func (w *Watermarks) commit(ctx context.Context, values map[string]*watermark) error {
+	builtAt := strconv.FormatInt(time.Now().UnixNano(), 10)
+	for _, aWatermark := range values {
+		service, err := aWatermark.entry.view.Cache.Service()
+		if err != nil {
+			return err
+		}
+
+		query := aWatermark.entry.watermark
+		if err = view.WriteCacheRecord(ctx, service, watermarkSQLPrefix+query.SQL, query.Args, []interface{}{builtAt, aWatermark.value}); err != nil {
+			return fmt.Errorf("failed to store view %v watermark: %w", aWatermark.entry.view.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func watermarkKey(entry *warmupEntry) (string, error) {
+	argsMarshal, err := json.Marshal(entry.watermark.Args)
+	if err != nil {
+		return "", err
+	}
+
+	return entry.view.Name + ":" + entry.watermark.SQL + ":" + string(argsMarshal), nil
+}
+
+func watermarkValue(ctx context.Context, entry *warmupEntry, column string) (string, error) {
+	db, err := DB(entry)
+	if err != nil {
+		return "", err
+	}
+
+	SQL := "SELECT MAX(" + column + ") FROM (" + entry.watermark.SQL + ") t"
+	var value interface{}
+	if err = db.QueryRowContext(ctx, SQL, entry.watermark.Args...).Scan(&value); err != nil {
+		return "", fmt.Errorf("failed to read view %v watermark: %w, %v", entry.view.Name, err, SQL)
+	}
+
+	if data, ok := value.([]byte); ok {
+		return string(data), nil
+	}
+
+	return fmt.Sprintf("%v", value), nil
+}
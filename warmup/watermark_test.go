@@ -0,0 +1,117 @@
+package warmup
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/datly/view"
+	"testing"
+	"time"
+)
+
+func TestRefreshCache_Watermark(t *testing.T) {
+	dbLocation := "/tmp/datly_watermark_test.db"
+	db, err := sql.Open("sqlite3", dbLocation)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	for _, SQL := range []string{
+		"DROP TABLE IF EXISTS EVENTS",
+		"CREATE TABLE EVENTS (ID INTEGER PRIMARY KEY, EVENT_TYPE_ID INTEGER, USER_ID INTEGER, UPDATED INTEGER)",
+		"INSERT INTO EVENTS VALUES (1, 10, 1, 1), (2, 11, 1, 1), (3, 10, 2, 1)",
+	} {
+		if _, err = db.Exec(SQL); !assert.Nil(t, err) {
+			return
+		}
+	}
+
+	ttl := time.Second
+	aView, err := newWatermarkView(dbLocation, ttl)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	useCases := []struct {
+		description string
+		SQL         string
+		wait        time.Duration
+		expect      Refresh
+	}{
+		{
+			description: "first run builds all cases",
+			expect:      Refresh{Indexed: 8, Rebuilt: 3},
+		},
+		{
+			description: "unchanged watermark is skipped",
+			expect:      Refresh{Skipped: 3},
+		},
+		{
+			description: "changed watermark is rebuilt",
+			SQL:         "UPDATE EVENTS SET UPDATED = 2 WHERE ID = 2",
+			expect:      Refresh{Indexed: 6, Rebuilt: 2, Skipped: 1},
+		},
+		{
+			description: "unchanged watermark is rebuilt after half of time to live",
+			wait:        ttl/2 + 100*time.Millisecond,
+			expect:      Refresh{Indexed: 8, Rebuilt: 3},
+		},
+	}
+
+	watermarks := NewWatermarks()
+	for _, useCase := range useCases {
+		if useCase.SQL != "" {
+			if _, err = db.Exec(useCase.SQL); !assert.Nil(t, err, useCase.description) {
+				continue
+			}
+		}
+
+		time.Sleep(useCase.wait)
+		refresh, err := RefreshCache([]*view.View{aView}, watermarks)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+
+		assert.Equal(t, useCase.expect, *refresh, useCase.description)
+	}
+}
+
+func newWatermarkView(dbLocation string, ttl time.Duration) (*view.View, error) {
+	connector := &view.Connector{Name: "db", Driver: "sqlite3", DSN: dbLocation}
+	userID := &view.Parameter{Name: "USER_ID", In: &view.Location{Kind: view.KindQuery, Name: "user_id"}, Schema: &view.Schema{DataType: "int"}}
+	resource := view.EmptyResource()
+	resource.Connectors = []*view.Connector{connector}
+	resource.AddViews(&view.View{
+		Name:      "events",
+		Connector: connector,
+		Table:     "EVENTS",
+		Columns: []*view.Column{
+			{Name: "ID", DataType: "int"},
+			{Name: "EVENT_TYPE_ID", DataType: "int"},
+			{Name: "USER_ID", DataType: "int"},
+			{Name: "UPDATED", DataType: "int"},
+		},
+		Cache: &view.Cache{
+			Name:         "events",
+			Provider:     "mem://localhost/events",
+			TimeToLiveMs: int(ttl.Milliseconds()),
+			Warmup: &view.Warmup{
+				IndexColumn: "EVENT_TYPE_ID",
+				Watermark:   "UPDATED",
+				Cases:       []*view.CacheParameters{{Set: []*view.ParamValue{{Name: "USER_ID", Values: []interface{}{1, 2}}}}},
+			},
+		},
+		Template: &view.Template{
+			Source:     "SELECT * FROM EVENTS WHERE 0=0 #if($Has.USER_ID) AND USER_ID = $Unsafe.USER_ID #end",
+			Parameters: []*view.Parameter{userID},
+		},
+	})
+
+	if err := resource.Init(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return resource.View("events")
+}